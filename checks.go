@@ -49,6 +49,8 @@ const (
 // dynamic address which contains the instance key for any resource that has
 // multiple instances.
 type CheckStaticAddress struct {
+	unknownMembers
+
 	// ToDisplay is a formatted and ready to display representation of the
 	// address.
 	ToDisplay string `json:"to_display"`
@@ -73,10 +75,18 @@ type CheckStaticAddress struct {
 	Name string `json:"name,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for CheckStaticAddress.
+func (a *CheckStaticAddress) MarshalJSON() ([]byte, error) {
+	type rawCheckStaticAddress CheckStaticAddress
+	return marshalUnknownMembers((*rawCheckStaticAddress)(a))
+}
+
 // CheckDynamicAddress contains the InstanceKey field for any resources that
 // have multiple instances. A complete address can be built by combining the
 // CheckStaticAddress with the CheckDynamicAddress.
 type CheckDynamicAddress struct {
+	unknownMembers
+
 	// ToDisplay is a formatted and ready to display representation of the
 	// full address, including the additional information from the relevant
 	// CheckStaticAddress.
@@ -95,11 +105,19 @@ type CheckDynamicAddress struct {
 	InstanceKey interface{} `json:"instance_key,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for CheckDynamicAddress.
+func (a *CheckDynamicAddress) MarshalJSON() ([]byte, error) {
+	type rawCheckDynamicAddress CheckDynamicAddress
+	return marshalUnknownMembers((*rawCheckDynamicAddress)(a))
+}
+
 // CheckResultStatic is the container for a "checkable object".
 //
 // A "checkable object" is a resource or data source, an output, or a check
 // block.
 type CheckResultStatic struct {
+	unknownMembers
+
 	// Address is the absolute address of the "checkable object"
 	Address CheckStaticAddress `json:"address"`
 
@@ -115,9 +133,17 @@ type CheckResultStatic struct {
 	Instances []CheckResultDynamic `json:"instances,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for CheckResultStatic.
+func (c *CheckResultStatic) MarshalJSON() ([]byte, error) {
+	type rawCheckResultStatic CheckResultStatic
+	return marshalUnknownMembers((*rawCheckResultStatic)(c))
+}
+
 // CheckResultDynamic describes the check result for a dynamic object that
 // results from the expansion of the containing object.
 type CheckResultDynamic struct {
+	unknownMembers
+
 	// Address is the relative address of this instance given the Address in the
 	// parent object.
 	Address CheckDynamicAddress `json:"address"`
@@ -136,10 +162,24 @@ type CheckResultDynamic struct {
 	Problems []CheckResultProblem `json:"problems,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for CheckResultDynamic.
+func (c *CheckResultDynamic) MarshalJSON() ([]byte, error) {
+	type rawCheckResultDynamic CheckResultDynamic
+	return marshalUnknownMembers((*rawCheckResultDynamic)(c))
+}
+
 // CheckResultProblem describes one of potentially several problems that led to
 // a check being classied as CheckStatusFail.
 type CheckResultProblem struct {
+	unknownMembers
+
 	// Message is the condition error message provided by the original check
 	// author.
 	Message string `json:"message"`
 }
+
+// MarshalJSON implements json.Marshaler for CheckResultProblem.
+func (p *CheckResultProblem) MarshalJSON() ([]byte, error) {
+	type rawCheckResultProblem CheckResultProblem
+	return marshalUnknownMembers((*rawCheckResultProblem)(p))
+}
@@ -37,16 +37,15 @@ func main() {
 
 	defer f.Close()
 
+	opts := tfjson.DecodeOptions{DisallowUnknownFields: true}
+
 	var parsed interface{}
 	if *schema {
-		parsed = &tfjson.ProviderSchemas{}
+		parsed, err = tfjson.DecodeSchemas(f, opts)
 	} else {
-		parsed = &tfjson.Plan{}
+		parsed, err = tfjson.DecodePlan(f, opts)
 	}
-
-	dec := json.NewDecoder(f)
-	dec.DisallowUnknownFields()
-	if err = dec.Decode(parsed); err != nil {
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -4,12 +4,13 @@
 package tfjson
 
 import (
-	"encoding/json"
 	"errors"
 )
 
 // Config represents the complete configuration source.
 type Config struct {
+	unknownMembers
+
 	// A map of all provider instances across all modules in the
 	// configuration.
 	//
@@ -23,6 +24,12 @@ type Config struct {
 	RootModule *ConfigModule `json:"root_module,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for Config.
+func (c *Config) MarshalJSON() ([]byte, error) {
+	type rawConfig Config
+	return marshalUnknownMembers((*rawConfig)(c))
+}
+
 // Validate checks to ensure that the config is present.
 func (c *Config) Validate() error {
 	if c == nil {
@@ -37,11 +44,17 @@ func (c *Config) Validate() error {
 // As per established convention this method should only ever
 // be invoked *indirectly* via [encoding/json] library.
 func (c *Config) UnmarshalJSON(b []byte) error {
+	_, err := DecodeOptions{}.Unmarshal(b, c)
+	return err
+}
+
+// unmarshalJSONOptions implements optionsUnmarshaler for Config, which is
+// nested within a Plan.
+func (c *Config) unmarshalJSONOptions(d *jsonDecoder, b []byte) error {
 	type rawConfig Config
 	var config rawConfig
 
-	err := json.Unmarshal(b, &config)
-	if err != nil {
+	if err := d.decode(b, &config); err != nil {
 		return err
 	}
 
@@ -52,6 +65,8 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 
 // ProviderConfig describes a provider configuration instance.
 type ProviderConfig struct {
+	unknownMembers
+
 	// The name of the provider, ie: "aws".
 	Name string `json:"name,omitempty"`
 
@@ -72,8 +87,16 @@ type ProviderConfig struct {
 	VersionConstraint string `json:"version_constraint,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for ProviderConfig.
+func (p *ProviderConfig) MarshalJSON() ([]byte, error) {
+	type rawProviderConfig ProviderConfig
+	return marshalUnknownMembers((*rawProviderConfig)(p))
+}
+
 // ConfigModule describes a module in Terraform configuration.
 type ConfigModule struct {
+	unknownMembers
+
 	// The outputs defined in the module.
 	Outputs map[string]*ConfigOutput `json:"outputs,omitempty"`
 
@@ -85,10 +108,21 @@ type ConfigModule struct {
 
 	// The variables defined in the module.
 	Variables map[string]*ConfigVariable `json:"variables,omitempty"`
+
+	// The actions defined in the module.
+	Actions []*ConfigAction `json:"actions,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler for ConfigModule.
+func (m *ConfigModule) MarshalJSON() ([]byte, error) {
+	type rawConfigModule ConfigModule
+	return marshalUnknownMembers((*rawConfigModule)(m))
 }
 
 // ConfigOutput defines an output as defined in configuration.
 type ConfigOutput struct {
+	unknownMembers
+
 	// Indicates whether or not the output was marked as sensitive.
 	Sensitive bool `json:"sensitive,omitempty"`
 
@@ -102,8 +136,16 @@ type ConfigOutput struct {
 	DependsOn []string `json:"depends_on,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for ConfigOutput.
+func (o *ConfigOutput) MarshalJSON() ([]byte, error) {
+	type rawConfigOutput ConfigOutput
+	return marshalUnknownMembers((*rawConfigOutput)(o))
+}
+
 // ConfigResource is the configuration representation of a resource.
 type ConfigResource struct {
+	unknownMembers
+
 	// The address of the resource relative to the module that it is
 	// in.
 	Address string `json:"address,omitempty"`
@@ -150,8 +192,47 @@ type ConfigResource struct {
 	DependsOn []string `json:"depends_on,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for ConfigResource.
+func (r *ConfigResource) MarshalJSON() ([]byte, error) {
+	type rawConfigResource ConfigResource
+	return marshalUnknownMembers((*rawConfigResource)(r))
+}
+
+// ConfigAction is the configuration representation of an action.
+type ConfigAction struct {
+	unknownMembers
+
+	// The address of the action relative to the module that it is in,
+	// such as "action.aws_lambda_invoke.notify".
+	Address string `json:"address,omitempty"`
+
+	// The type of the action, ie: "aws_lambda_invoke".
+	Type string `json:"type,omitempty"`
+
+	// The name of the action, ie: "notify".
+	Name string `json:"name,omitempty"`
+
+	// An opaque key representing the provider configuration this action
+	// uses, as in ConfigResource.
+	ProviderConfigKey string `json:"provider_config_key,omitempty"`
+
+	// The expression data for the "count" value in the action.
+	CountExpression *Expression `json:"count_expression,omitempty"`
+
+	// The expression data for the "for_each" value in the action.
+	ForEachExpression *Expression `json:"for_each_expression,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler for ConfigAction.
+func (a *ConfigAction) MarshalJSON() ([]byte, error) {
+	type rawConfigAction ConfigAction
+	return marshalUnknownMembers((*rawConfigAction)(a))
+}
+
 // ConfigVariable defines a variable as defined in configuration.
 type ConfigVariable struct {
+	unknownMembers
+
 	// The defined default value of the variable.
 	Default interface{} `json:"default,omitempty"`
 
@@ -162,9 +243,17 @@ type ConfigVariable struct {
 	Sensitive bool `json:"sensitive,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for ConfigVariable.
+func (v *ConfigVariable) MarshalJSON() ([]byte, error) {
+	type rawConfigVariable ConfigVariable
+	return marshalUnknownMembers((*rawConfigVariable)(v))
+}
+
 // ConfigProvisioner describes a provisioner declared in a resource
 // configuration.
 type ConfigProvisioner struct {
+	unknownMembers
+
 	// The type of the provisioner, ie: "local-exec".
 	Type string `json:"type,omitempty"`
 
@@ -173,9 +262,17 @@ type ConfigProvisioner struct {
 	Expressions map[string]*Expression `json:"expressions,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for ConfigProvisioner.
+func (p *ConfigProvisioner) MarshalJSON() ([]byte, error) {
+	type rawConfigProvisioner ConfigProvisioner
+	return marshalUnknownMembers((*rawConfigProvisioner)(p))
+}
+
 // ModuleCall describes a declared "module" within a configuration.
 // It also contains the data for the module itself.
 type ModuleCall struct {
+	unknownMembers
+
 	// The contents of the "source" field.
 	Source string `json:"source,omitempty"`
 
@@ -198,4 +295,21 @@ type ModuleCall struct {
 	// The explicit resource dependencies for the "depends_on" value.
 	// As it must be a slice of references, Expression is not used.
 	DependsOn []string `json:"depends_on,omitempty"`
+
+	// The version of the module that is installed, for modules that come
+	// from the registry. This is not part of the JSON representation, and
+	// is only set by ModuleManifest.AnnotateConfig or
+	// ModulesOutput.AnnotateConfig.
+	ResolvedVersion string `json:"-"`
+
+	// The directory the module is installed in, relative to the working
+	// directory. This is not part of the JSON representation, and is only
+	// set by ModuleManifest.AnnotateConfig.
+	ResolvedDir string `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler for ModuleCall.
+func (c *ModuleCall) MarshalJSON() ([]byte, error) {
+	type rawModuleCall ModuleCall
+	return marshalUnknownMembers((*rawModuleCall)(c))
 }
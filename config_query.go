@@ -0,0 +1,330 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import "sort"
+
+// ConfigModuleNode is a module within a configuration, along with its
+// location in the tree of module calls, which ConfigModule does not
+// record.
+type ConfigModuleNode struct {
+	// Address is the address of the module, such as
+	// "module.app.module.web", or empty for the root module.
+	Address string
+
+	// Path is the names of the module calls leading to the module from
+	// the root module, such as ["app", "web"]. It is empty for the root
+	// module.
+	Path []string
+
+	// Module is the module.
+	Module *ConfigModule
+
+	// Call is the module call in the parent module which calls the
+	// module, or nil for the root module.
+	Call *ModuleCall
+
+	// Parent is the module containing Call, or nil for the root module.
+	Parent *ConfigModuleNode
+}
+
+// Qualify returns address, which is relative to the module, qualified
+// with the address of the module.
+func (n *ConfigModuleNode) Qualify(address string) string {
+	if n.Address == "" {
+		return address
+	}
+
+	return n.Address + "." + address
+}
+
+// ConfigObjectKind is the kind of a ConfigObject.
+type ConfigObjectKind string
+
+const (
+	// ConfigObjectResource is a resource, data source or other resource
+	// declared in a module.
+	ConfigObjectResource ConfigObjectKind = "resource"
+
+	// ConfigObjectAction is an action.
+	ConfigObjectAction ConfigObjectKind = "action"
+
+	// ConfigObjectOutput is an output value.
+	ConfigObjectOutput ConfigObjectKind = "output"
+
+	// ConfigObjectVariable is an input variable.
+	ConfigObjectVariable ConfigObjectKind = "variable"
+
+	// ConfigObjectProviderConfig is a provider configuration.
+	ConfigObjectProviderConfig ConfigObjectKind = "provider_config"
+)
+
+// ConfigObject is an object declared within a module of a configuration,
+// visited by Config.Walk. Exactly one of Resource, Action, Output,
+// Variable and ProviderConfig is set, according to Kind.
+type ConfigObject struct {
+	// Kind is the kind of object.
+	Kind ConfigObjectKind
+
+	// Address is the fully qualified address of the object, such as
+	// "module.app.aws_instance.web", "module.app.output.id",
+	// "module.app.var.region" or "module.app.provider.aws.west".
+	Address string
+
+	// Module is the module declaring the object.
+	Module *ConfigModuleNode
+
+	// The object itself.
+	Resource       *ConfigResource
+	Action         *ConfigAction
+	Output         *ConfigOutput
+	Variable       *ConfigVariable
+	ProviderConfig *ProviderConfig
+
+	// ProviderConfigKey is the key of ProviderConfig in
+	// Config.ProviderConfigs, for provider configurations.
+	ProviderConfigKey string
+}
+
+// ConfigQuery selects objects from a configuration. An object must match
+// every field that is set; the zero value matches every object.
+//
+// Fields documented as patterns are matched against the whole value, as
+// in StateResourceQuery.
+type ConfigQuery struct {
+	// Kind is the kind of object, if set.
+	Kind ConfigObjectKind
+
+	// Address is a pattern matched against the fully qualified address of
+	// the object, for example "module.*.aws_iam_role.*".
+	Address string
+
+	// ModuleAddress is a pattern matched against the address of the
+	// module declaring the object, if set. The root module has an empty
+	// address, so a pointer to "" selects only root module objects.
+	ModuleAddress *string
+
+	// ResourceMode is the resource mode, if set. Setting it selects only
+	// resources.
+	ResourceMode ResourceMode
+
+	// ResourceType is a pattern matched against the resource type, for
+	// example "aws_iam_*". Setting it selects only resources.
+	ResourceType string
+}
+
+// Matches returns true if obj matches the query.
+func (q ConfigQuery) Matches(obj *ConfigObject) bool {
+	if obj == nil {
+		return false
+	}
+
+	if q.Kind != "" && q.Kind != obj.Kind {
+		return false
+	}
+	if q.Address != "" && !matchPattern(q.Address, obj.Address) {
+		return false
+	}
+	if q.ModuleAddress != nil {
+		var address string
+		if obj.Module != nil {
+			address = obj.Module.Address
+		}
+		if !matchPattern(*q.ModuleAddress, address) {
+			return false
+		}
+	}
+	if q.ResourceMode != "" && (obj.Resource == nil || obj.Resource.Mode != q.ResourceMode) {
+		return false
+	}
+	if q.ResourceType != "" && (obj.Resource == nil || !matchPattern(q.ResourceType, obj.Resource.Type)) {
+		return false
+	}
+
+	return true
+}
+
+// WalkModules calls fn for the root module of c and each of its
+// descendant modules, depth first, with parents before their children
+// and sibling modules ordered by the name of their module call. If fn
+// returns false the walk stops and WalkModules returns false.
+func (c *Config) WalkModules(fn func(n *ConfigModuleNode) bool) bool {
+	if c == nil || c.RootModule == nil {
+		return true
+	}
+
+	return walkConfigModules(&ConfigModuleNode{Module: c.RootModule}, fn)
+}
+
+func walkConfigModules(n *ConfigModuleNode, fn func(n *ConfigModuleNode) bool) bool {
+	if !fn(n) {
+		return false
+	}
+
+	names := make([]string, 0, len(n.Module.ModuleCalls))
+	for name, call := range n.Module.ModuleCalls {
+		if call != nil && call.Module != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		call := n.Module.ModuleCalls[name]
+		path := make([]string, len(n.Path), len(n.Path)+1)
+		copy(path, n.Path)
+
+		child := &ConfigModuleNode{
+			Address: n.Qualify("module." + name),
+			Path:    append(path, name),
+			Module:  call.Module,
+			Call:    call,
+			Parent:  n,
+		}
+		if !walkConfigModules(child, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Walk calls fn for every object declared in c, in the modules visited by
+// WalkModules. Within each module, provider configurations are visited
+// first, ordered by key, followed by variables ordered by name, resources
+// and then actions in the order they appear, and outputs ordered by name.
+// If fn returns false the walk stops and Walk returns false.
+func (c *Config) Walk(fn func(obj *ConfigObject) bool) bool {
+	if c == nil {
+		return true
+	}
+
+	providerConfigs := map[string][]string{}
+	for key, pc := range c.ProviderConfigs {
+		if pc != nil {
+			module := pc.Ref().Module
+			providerConfigs[module] = append(providerConfigs[module], key)
+		}
+	}
+
+	return c.WalkModules(func(n *ConfigModuleNode) bool {
+		keys := providerConfigs[n.Address]
+		sort.Strings(keys)
+		for _, key := range keys {
+			pc := c.ProviderConfigs[key]
+			address := "provider." + pc.Name
+			if pc.Alias != "" {
+				address += "." + pc.Alias
+			}
+
+			obj := &ConfigObject{
+				Kind:              ConfigObjectProviderConfig,
+				Address:           n.Qualify(address),
+				Module:            n,
+				ProviderConfig:    pc,
+				ProviderConfigKey: key,
+			}
+			if !fn(obj) {
+				return false
+			}
+		}
+
+		for _, name := range sortedKeys(n.Module.Variables) {
+			obj := &ConfigObject{
+				Kind:     ConfigObjectVariable,
+				Address:  n.Qualify("var." + name),
+				Module:   n,
+				Variable: n.Module.Variables[name],
+			}
+			if !fn(obj) {
+				return false
+			}
+		}
+
+		for _, r := range n.Module.Resources {
+			if r == nil {
+				continue
+			}
+
+			obj := &ConfigObject{
+				Kind:     ConfigObjectResource,
+				Address:  n.Qualify(r.Address),
+				Module:   n,
+				Resource: r,
+			}
+			if !fn(obj) {
+				return false
+			}
+		}
+
+		for _, a := range n.Module.Actions {
+			if a == nil {
+				continue
+			}
+
+			obj := &ConfigObject{
+				Kind:    ConfigObjectAction,
+				Address: n.Qualify(a.Address),
+				Module:  n,
+				Action:  a,
+			}
+			if !fn(obj) {
+				return false
+			}
+		}
+
+		for _, name := range sortedKeys(n.Module.Outputs) {
+			obj := &ConfigObject{
+				Kind:    ConfigObjectOutput,
+				Address: n.Qualify("output." + name),
+				Module:  n,
+				Output:  n.Module.Outputs[name],
+			}
+			if !fn(obj) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// Find returns every object declared in c that matches q, in the order
+// visited by Walk.
+func (c *Config) Find(q ConfigQuery) []*ConfigObject {
+	var result []*ConfigObject
+	c.Walk(func(obj *ConfigObject) bool {
+		if q.Matches(obj) {
+			result = append(result, obj)
+		}
+		return true
+	})
+
+	return result
+}
+
+// Module returns the module at address, such as "module.app.module.web",
+// or nil if there is none. The empty address is the root module.
+func (c *Config) Module(address string) *ConfigModuleNode {
+	var result *ConfigModuleNode
+	c.WalkModules(func(n *ConfigModuleNode) bool {
+		if n.Address == address {
+			result = n
+			return false
+		}
+		return true
+	})
+
+	return result
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
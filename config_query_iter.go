@@ -0,0 +1,37 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.23
+
+package tfjson
+
+import "iter"
+
+// AllModules returns an iterator over the root module of c and its
+// descendant modules, in the order visited by WalkModules.
+func (c *Config) AllModules() iter.Seq[*ConfigModuleNode] {
+	return func(yield func(*ConfigModuleNode) bool) {
+		c.WalkModules(yield)
+	}
+}
+
+// AllObjects returns an iterator over every object declared in c, in the
+// order visited by Walk.
+func (c *Config) AllObjects() iter.Seq[*ConfigObject] {
+	return func(yield func(*ConfigObject) bool) {
+		c.Walk(yield)
+	}
+}
+
+// Query returns an iterator over the objects declared in c that match q,
+// in the order visited by Walk.
+func (c *Config) Query(q ConfigQuery) iter.Seq[*ConfigObject] {
+	return func(yield func(*ConfigObject) bool) {
+		c.Walk(func(obj *ConfigObject) bool {
+			if !q.Matches(obj) {
+				return true
+			}
+			return yield(obj)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.23
+
+package tfjson
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConfigQuery_iterator(t *testing.T) {
+	c := testQueryConfig(t, "110_basic")
+
+	var actual []string
+	for obj := range c.Query(ConfigQuery{Kind: ConfigObjectResource}) {
+		actual = append(actual, obj.Address)
+		if obj.Module.Address != "" {
+			break
+		}
+	}
+
+	expected := []string{
+		"null_resource.bar",
+		"null_resource.baz",
+		"null_resource.foo",
+		"module.foo.null_resource.aliased",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("Query() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestConfigAllModules_iterator(t *testing.T) {
+	c := testQueryConfig(t, "deep_module")
+
+	var actual []string
+	for n := range c.AllModules() {
+		actual = append(actual, n.Address)
+	}
+
+	expected := []string{"", "module.foo", "module.foo.module.bar"}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("AllModules() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	count := 0
+	for range c.AllObjects() {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("expected 1 object, got %d", count)
+	}
+}
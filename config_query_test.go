@@ -0,0 +1,172 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testQueryConfig(t *testing.T, fixture string) *Config {
+	t.Helper()
+
+	return testReadPlan(t, filepath.Join(testFixtureDir, fixture, testGoldenPlanFileName)).Config
+}
+
+func TestConfigWalk(t *testing.T) {
+	c := testQueryConfig(t, "110_basic")
+
+	var actual []string
+	c.Walk(func(obj *ConfigObject) bool {
+		actual = append(actual, string(obj.Kind)+" "+obj.Address)
+		return true
+	})
+
+	expected := []string{
+		"provider_config provider.aws",
+		"provider_config provider.aws.east",
+		"provider_config provider.null",
+		"variable var.foo",
+		"variable var.map",
+		"variable var.number",
+		"resource null_resource.bar",
+		"resource null_resource.baz",
+		"resource null_resource.foo",
+		"output output.foo",
+		"output output.interpolated",
+		"output output.interpolated_deep",
+		"output output.list",
+		"output output.map",
+		"output output.referenced",
+		"output output.referenced_deep",
+		"output output.string",
+		"provider_config module.foo.provider.null",
+		"variable module.foo.var.bar",
+		"variable module.foo.var.one",
+		"resource module.foo.null_resource.aliased",
+		"resource module.foo.null_resource.foo",
+		"output module.foo.output.foo",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("Walk() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	var count int
+	if c.Walk(func(obj *ConfigObject) bool {
+		count++
+		return obj.Kind != ConfigObjectResource
+	}) {
+		t.Error("expected Walk to return false when stopped")
+	}
+	if count != 7 {
+		t.Errorf("expected walk to stop at the first resource, visited %d objects", count)
+	}
+
+	var nilConfig *Config
+	if !nilConfig.Walk(func(*ConfigObject) bool { t.Fatal("unexpected object"); return true }) {
+		t.Error("expected Walk of nil config to return true")
+	}
+}
+
+func TestConfigWalkModules(t *testing.T) {
+	c := testQueryConfig(t, "deep_module")
+
+	type module struct {
+		Address string
+		Path    []string
+		Parent  string
+	}
+	var actual []module
+	c.WalkModules(func(n *ConfigModuleNode) bool {
+		m := module{Address: n.Address, Path: n.Path}
+		if n.Parent != nil {
+			m.Parent = n.Parent.Address
+			if n.Parent.Module.ModuleCalls[n.Path[len(n.Path)-1]] != n.Call {
+				t.Errorf("%s: unexpected module call", n.Address)
+			}
+		}
+		actual = append(actual, m)
+		return true
+	})
+
+	expected := []module{
+		{Address: ""},
+		{Address: "module.foo", Path: []string{"foo"}},
+		{Address: "module.foo.module.bar", Path: []string{"foo", "bar"}, Parent: "module.foo"},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("WalkModules() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	n := c.Module("module.foo.module.bar")
+	if n == nil {
+		t.Fatal("expected module.foo.module.bar")
+	}
+	if got := n.Qualify(n.Module.Resources[0].Address); got != "module.foo.module.bar.null_resource.baz" {
+		t.Errorf("unexpected qualified address %q", got)
+	}
+	if c.Module("module.bar") != nil {
+		t.Error("expected no module.bar")
+	}
+}
+
+func TestConfigFind(t *testing.T) {
+	c := testQueryConfig(t, "110_basic")
+	root := ""
+
+	cases := []struct {
+		name     string
+		query    ConfigQuery
+		expected []string
+	}{
+		{
+			name:     "kind",
+			query:    ConfigQuery{Kind: ConfigObjectVariable, Address: "module.*"},
+			expected: []string{"module.foo.var.bar", "module.foo.var.one"},
+		},
+		{
+			name:     "root module",
+			query:    ConfigQuery{ModuleAddress: &root, Address: "*.foo"},
+			expected: []string{"var.foo", "null_resource.foo", "output.foo"},
+		},
+		{
+			name:     "resource type",
+			query:    ConfigQuery{ResourceType: "null_*", ModuleAddress: new(string)},
+			expected: []string{"null_resource.bar", "null_resource.baz", "null_resource.foo"},
+		},
+		{
+			name:  "resource mode",
+			query: ConfigQuery{ResourceMode: DataResourceMode},
+		},
+		{
+			name:     "provider config",
+			query:    ConfigQuery{Kind: ConfigObjectProviderConfig, Address: "*.null"},
+			expected: []string{"provider.null", "module.foo.provider.null"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual []string
+			for _, obj := range c.Find(tc.query) {
+				actual = append(actual, obj.Address)
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("Find() mismatch (-expected +actual):\n%s", diff)
+			}
+		})
+	}
+
+	actions := testQueryConfig(t, "config_constructs").Find(ConfigQuery{Kind: ConfigObjectAction})
+	if len(actions) != 2 || actions[0].Address != "action.demo_notify.batch" || actions[0].Action == nil {
+		t.Errorf("unexpected actions %#v", actions)
+	}
+
+	obj := c.Find(ConfigQuery{Address: "module.foo.provider.null"})
+	if len(obj) != 1 || obj[0].ProviderConfigKey != "module.foo:null" || obj[0].ProviderConfig != c.ProviderConfigs["module.foo:null"] {
+		t.Errorf("unexpected provider config %#v", obj)
+	}
+}
@@ -6,7 +6,10 @@ package tfjson
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestConfigValidate(t *testing.T) {
@@ -25,3 +28,57 @@ func TestConfigValidate(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestConfig_constructs(t *testing.T) {
+	plan := testReadPlan(t, filepath.Join(testFixtureDir, "config_constructs", testGoldenPlanFileName))
+	root := plan.Config.RootModule
+
+	expectedVariables := map[string]*ConfigVariable{
+		"environment": {
+			Default:     "staging",
+			Description: "The environment to deploy into.",
+		},
+		"labels": {
+			Default: map[string]interface{}{"team": "platform"},
+		},
+		"replicas": {
+			Default: 2.0,
+		},
+		"token": {
+			Default:   "secret",
+			Sensitive: true,
+		},
+	}
+	if diff := cmp.Diff(expectedVariables, root.Variables, testCmpUnexported); diff != "" {
+		t.Errorf("unexpected variables (-want +got):\n%s", diff)
+	}
+
+	expectedActions := []*ConfigAction{
+		{
+			Address:           "action.demo_notify.batch",
+			Type:              "demo_notify",
+			Name:              "batch",
+			ProviderConfigKey: "demo",
+			CountExpression: &Expression{
+				ExpressionData: &ExpressionData{ConstantValue: 2.0},
+			},
+		},
+		{
+			Address:           "action.demo_notify.deployed",
+			Type:              "demo_notify",
+			Name:              "deployed",
+			ProviderConfigKey: "demo",
+		},
+	}
+	if diff := cmp.Diff(expectedActions, root.Actions, testCmpUnexported); diff != "" {
+		t.Errorf("unexpected actions (-want +got):\n%s", diff)
+	}
+
+	notifier := root.ModuleCalls["notifier"].Module
+	if o := notifier.Outputs["token"]; !o.Sensitive {
+		t.Errorf("expected sensitive output, got %#v", o)
+	}
+	if v := notifier.Variables["token"]; !v.Sensitive {
+		t.Errorf("expected sensitive variable, got %#v", v)
+	}
+}
@@ -0,0 +1,901 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import "encoding/json"
+
+// copyJSONValue returns a deep copy of a value decoded from JSON into an
+// interface{}. Objects and arrays are copied recursively. Any other value,
+// including scalars, json.Number and the UnknownConstantValue singleton, is
+// returned as-is so that it continues to compare equal to the original.
+func copyJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return copyJSONObject(v)
+	case []interface{}:
+		return copyJSONArray(v)
+	case json.RawMessage:
+		return copyRawMessage(v)
+	}
+
+	return v
+}
+
+func copyJSONObject(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = copyJSONValue(v)
+	}
+
+	return result
+}
+
+func copyJSONArray(s []interface{}) []interface{} {
+	if s == nil {
+		return nil
+	}
+
+	result := make([]interface{}, len(s))
+	for i, v := range s {
+		result[i] = copyJSONValue(v)
+	}
+
+	return result
+}
+
+func copyRawMessage(m json.RawMessage) json.RawMessage {
+	if m == nil {
+		return nil
+	}
+
+	result := make(json.RawMessage, len(m))
+	copy(result, m)
+	return result
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+
+	result := make([]string, len(s))
+	copy(result, s)
+	return result
+}
+
+func copyUint64Ptr(p *uint64) *uint64 {
+	if p == nil {
+		return nil
+	}
+
+	v := *p
+	return &v
+}
+
+func copyBoolPtr(p *bool) *bool {
+	if p == nil {
+		return nil
+	}
+
+	v := *p
+	return &v
+}
+
+// DeepCopy returns a deep copy of the Plan.
+func (p *Plan) DeepCopy() *Plan {
+	if p == nil {
+		return nil
+	}
+
+	result := *p
+	result.unknownFields = copyStrings(p.unknownFields)
+
+	if p.Variables != nil {
+		result.Variables = make(map[string]*PlanVariable, len(p.Variables))
+		for k, v := range p.Variables {
+			result.Variables[k] = v.DeepCopy()
+		}
+	}
+
+	result.PlannedValues = p.PlannedValues.DeepCopy()
+	result.ResourceDrift = copyResourceChanges(p.ResourceDrift)
+	result.ResourceChanges = copyResourceChanges(p.ResourceChanges)
+
+	if p.DeferredChanges != nil {
+		result.DeferredChanges = make([]*DeferredResourceChange, len(p.DeferredChanges))
+		for i, v := range p.DeferredChanges {
+			result.DeferredChanges[i] = v.DeepCopy()
+		}
+	}
+
+	result.Complete = copyBoolPtr(p.Complete)
+	result.Applyable = copyBoolPtr(p.Applyable)
+	result.Errored = copyBoolPtr(p.Errored)
+
+	if p.OutputChanges != nil {
+		result.OutputChanges = make(map[string]*Change, len(p.OutputChanges))
+		for k, v := range p.OutputChanges {
+			result.OutputChanges[k] = v.DeepCopy()
+		}
+	}
+
+	result.PriorState = p.PriorState.DeepCopy()
+	result.Config = p.Config.DeepCopy()
+
+	if p.RelevantAttributes != nil {
+		result.RelevantAttributes = make([]ResourceAttribute, len(p.RelevantAttributes))
+		for i := range p.RelevantAttributes {
+			result.RelevantAttributes[i] = *p.RelevantAttributes[i].DeepCopy()
+		}
+	}
+
+	result.Checks = copyCheckResults(p.Checks)
+
+	if p.ActionInvocations != nil {
+		result.ActionInvocations = make([]*ActionInvocation, len(p.ActionInvocations))
+		for i, v := range p.ActionInvocations {
+			result.ActionInvocations[i] = v.DeepCopy()
+		}
+	}
+
+	return &result
+}
+
+func copyResourceChanges(s []*ResourceChange) []*ResourceChange {
+	if s == nil {
+		return nil
+	}
+
+	result := make([]*ResourceChange, len(s))
+	for i, v := range s {
+		result[i] = v.DeepCopy()
+	}
+
+	return result
+}
+
+// DeepCopy returns a deep copy of the ResourceAttribute.
+func (ra *ResourceAttribute) DeepCopy() *ResourceAttribute {
+	if ra == nil {
+		return nil
+	}
+
+	result := *ra
+	if ra.Attribute != nil {
+		result.Attribute = make([]json.RawMessage, len(ra.Attribute))
+		for i, v := range ra.Attribute {
+			result.Attribute[i] = copyRawMessage(v)
+		}
+	}
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the ResourceChange.
+func (rc *ResourceChange) DeepCopy() *ResourceChange {
+	if rc == nil {
+		return nil
+	}
+
+	result := *rc
+	result.Index = copyJSONValue(rc.Index)
+	result.Change = rc.Change.DeepCopy()
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the Change.
+func (c *Change) DeepCopy() *Change {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+
+	if c.Actions != nil {
+		result.Actions = make(Actions, len(c.Actions))
+		copy(result.Actions, c.Actions)
+	}
+
+	result.Before = copyJSONValue(c.Before)
+	result.After = copyJSONValue(c.After)
+	result.AfterUnknown = copyJSONValue(c.AfterUnknown)
+	result.BeforeSensitive = copyJSONValue(c.BeforeSensitive)
+	result.AfterSensitive = copyJSONValue(c.AfterSensitive)
+	result.Importing = c.Importing.DeepCopy()
+	result.ReplacePaths = copyJSONArray(c.ReplacePaths)
+	result.BeforeIdentity = copyJSONValue(c.BeforeIdentity)
+	result.AfterIdentity = copyJSONValue(c.AfterIdentity)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the Importing metadata.
+func (i *Importing) DeepCopy() *Importing {
+	if i == nil {
+		return nil
+	}
+
+	result := *i
+	result.Identity = copyJSONValue(i.Identity)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the PlanVariable.
+func (v *PlanVariable) DeepCopy() *PlanVariable {
+	if v == nil {
+		return nil
+	}
+
+	result := *v
+	result.Value = copyJSONValue(v.Value)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the DeferredResourceChange.
+func (d *DeferredResourceChange) DeepCopy() *DeferredResourceChange {
+	if d == nil {
+		return nil
+	}
+
+	result := *d
+	result.ResourceChange = d.ResourceChange.DeepCopy()
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the ActionInvocation.
+func (a *ActionInvocation) DeepCopy() *ActionInvocation {
+	if a == nil {
+		return nil
+	}
+
+	result := *a
+	result.ConfigValues = copyJSONValue(a.ConfigValues)
+	result.ConfigSensitive = copyJSONValue(a.ConfigSensitive)
+	result.ConfigUnknown = copyJSONValue(a.ConfigUnknown)
+
+	if a.LifecycleActionTrigger != nil {
+		trigger := *a.LifecycleActionTrigger
+		result.LifecycleActionTrigger = &trigger
+	}
+
+	if a.InvokeActionTrigger != nil {
+		trigger := *a.InvokeActionTrigger
+		result.InvokeActionTrigger = &trigger
+	}
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the State.
+func (s *State) DeepCopy() *State {
+	if s == nil {
+		return nil
+	}
+
+	result := *s
+	result.unknownFields = copyStrings(s.unknownFields)
+	result.Values = s.Values.DeepCopy()
+	result.Checks = copyCheckResults(s.Checks)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the StateValues.
+func (v *StateValues) DeepCopy() *StateValues {
+	if v == nil {
+		return nil
+	}
+
+	result := *v
+
+	if v.Outputs != nil {
+		result.Outputs = make(map[string]*StateOutput, len(v.Outputs))
+		for k, o := range v.Outputs {
+			result.Outputs[k] = o.DeepCopy()
+		}
+	}
+
+	result.RootModule = v.RootModule.DeepCopy()
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the StateModule, including all of its
+// resources and child modules.
+func (m *StateModule) DeepCopy() *StateModule {
+	if m == nil {
+		return nil
+	}
+
+	result := *m
+
+	if m.Resources != nil {
+		result.Resources = make([]*StateResource, len(m.Resources))
+		for i, r := range m.Resources {
+			result.Resources[i] = r.DeepCopy()
+		}
+	}
+
+	if m.ChildModules != nil {
+		result.ChildModules = make([]*StateModule, len(m.ChildModules))
+		for i, c := range m.ChildModules {
+			result.ChildModules[i] = c.DeepCopy()
+		}
+	}
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the StateResource.
+func (r *StateResource) DeepCopy() *StateResource {
+	if r == nil {
+		return nil
+	}
+
+	result := *r
+	result.Index = copyJSONValue(r.Index)
+	result.AttributeValues = copyJSONObject(r.AttributeValues)
+	result.SensitiveValues = copyRawMessage(r.SensitiveValues)
+	result.DependsOn = copyStrings(r.DependsOn)
+	result.IdentitySchemaVersion = copyUint64Ptr(r.IdentitySchemaVersion)
+	result.IdentityValues = copyJSONObject(r.IdentityValues)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the StateOutput. The output type is
+// immutable and is shared with the original.
+func (o *StateOutput) DeepCopy() *StateOutput {
+	if o == nil {
+		return nil
+	}
+
+	result := *o
+	result.Value = copyJSONValue(o.Value)
+
+	return &result
+}
+
+func copyCheckResults(s []CheckResultStatic) []CheckResultStatic {
+	if s == nil {
+		return nil
+	}
+
+	result := make([]CheckResultStatic, len(s))
+	for i := range s {
+		result[i] = *s[i].DeepCopy()
+	}
+
+	return result
+}
+
+// DeepCopy returns a deep copy of the CheckResultStatic.
+func (c *CheckResultStatic) DeepCopy() *CheckResultStatic {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+	if c.Instances != nil {
+		result.Instances = make([]CheckResultDynamic, len(c.Instances))
+		for i := range c.Instances {
+			result.Instances[i] = *c.Instances[i].DeepCopy()
+		}
+	}
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the CheckResultDynamic.
+func (c *CheckResultDynamic) DeepCopy() *CheckResultDynamic {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+	result.Address.InstanceKey = copyJSONValue(c.Address.InstanceKey)
+	if c.Problems != nil {
+		result.Problems = make([]CheckResultProblem, len(c.Problems))
+		copy(result.Problems, c.Problems)
+	}
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the Config.
+func (c *Config) DeepCopy() *Config {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+
+	if c.ProviderConfigs != nil {
+		result.ProviderConfigs = make(map[string]*ProviderConfig, len(c.ProviderConfigs))
+		for k, v := range c.ProviderConfigs {
+			result.ProviderConfigs[k] = v.DeepCopy()
+		}
+	}
+
+	result.RootModule = c.RootModule.DeepCopy()
+
+	return &result
+}
+
+func copyExpressions(m map[string]*Expression) map[string]*Expression {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[string]*Expression, len(m))
+	for k, v := range m {
+		result[k] = v.DeepCopy()
+	}
+
+	return result
+}
+
+// DeepCopy returns a deep copy of the ProviderConfig.
+func (p *ProviderConfig) DeepCopy() *ProviderConfig {
+	if p == nil {
+		return nil
+	}
+
+	result := *p
+	result.Expressions = copyExpressions(p.Expressions)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the ConfigModule, including all of its
+// module calls and their modules.
+func (m *ConfigModule) DeepCopy() *ConfigModule {
+	if m == nil {
+		return nil
+	}
+
+	result := *m
+
+	if m.Outputs != nil {
+		result.Outputs = make(map[string]*ConfigOutput, len(m.Outputs))
+		for k, v := range m.Outputs {
+			result.Outputs[k] = v.DeepCopy()
+		}
+	}
+
+	if m.Resources != nil {
+		result.Resources = make([]*ConfigResource, len(m.Resources))
+		for i, v := range m.Resources {
+			result.Resources[i] = v.DeepCopy()
+		}
+	}
+
+	if m.ModuleCalls != nil {
+		result.ModuleCalls = make(map[string]*ModuleCall, len(m.ModuleCalls))
+		for k, v := range m.ModuleCalls {
+			result.ModuleCalls[k] = v.DeepCopy()
+		}
+	}
+
+	if m.Variables != nil {
+		result.Variables = make(map[string]*ConfigVariable, len(m.Variables))
+		for k, v := range m.Variables {
+			result.Variables[k] = v.DeepCopy()
+		}
+	}
+
+	if m.Actions != nil {
+		result.Actions = make([]*ConfigAction, len(m.Actions))
+		for i, v := range m.Actions {
+			result.Actions[i] = v.DeepCopy()
+		}
+	}
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the ConfigOutput.
+func (o *ConfigOutput) DeepCopy() *ConfigOutput {
+	if o == nil {
+		return nil
+	}
+
+	result := *o
+	result.Expression = o.Expression.DeepCopy()
+	result.DependsOn = copyStrings(o.DependsOn)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the ConfigResource.
+func (r *ConfigResource) DeepCopy() *ConfigResource {
+	if r == nil {
+		return nil
+	}
+
+	result := *r
+
+	if r.Provisioners != nil {
+		result.Provisioners = make([]*ConfigProvisioner, len(r.Provisioners))
+		for i, v := range r.Provisioners {
+			result.Provisioners[i] = v.DeepCopy()
+		}
+	}
+
+	result.Expressions = copyExpressions(r.Expressions)
+	result.CountExpression = r.CountExpression.DeepCopy()
+	result.ForEachExpression = r.ForEachExpression.DeepCopy()
+	result.DependsOn = copyStrings(r.DependsOn)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the ConfigAction.
+func (a *ConfigAction) DeepCopy() *ConfigAction {
+	if a == nil {
+		return nil
+	}
+
+	result := *a
+	result.CountExpression = a.CountExpression.DeepCopy()
+	result.ForEachExpression = a.ForEachExpression.DeepCopy()
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the ConfigVariable.
+func (v *ConfigVariable) DeepCopy() *ConfigVariable {
+	if v == nil {
+		return nil
+	}
+
+	result := *v
+	result.Default = copyJSONValue(v.Default)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the ConfigProvisioner.
+func (p *ConfigProvisioner) DeepCopy() *ConfigProvisioner {
+	if p == nil {
+		return nil
+	}
+
+	result := *p
+	result.Expressions = copyExpressions(p.Expressions)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the ModuleCall, including the module it
+// calls.
+func (c *ModuleCall) DeepCopy() *ModuleCall {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+	result.Expressions = copyExpressions(c.Expressions)
+	result.CountExpression = c.CountExpression.DeepCopy()
+	result.ForEachExpression = c.ForEachExpression.DeepCopy()
+	result.Module = c.Module.DeepCopy()
+	result.DependsOn = copyStrings(c.DependsOn)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the Expression. An UnknownConstantValue
+// is preserved as the same singleton.
+func (e *Expression) DeepCopy() *Expression {
+	if e == nil {
+		return nil
+	}
+
+	return &Expression{ExpressionData: e.ExpressionData.DeepCopy()}
+}
+
+// DeepCopy returns a deep copy of the ExpressionData.
+func (e *ExpressionData) DeepCopy() *ExpressionData {
+	if e == nil {
+		return nil
+	}
+
+	result := *e
+	result.ConstantValue = copyJSONValue(e.ConstantValue)
+	result.References = copyStrings(e.References)
+
+	if e.NestedBlocks != nil {
+		result.NestedBlocks = make([]map[string]*Expression, len(e.NestedBlocks))
+		for i, block := range e.NestedBlocks {
+			result.NestedBlocks[i] = copyExpressions(block)
+		}
+	}
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the ProviderSchemas.
+func (p *ProviderSchemas) DeepCopy() *ProviderSchemas {
+	if p == nil {
+		return nil
+	}
+
+	result := *p
+	result.unknownFields = copyStrings(p.unknownFields)
+	if p.Schemas != nil {
+		result.Schemas = make(map[string]*ProviderSchema, len(p.Schemas))
+		for k, v := range p.Schemas {
+			result.Schemas[k] = v.DeepCopy()
+		}
+	}
+
+	return &result
+}
+
+func copySchemas(m map[string]*Schema) map[string]*Schema {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[string]*Schema, len(m))
+	for k, v := range m {
+		result[k] = v.DeepCopy()
+	}
+
+	return result
+}
+
+func copyFunctionSignatures(m map[string]*FunctionSignature) map[string]*FunctionSignature {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[string]*FunctionSignature, len(m))
+	for k, v := range m {
+		result[k] = v.DeepCopy()
+	}
+
+	return result
+}
+
+// DeepCopy returns a deep copy of the ProviderSchema.
+func (p *ProviderSchema) DeepCopy() *ProviderSchema {
+	if p == nil {
+		return nil
+	}
+
+	result := *p
+	result.ConfigSchema = p.ConfigSchema.DeepCopy()
+	result.ResourceSchemas = copySchemas(p.ResourceSchemas)
+	result.DataSourceSchemas = copySchemas(p.DataSourceSchemas)
+	result.EphemeralResourceSchemas = copySchemas(p.EphemeralResourceSchemas)
+	result.ListResourceSchemas = copySchemas(p.ListResourceSchemas)
+	result.StateStoreSchemas = copySchemas(p.StateStoreSchemas)
+	result.Functions = copyFunctionSignatures(p.Functions)
+
+	if p.ActionSchemas != nil {
+		result.ActionSchemas = make(map[string]*ActionSchema, len(p.ActionSchemas))
+		for k, v := range p.ActionSchemas {
+			result.ActionSchemas[k] = v.DeepCopy()
+		}
+	}
+
+	if p.ResourceIdentitySchemas != nil {
+		result.ResourceIdentitySchemas = make(map[string]*IdentitySchema, len(p.ResourceIdentitySchemas))
+		for k, v := range p.ResourceIdentitySchemas {
+			result.ResourceIdentitySchemas[k] = v.DeepCopy()
+		}
+	}
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the Schema.
+func (s *Schema) DeepCopy() *Schema {
+	if s == nil {
+		return nil
+	}
+
+	result := *s
+	result.Block = s.Block.DeepCopy()
+
+	return &result
+}
+
+func copySchemaAttributes(m map[string]*SchemaAttribute) map[string]*SchemaAttribute {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[string]*SchemaAttribute, len(m))
+	for k, v := range m {
+		result[k] = v.DeepCopy()
+	}
+
+	return result
+}
+
+// DeepCopy returns a deep copy of the SchemaBlock.
+func (b *SchemaBlock) DeepCopy() *SchemaBlock {
+	if b == nil {
+		return nil
+	}
+
+	result := *b
+	result.Attributes = copySchemaAttributes(b.Attributes)
+
+	if b.NestedBlocks != nil {
+		result.NestedBlocks = make(map[string]*SchemaBlockType, len(b.NestedBlocks))
+		for k, v := range b.NestedBlocks {
+			result.NestedBlocks[k] = v.DeepCopy()
+		}
+	}
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the SchemaBlockType.
+func (b *SchemaBlockType) DeepCopy() *SchemaBlockType {
+	if b == nil {
+		return nil
+	}
+
+	result := *b
+	result.Block = b.Block.DeepCopy()
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the SchemaAttribute. The attribute type is
+// immutable and is shared with the original.
+func (a *SchemaAttribute) DeepCopy() *SchemaAttribute {
+	if a == nil {
+		return nil
+	}
+
+	result := *a
+	result.AttributeNestedType = a.AttributeNestedType.DeepCopy()
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the SchemaNestedAttributeType.
+func (t *SchemaNestedAttributeType) DeepCopy() *SchemaNestedAttributeType {
+	if t == nil {
+		return nil
+	}
+
+	result := *t
+	result.Attributes = copySchemaAttributes(t.Attributes)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the IdentitySchema.
+func (s *IdentitySchema) DeepCopy() *IdentitySchema {
+	if s == nil {
+		return nil
+	}
+
+	result := *s
+	if s.Attributes != nil {
+		result.Attributes = make(map[string]*IdentityAttribute, len(s.Attributes))
+		for k, v := range s.Attributes {
+			attr := *v
+			result.Attributes[k] = &attr
+		}
+	}
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the ActionSchema.
+func (s *ActionSchema) DeepCopy() *ActionSchema {
+	if s == nil {
+		return nil
+	}
+
+	result := *s
+	result.Block = s.Block.DeepCopy()
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the MetadataFunctions.
+func (f *MetadataFunctions) DeepCopy() *MetadataFunctions {
+	if f == nil {
+		return nil
+	}
+
+	result := *f
+	result.unknownFields = copyStrings(f.unknownFields)
+	result.Signatures = copyFunctionSignatures(f.Signatures)
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the FunctionSignature.
+func (s *FunctionSignature) DeepCopy() *FunctionSignature {
+	if s == nil {
+		return nil
+	}
+
+	result := *s
+
+	if s.Parameters != nil {
+		result.Parameters = make([]*FunctionParameter, len(s.Parameters))
+		for i, v := range s.Parameters {
+			result.Parameters[i] = v.DeepCopy()
+		}
+	}
+
+	result.VariadicParameter = s.VariadicParameter.DeepCopy()
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the FunctionParameter.
+func (p *FunctionParameter) DeepCopy() *FunctionParameter {
+	if p == nil {
+		return nil
+	}
+
+	result := *p
+	return &result
+}
+
+// DeepCopy returns a deep copy of the ValidateOutput.
+func (vo *ValidateOutput) DeepCopy() *ValidateOutput {
+	if vo == nil {
+		return nil
+	}
+
+	result := *vo
+	result.unknownFields = copyStrings(vo.unknownFields)
+	if vo.Diagnostics != nil {
+		result.Diagnostics = make([]Diagnostic, len(vo.Diagnostics))
+		for i := range vo.Diagnostics {
+			result.Diagnostics[i] = *vo.Diagnostics[i].DeepCopy()
+		}
+	}
+
+	return &result
+}
+
+// DeepCopy returns a deep copy of the Diagnostic.
+func (d *Diagnostic) DeepCopy() *Diagnostic {
+	if d == nil {
+		return nil
+	}
+
+	result := *d
+
+	if d.Range != nil {
+		rng := *d.Range
+		result.Range = &rng
+	}
+
+	if d.Snippet != nil {
+		snippet := *d.Snippet
+		if d.Snippet.Context != nil {
+			context := *d.Snippet.Context
+			snippet.Context = &context
+		}
+		if d.Snippet.Values != nil {
+			snippet.Values = make([]DiagnosticExpressionValue, len(d.Snippet.Values))
+			copy(snippet.Values, d.Snippet.Values)
+		}
+		result.Snippet = &snippet
+	}
+
+	return &result
+}
@@ -0,0 +1,136 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/copystructure"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+)
+
+var copyCmpOpts = cmp.Options{
+	ctydebug.CmpOptions,
+	testCmpUnexported,
+}
+
+func TestPlanDeepCopy(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(testFixtureDir, "*", testGoldenPlanFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range paths {
+		if filepath.Base(filepath.Dir(path)) == testInvalidDir {
+			continue
+		}
+
+		t.Run(filepath.Base(filepath.Dir(path)), func(t *testing.T) {
+			plan := testReadPlan(t, path)
+			copied := plan.DeepCopy()
+
+			if diff := cmp.Diff(plan, copied, copyCmpOpts); diff != "" {
+				t.Fatalf("unexpected difference: %s", diff)
+			}
+
+			// Mutating the copy must not affect the original.
+			for _, rc := range copied.ResourceChanges {
+				if after, ok := rc.Change.After.(map[string]interface{}); ok {
+					after["mutated"] = true
+				}
+			}
+			for _, rc := range plan.ResourceChanges {
+				if after, ok := rc.Change.After.(map[string]interface{}); ok {
+					if _, ok := after["mutated"]; ok {
+						t.Fatalf("original plan was mutated at %s", rc.Address)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExpressionDeepCopy_unknownConstantValue(t *testing.T) {
+	expr := &Expression{
+		ExpressionData: &ExpressionData{
+			ConstantValue: UnknownConstantValue,
+			References:    []string{"var.foo"},
+		},
+	}
+
+	copied := expr.DeepCopy()
+	if copied.ConstantValue != UnknownConstantValue {
+		t.Fatal("UnknownConstantValue was not preserved")
+	}
+
+	copied.References[0] = "var.bar"
+	if expr.References[0] != "var.foo" {
+		t.Fatal("original expression was mutated")
+	}
+}
+
+func TestDeepCopy_nil(t *testing.T) {
+	if (*Plan)(nil).DeepCopy() != nil {
+		t.Fatal("expected nil plan copy")
+	}
+	if (*State)(nil).DeepCopy() != nil {
+		t.Fatal("expected nil state copy")
+	}
+	if (*Config)(nil).DeepCopy() != nil {
+		t.Fatal("expected nil config copy")
+	}
+	if (*Change)(nil).DeepCopy() != nil {
+		t.Fatal("expected nil change copy")
+	}
+}
+
+func BenchmarkPlanDeepCopy(b *testing.B) {
+	plan := testReadPlan(b, filepath.Join(testFixtureDir, "has_changes", testGoldenPlanFileName))
+
+	b.Run("DeepCopy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = plan.DeepCopy()
+		}
+	})
+
+	// copystructure is the reflective copier previously used by the
+	// sanitize package and is kept here as a baseline.
+	b.Run("copystructure", func(b *testing.B) {
+		c := &copystructure.Config{
+			ShallowCopiers: map[reflect.Type]struct{}{
+				reflect.TypeOf(UnknownConstantValue): {},
+			},
+		}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := c.Copy(plan); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func testReadPlan(t testing.TB, path string) *Plan {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var plan *Plan
+	if err := json.NewDecoder(f).Decode(&plan); err != nil {
+		t.Fatal(err)
+	}
+
+	return plan
+}
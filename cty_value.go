@@ -0,0 +1,292 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// ValueMark is the type of the cty value marks applied by this package.
+type ValueMark string
+
+// SensitiveMark is the mark applied to sensitive values returned as
+// cty.Values, such as by StateOutput.CtyValue. Sensitive values must be
+// unmarked with cty.Value.Unmark or cty.Value.UnmarkDeep before they can
+// be used in most operations.
+const SensitiveMark ValueMark = "sensitive"
+
+// CtyValue returns the value of the output as a cty.Value of the output's
+// Type, so that numbers keep their precision and sets and maps are
+// distinguished from lists and objects. If the output is sensitive, the
+// value is marked with SensitiveMark.
+//
+// Type is only recorded by Terraform 0.15 and later. If it is absent,
+// the type is inferred from the JSON representation of the value, which
+// can only produce numbers, strings, bools, tuples and objects.
+func (so *StateOutput) CtyValue() (cty.Value, error) {
+	if so == nil {
+		return cty.NilVal, errors.New("output is nil")
+	}
+
+	return ctyValue(so.Value, so.Type, nil, so.Sensitive)
+}
+
+// Decode decodes the value of the output into the Go value pointed to by
+// target, using gocty. See gocty.FromCtyValue for the supported target
+// types. Decode will decode sensitive values, so callers must take care
+// not to reveal them.
+func (so *StateOutput) Decode(target interface{}) error {
+	val, err := so.CtyValue()
+	if err != nil {
+		return err
+	}
+
+	return DecodeCtyValue(val, target)
+}
+
+// CtyValues returns the Before and After values of the change as
+// cty.Values of the given types. Values within After which are not known
+// until apply, as described by AfterUnknown, are unknown values, and
+// values described as sensitive by BeforeSensitive and AfterSensitive
+// are marked with SensitiveMark.
+//
+// Either type may be cty.NilType, in which case it is inferred from the
+// JSON representation of the value as in StateOutput.CtyValue. A
+// missing Before or After value, such as the Before value of a create
+// action, is returned as a null value.
+func (c *Change) CtyValues(beforeType, afterType cty.Type) (before, after cty.Value, err error) {
+	if c == nil {
+		return cty.NilVal, cty.NilVal, errors.New("change is nil")
+	}
+
+	before, err = ctyValue(c.Before, beforeType, nil, c.BeforeSensitive)
+	if err != nil {
+		return cty.NilVal, cty.NilVal, fmt.Errorf("before: %w", err)
+	}
+
+	after, err = ctyValue(c.After, afterType, c.AfterUnknown, c.AfterSensitive)
+	if err != nil {
+		return cty.NilVal, cty.NilVal, fmt.Errorf("after: %w", err)
+	}
+
+	return before, after, nil
+}
+
+// OutputChangeValues returns the Before and After values of the named
+// output change as cty.Values, using the output types recorded in
+// PriorState and PlannedValues respectively. See Change.CtyValues.
+//
+// Values are also marked with SensitiveMark if the output is marked as
+// sensitive in PriorState or PlannedValues, as older versions of
+// Terraform do not record the sensitivity of output changes.
+func (p *Plan) OutputChangeValues(name string) (before, after cty.Value, err error) {
+	if p == nil {
+		return cty.NilVal, cty.NilVal, errors.New("plan is nil")
+	}
+
+	c, ok := p.OutputChanges[name]
+	if !ok || c == nil {
+		return cty.NilVal, cty.NilVal, fmt.Errorf("no change for output %q", name)
+	}
+
+	var prior, planned *StateOutput
+	if p.PriorState != nil && p.PriorState.Values != nil {
+		prior = p.PriorState.Values.Outputs[name]
+	}
+	if p.PlannedValues != nil {
+		planned = p.PlannedValues.Outputs[name]
+	}
+
+	var beforeType, afterType cty.Type
+	if prior != nil {
+		beforeType = prior.Type
+	}
+	if planned != nil {
+		afterType = planned.Type
+	}
+
+	before, after, err = c.CtyValues(beforeType, afterType)
+	if err != nil {
+		return cty.NilVal, cty.NilVal, fmt.Errorf("output %q: %w", name, err)
+	}
+
+	before = markSensitive(before, prior != nil && prior.Sensitive)
+	after = markSensitive(after, planned != nil && planned.Sensitive)
+
+	return before, after, nil
+}
+
+// DecodeCtyValue decodes val into the Go value pointed to by target using
+// gocty, after removing any marks. See gocty.FromCtyValue for the
+// supported target types. Unknown values cannot be decoded.
+func DecodeCtyValue(val cty.Value, target interface{}) error {
+	val, _ = val.UnmarkDeep()
+	if !val.IsWhollyKnown() {
+		return errors.New("value is not known")
+	}
+
+	return gocty.FromCtyValue(val, target)
+}
+
+// ctyValue converts a value decoded from JSON into a cty.Value of type ty,
+// or of a type implied by the value if ty is cty.NilType. The unknown and
+// sensitive arguments are either a bool applying to the whole value, or a
+// structure of the same shape as the value with true at each unknown or
+// sensitive position, as in Change.AfterUnknown.
+func ctyValue(value interface{}, ty cty.Type, unknown, sensitive interface{}) (cty.Value, error) {
+	if isTrue(unknown) {
+		if ty == cty.NilType {
+			ty = cty.DynamicPseudoType
+		}
+		return markSensitive(cty.UnknownVal(ty), isTrue(sensitive)), nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	if ty == cty.NilType {
+		if value == nil {
+			ty = cty.DynamicPseudoType
+		} else {
+			ty, err = ctyjson.ImpliedType(raw)
+			if err != nil {
+				return cty.NilVal, err
+			}
+		}
+	}
+
+	var val cty.Value
+	if value == nil {
+		val = cty.NullVal(ty)
+	} else {
+		val, err = ctyjson.Unmarshal(raw, ty)
+		if err != nil {
+			return cty.NilVal, err
+		}
+	}
+
+	if unknown == nil && sensitive == nil {
+		return val, nil
+	}
+
+	return cty.Transform(val, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		unknownAt := valueAtPath(unknown, val.Type(), path)
+		sensitiveAt := valueAtPath(sensitive, val.Type(), path)
+
+		// The elements of a set cannot be matched with the positions of
+		// the corresponding JSON array, so a set is unknown or sensitive
+		// as a whole if any of its elements are.
+		if v.Type().IsSetType() {
+			if containsTrue(unknownAt) {
+				unknownAt = true
+			}
+			if containsTrue(sensitiveAt) {
+				sensitiveAt = true
+			}
+		}
+
+		if isTrue(unknownAt) {
+			v = cty.UnknownVal(v.Type())
+		}
+
+		return markSensitive(v, isTrue(sensitiveAt)), nil
+	})
+}
+
+// valueAtPath returns the element of a structure decoded from JSON
+// corresponding to path within a value of type ty. Steps into sets, which
+// cannot be matched with the elements of the JSON structure, always
+// return nil.
+func valueAtPath(v interface{}, ty cty.Type, path cty.Path) interface{} {
+	for _, step := range path {
+		if v == nil {
+			return nil
+		}
+
+		switch s := step.(type) {
+		case cty.GetAttrStep:
+			m, ok := v.(map[string]interface{})
+			if !ok || !ty.IsObjectType() || !ty.HasAttribute(s.Name) {
+				return nil
+			}
+			v = m[s.Name]
+			ty = ty.AttributeType(s.Name)
+		case cty.IndexStep:
+			switch {
+			case ty.IsMapType() && s.Key.Type() == cty.String:
+				m, ok := v.(map[string]interface{})
+				if !ok {
+					return nil
+				}
+				v = m[s.Key.AsString()]
+				ty = ty.ElementType()
+			case (ty.IsListType() || ty.IsTupleType()) && s.Key.Type() == cty.Number:
+				l, ok := v.([]interface{})
+				if !ok {
+					return nil
+				}
+				i, accuracy := s.Key.AsBigFloat().Int64()
+				if accuracy != 0 || i < 0 || i >= int64(len(l)) {
+					return nil
+				}
+				v = l[i]
+				if ty.IsListType() {
+					ty = ty.ElementType()
+				} else {
+					ty = ty.TupleElementType(int(i))
+				}
+			default:
+				return nil
+			}
+		default:
+			return nil
+		}
+	}
+
+	return v
+}
+
+// containsTrue returns true if v, a structure decoded from JSON, is or
+// contains the value true.
+func containsTrue(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case []interface{}:
+		for _, e := range v {
+			if containsTrue(e) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, e := range v {
+			if containsTrue(e) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isTrue(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+func markSensitive(v cty.Value, sensitive bool) cty.Value {
+	if !sensitive || v.HasMark(SensitiveMark) {
+		return v
+	}
+
+	return v.Mark(SensitiveMark)
+}
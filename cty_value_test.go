@@ -0,0 +1,246 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestStateOutputCtyValue(t *testing.T) {
+	cases := []struct {
+		name     string
+		output   string
+		expected cty.Value
+	}{
+		{
+			name:   "set of numbers",
+			output: `{"sensitive":false,"type":["set","number"],"value":[1,12345678901234567890]}`,
+			expected: cty.SetVal([]cty.Value{
+				cty.NumberIntVal(1),
+				cty.MustParseNumberVal("12345678901234567890"),
+			}),
+		},
+		{
+			name:   "map",
+			output: `{"sensitive":false,"type":["map","string"],"value":{"a":"b"}}`,
+			expected: cty.MapVal(map[string]cty.Value{
+				"a": cty.StringVal("b"),
+			}),
+		},
+		{
+			name:     "sensitive",
+			output:   `{"sensitive":true,"type":"string","value":"hunter2"}`,
+			expected: cty.StringVal("hunter2").Mark(SensitiveMark),
+		},
+		{
+			name:     "null",
+			output:   `{"sensitive":false,"type":"string","value":null}`,
+			expected: cty.NullVal(cty.String),
+		},
+		{
+			name:   "no type",
+			output: `{"sensitive":false,"value":{"a":[1,"b"]}}`,
+			expected: cty.ObjectVal(map[string]cty.Value{
+				"a": cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.StringVal("b")}),
+			}),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Decode using the default float64 representation, which can
+			// not represent every number precisely.
+			var output StateOutput
+			if err := json.Unmarshal([]byte(tc.output), &output); err != nil {
+				t.Fatal(err)
+			}
+
+			// The typed value should be identical when using json.Number.
+			var state State
+			state.UseJSONNumber(true)
+			raw := `{"format_version":"1.0","values":{"outputs":{"o":` + tc.output + `}}}`
+			if err := json.Unmarshal([]byte(raw), &state); err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := state.Values.Outputs["o"].CtyValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, actual, ctydebug.CmpOptions); diff != "" {
+				t.Errorf("CtyValue() mismatch (-expected +actual):\n%s", diff)
+			}
+
+			if _, err := output.CtyValue(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestStateOutputDecode(t *testing.T) {
+	output := &StateOutput{
+		Sensitive: true,
+		Type: cty.Object(map[string]cty.Type{
+			"name":  cty.String,
+			"ports": cty.Set(cty.Number),
+		}),
+		Value: map[string]interface{}{
+			"name":  "web",
+			"ports": []interface{}{json.Number("80"), json.Number("443")},
+		},
+	}
+
+	type service struct {
+		Name  string `cty:"name"`
+		Ports []int  `cty:"ports"`
+	}
+
+	var actual service
+	if err := output.Decode(&actual); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := service{Name: "web", Ports: []int{80, 443}}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("Decode() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestChangeCtyValues(t *testing.T) {
+	change := &Change{
+		Actions: Actions{ActionUpdate},
+		Before: map[string]interface{}{
+			"id":       "a",
+			"password": "old",
+			"tags":     []interface{}{"x"},
+		},
+		After: map[string]interface{}{
+			"password": "new",
+			"tags":     []interface{}{"x", nil},
+		},
+		AfterUnknown: map[string]interface{}{
+			"id":   true,
+			"tags": []interface{}{false, true},
+		},
+		BeforeSensitive: map[string]interface{}{"password": true},
+		AfterSensitive:  map[string]interface{}{"password": true},
+	}
+
+	ty := cty.Object(map[string]cty.Type{
+		"id":       cty.String,
+		"password": cty.String,
+		"tags":     cty.List(cty.String),
+	})
+
+	before, after, err := change.CtyValues(ty, ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedBefore := cty.ObjectVal(map[string]cty.Value{
+		"id":       cty.StringVal("a"),
+		"password": cty.StringVal("old").Mark(SensitiveMark),
+		"tags":     cty.ListVal([]cty.Value{cty.StringVal("x")}),
+	})
+	expectedAfter := cty.ObjectVal(map[string]cty.Value{
+		"id":       cty.UnknownVal(cty.String),
+		"password": cty.StringVal("new").Mark(SensitiveMark),
+		"tags":     cty.ListVal([]cty.Value{cty.StringVal("x"), cty.UnknownVal(cty.String)}),
+	})
+
+	if diff := cmp.Diff(expectedBefore, before, ctydebug.CmpOptions); diff != "" {
+		t.Errorf("before mismatch (-expected +actual):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedAfter, after, ctydebug.CmpOptions); diff != "" {
+		t.Errorf("after mismatch (-expected +actual):\n%s", diff)
+	}
+
+	var target map[string]string
+	if err := DecodeCtyValue(after, &target); err == nil {
+		t.Fatal("expected error decoding unknown value")
+	}
+}
+
+func TestChangeCtyValues_sets(t *testing.T) {
+	change := &Change{
+		Actions: Actions{ActionCreate},
+		After: map[string]interface{}{
+			"secrets": []interface{}{"a", "b"},
+			"ports":   []interface{}{json.Number("80"), json.Number("443")},
+			"rules":   []interface{}{map[string]interface{}{"name": "x", "token": "t"}},
+			"tags":    []interface{}{"c"},
+		},
+		AfterUnknown: map[string]interface{}{
+			"ports": []interface{}{false, true},
+		},
+		AfterSensitive: map[string]interface{}{
+			"secrets": []interface{}{false, true},
+			"rules":   []interface{}{map[string]interface{}{"token": true}},
+			"tags":    []interface{}{false},
+		},
+	}
+
+	ty := cty.Object(map[string]cty.Type{
+		"secrets": cty.Set(cty.String),
+		"ports":   cty.Set(cty.Number),
+		"rules":   cty.Set(cty.Object(map[string]cty.Type{"name": cty.String, "token": cty.String})),
+		"tags":    cty.Set(cty.String),
+	})
+
+	_, after, err := change.CtyValues(cty.NilType, ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := cty.ObjectVal(map[string]cty.Value{
+		"secrets": cty.SetVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}).Mark(SensitiveMark),
+		"ports":   cty.UnknownVal(cty.Set(cty.Number)),
+		"rules": cty.SetVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("x"), "token": cty.StringVal("t")}),
+		}).Mark(SensitiveMark),
+		"tags": cty.SetVal([]cty.Value{cty.StringVal("c")}),
+	})
+	if !after.RawEquals(expected) {
+		t.Errorf("after mismatch\nexpected: %s\nactual:   %s", ctydebug.ValueString(expected), ctydebug.ValueString(after))
+	}
+}
+
+func TestPlanOutputChangeValues(t *testing.T) {
+	plan := testReadPlan(t, filepath.Join(testFixtureDir, "basic", testGoldenPlanFileName))
+
+	cases := map[string]cty.Value{
+		"foo":          cty.StringVal("bar").Mark(SensitiveMark),
+		"interpolated": cty.DynamicVal,
+		"list":         cty.TupleVal([]cty.Value{cty.StringVal("foo"), cty.StringVal("bar")}),
+		"map": cty.ObjectVal(map[string]cty.Value{
+			"foo":    cty.StringVal("bar"),
+			"number": cty.NumberIntVal(42),
+		}),
+	}
+
+	for name, expected := range cases {
+		before, after, err := plan.OutputChangeValues(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !before.IsNull() {
+			t.Errorf("%s: expected null before value, got %#v", name, before)
+		}
+		if diff := cmp.Diff(expected, after, ctydebug.CmpOptions); diff != "" {
+			t.Errorf("%s: after mismatch (-expected +actual):\n%s", name, diff)
+		}
+	}
+
+	if _, _, err := plan.OutputChangeValues("missing"); err == nil {
+		t.Fatal("expected error for missing output")
+	}
+}
@@ -0,0 +1,540 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// DecodeOptions control how documents such as Plan and State are decoded.
+// The options of a document also apply to the documents and other values
+// nested within it, such as the configuration and prior state of a Plan.
+//
+// Options are used by DecodePlan and the other Decode functions, or can be
+// set on a document prior to decoding it using encoding/json, for example
+// using Plan.SetDecodeOptions. MaxSize is only honored by the Decode
+// functions and DecodeOptions.Decode.
+type DecodeOptions struct {
+	// UseJSONNumber decodes numbers within values of arbitrary type, such
+	// as resource attributes, output values and the constant values of
+	// expressions, into json.Number rather than float64, so that they are
+	// not rounded.
+	UseJSONNumber bool
+
+	// CollectUnknownFields records the paths of JSON object members which
+	// have no corresponding field in this package, such as those added by
+	// a newer version of Terraform, which are otherwise silently
+	// discarded. The paths are returned by the UnknownFields method of
+	// the document, such as Plan.UnknownFields.
+	CollectUnknownFields bool
+
+	// PreserveUnknownFields retains JSON object members which have no
+	// corresponding field in this package with the objects containing
+	// them, and emits them again when those objects are encoded, after
+	// their known members. This allows documents to be modified and
+	// re-encoded without losing information.
+	//
+	// Retained members are emitted exactly as they were decoded, and are
+	// not redacted by the sanitize package. They are held by the value
+	// decoded from the object containing them, such as a ResourceChange,
+	// and so are emitted wherever that value is encoded, and discarded if
+	// it is removed.
+	PreserveUnknownFields bool
+
+	// DisallowUnknownFields causes decoding to fail with an
+	// *UnknownFieldsError if the document contains any JSON object members
+	// which have no corresponding field in this package.
+	DisallowUnknownFields bool
+
+	// SkipValidate skips the checks, such as those of Plan.Validate, which
+	// otherwise ensure that the format version of the document is
+	// supported by this package. Documents in unsupported formats may be
+	// decoded incorrectly.
+	SkipValidate bool
+
+	// MaxSize is the maximum size of the document in bytes. Decoding a
+	// larger document fails with an error wrapping ErrDocumentTooLarge.
+	// Zero means no limit.
+	MaxSize int64
+}
+
+// ErrDocumentTooLarge is returned, wrapped, when decoding a document which
+// is larger than DecodeOptions.MaxSize.
+var ErrDocumentTooLarge = errors.New("document too large")
+
+// UnknownFieldsError is returned when decoding a document with
+// DecodeOptions.DisallowUnknownFields set which contains JSON object
+// members that have no corresponding field in this package.
+type UnknownFieldsError struct {
+	// Fields are the paths of the members, as returned by
+	// Plan.UnknownFields.
+	Fields []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("unknown fields: %s", strings.Join(e.Fields, ", "))
+}
+
+// DecodePlan decodes the plan read from r, as produced by
+// "terraform show -json PLANFILE".
+func DecodePlan(r io.Reader, opts DecodeOptions) (*Plan, error) {
+	p := &Plan{decodeOptions: opts}
+	if err := opts.Decode(r, p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// DecodeState decodes the state read from r, as produced by
+// "terraform show -json".
+func DecodeState(r io.Reader, opts DecodeOptions) (*State, error) {
+	s := &State{decodeOptions: opts}
+	if err := opts.Decode(r, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// DecodeSchemas decodes the provider schemas read from r, as produced by
+// "terraform providers schema -json".
+func DecodeSchemas(r io.Reader, opts DecodeOptions) (*ProviderSchemas, error) {
+	p := &ProviderSchemas{decodeOptions: opts}
+	if err := opts.Decode(r, p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// DecodeValidate decodes the validation output read from r, as produced
+// by "terraform validate -json".
+func DecodeValidate(r io.Reader, opts DecodeOptions) (*ValidateOutput, error) {
+	vo := &ValidateOutput{decodeOptions: opts}
+	if err := opts.Decode(r, vo); err != nil {
+		return nil, err
+	}
+
+	return vo, nil
+}
+
+// DecodeMetadataFunctions decodes the function signatures read from r, as
+// produced by "terraform metadata functions -json".
+func DecodeMetadataFunctions(r io.Reader, opts DecodeOptions) (*MetadataFunctions, error) {
+	f := &MetadataFunctions{decodeOptions: opts}
+	if err := opts.Decode(r, f); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// DecodeOutputs decodes the root module outputs read from r, as produced
+// by "terraform output -json".
+func DecodeOutputs(r io.Reader, opts DecodeOptions) (*Outputs, error) {
+	o := &Outputs{decodeOptions: opts}
+	if err := opts.Decode(r, o); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// DecodeOutputValue decodes the single output value read from r, as
+// produced by "terraform output -json NAME".
+func DecodeOutputValue(r io.Reader, opts DecodeOptions) (*OutputValue, error) {
+	v := &OutputValue{decodeOptions: opts}
+	if err := opts.Decode(r, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// Decode reads a document from r, honoring MaxSize, and decodes it into v,
+// whose UnmarshalJSON method is expected to honor the other options, for
+// example by calling Unmarshal. It is used by the Decode functions of this
+// package, and by packages decoding related documents, such as tfstate.
+func (o DecodeOptions) Decode(r io.Reader, v json.Unmarshaler) error {
+	if o.MaxSize > 0 {
+		r = io.LimitReader(r, o.MaxSize+1)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if o.MaxSize > 0 && int64(len(b)) > o.MaxSize {
+		return fmt.Errorf("%w: exceeds %d bytes", ErrDocumentTooLarge, o.MaxSize)
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// Unmarshal decodes the JSON document b into v, which must be a pointer,
+// according to the options other than MaxSize and SkipValidate. Unknown
+// fields are the members of objects decoded into the struct types of this
+// module which have no corresponding field. Unmarshal returns their paths
+// if they are collected, or an *UnknownFieldsError if there are any and
+// they are disallowed. They can only be preserved by types of this package.
+func (o DecodeOptions) Unmarshal(b []byte, v interface{}) ([]string, error) {
+	// Invalid documents are rejected up front, with the error encoding/json
+	// reports for them, so that they need not be handled while decoding.
+	if !json.Valid(b) {
+		return nil, json.Unmarshal(b, &discardJSONValue{})
+	}
+
+	d := &jsonDecoder{opts: o}
+	if err := d.decode(b, v); err != nil {
+		return nil, err
+	}
+
+	return d.unknownFieldsResult()
+}
+
+// validate validates the document v unless SkipValidate is set.
+func (o DecodeOptions) validate(v interface{ Validate() error }) error {
+	if o.SkipValidate {
+		return nil
+	}
+
+	return v.Validate()
+}
+
+// optionsUnmarshaler is implemented by the types of this package which
+// implement json.Unmarshaler and may be nested within a document, such as
+// Config and Expression. When decoding a document, they are decoded by
+// unmarshalJSONOptions rather than UnmarshalJSON, so that the options of
+// the document apply to them too.
+type optionsUnmarshaler interface {
+	unmarshalJSONOptions(d *jsonDecoder, b []byte) error
+}
+
+var (
+	optionsUnmarshalerType = reflect.TypeOf((*optionsUnmarshaler)(nil)).Elem()
+	unmarshalerType        = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// jsonDecoder decodes a single JSON document according to DecodeOptions.
+//
+// Values which may contain a type implementing optionsUnmarshaler, and, if
+// unknown fields are being handled, structs of this module, are decoded
+// by walking the members and elements of their objects and arrays. All
+// other values are decoded using encoding/json.
+type jsonDecoder struct {
+	opts DecodeOptions
+
+	// path is the path to the value being decoded, tracked only when
+	// unknown fields are collected or disallowed.
+	path []jsonPathStep
+
+	// unknownFields are the paths of the unknown fields found so far.
+	unknownFields [][]jsonPathStep
+}
+
+// decode decodes the JSON value b into v, which must be a pointer.
+func (d *jsonDecoder) decode(b []byte, v interface{}) error {
+	return d.value(bytes.TrimSpace(b), reflect.ValueOf(v).Elem())
+}
+
+// trackPaths returns true if the paths of unknown fields are needed.
+func (d *jsonDecoder) trackPaths() bool {
+	return d.opts.CollectUnknownFields || d.opts.DisallowUnknownFields
+}
+
+// unknownFieldsHandled returns true if unknown fields are being handled.
+func (d *jsonDecoder) unknownFieldsHandled() bool {
+	return d.trackPaths() || d.opts.PreserveUnknownFields
+}
+
+// unknownFieldsResult returns the paths of the unknown fields found if
+// they are collected, or an *UnknownFieldsError if there are any and they
+// are disallowed.
+func (d *jsonDecoder) unknownFieldsResult() ([]string, error) {
+	if !d.trackPaths() {
+		return nil, nil
+	}
+
+	var paths []string
+	for _, path := range d.unknownFields {
+		paths = append(paths, formatJSONPath(path))
+	}
+	if d.opts.DisallowUnknownFields && len(paths) > 0 {
+		return nil, &UnknownFieldsError{Fields: paths}
+	}
+	if !d.opts.CollectUnknownFields {
+		paths = nil
+	}
+
+	return paths, nil
+}
+
+// value decodes the JSON value b into v, which must be addressable.
+func (d *jsonDecoder) value(b []byte, v reflect.Value) error {
+	if u, ok := v.Addr().Interface().(optionsUnmarshaler); ok {
+		return u.unmarshalJSONOptions(d, b)
+	}
+	if !walkJSONType(v.Type(), d.unknownFieldsHandled()) {
+		return d.leaf(b, v)
+	}
+
+	null := string(b) == "null"
+	switch v.Kind() {
+	case reflect.Pointer:
+		if null {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return d.value(b, v.Elem())
+	case reflect.Struct:
+		if null {
+			return nil
+		}
+		if b[0] != '{' {
+			return d.leaf(b, v)
+		}
+
+		return d.object(b, v)
+	case reflect.Map:
+		if null {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if b[0] != '{' || v.Type().Key().Kind() != reflect.String {
+			return d.leaf(b, v)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+
+		return eachJSONMember(b, func(name string, value []byte) error {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			err := d.member(jsonPathStep{kind: jsonPathKey, name: name}, value, elem)
+			if err != nil {
+				return err
+			}
+
+			v.SetMapIndex(reflect.ValueOf(name).Convert(v.Type().Key()), elem)
+			return nil
+		})
+	case reflect.Slice:
+		if null {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if b[0] != '[' {
+			return d.leaf(b, v)
+		}
+
+		var elems [][]byte
+		if err := eachJSONElem(b, func(value []byte) error {
+			elems = append(elems, value)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		v.Set(reflect.MakeSlice(v.Type(), len(elems), len(elems)))
+		for i, value := range elems {
+			if err := d.member(jsonPathStep{kind: jsonPathIndex, index: i}, value, v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return d.leaf(b, v)
+}
+
+// object decodes the JSON object b into the struct v, recording or
+// preserving any members which have no corresponding field.
+func (d *jsonDecoder) object(b []byte, v reflect.Value) error {
+	typ := v.Type()
+
+	var unknown []*jsonMember
+	err := eachJSONMember(b, func(name string, value []byte) error {
+		step := jsonPathStep{kind: jsonPathField, name: name}
+
+		index, ok := jsonFieldIndex(typ, name)
+		if !ok {
+			if d.trackPaths() {
+				d.unknownFields = append(d.unknownFields, appendJSONPath(d.path, step))
+			}
+			if d.opts.PreserveUnknownFields {
+				// The value is copied, as b may be reused once decoding
+				// is complete.
+				raw := append([]byte(nil), value...)
+				unknown = append(unknown, &jsonMember{name: name, value: &jsonNode{raw: raw}})
+			}
+			return nil
+		}
+
+		return d.member(step, value, jsonField(v, index))
+	})
+	if err != nil {
+		return err
+	}
+
+	if d.opts.PreserveUnknownFields {
+		if h, ok := v.Addr().Interface().(unknownMembersHolder); ok {
+			h.setUnknownMembers(unknown)
+		}
+	}
+
+	return nil
+}
+
+// member decodes the JSON value b, found at step within the value being
+// decoded, into v.
+func (d *jsonDecoder) member(step jsonPathStep, b []byte, v reflect.Value) error {
+	if !d.trackPaths() {
+		return d.value(b, v)
+	}
+
+	d.path = append(d.path, step)
+	err := d.value(b, v)
+	d.path = d.path[:len(d.path)-1]
+
+	return err
+}
+
+// leaf decodes the JSON value b into v using encoding/json.
+func (d *jsonDecoder) leaf(b []byte, v reflect.Value) error {
+	if !d.opts.UseJSONNumber || !hasInterfaceValue(v.Type()) {
+		return json.Unmarshal(b, v.Addr().Interface())
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v.Addr().Interface())
+}
+
+// jsonField returns the field of the struct v with the given index,
+// allocating any nil embedded pointers it is promoted through, as
+// encoding/json does.
+func jsonField(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+type walkJSONTypeKey struct {
+	typ           reflect.Type
+	unknownFields bool
+}
+
+var walkJSONTypeCache sync.Map
+
+// walkJSONType returns true if values of typ must be decoded by
+// jsonDecoder walking them, rather than by encoding/json, because they may
+// contain a value of a type which implements optionsUnmarshaler or, if
+// unknownFields is true, of a struct type of this module.
+func walkJSONType(typ reflect.Type, unknownFields bool) bool {
+	key := walkJSONTypeKey{typ: typ, unknownFields: unknownFields}
+	if result, ok := walkJSONTypeCache.Load(key); ok {
+		return result.(bool)
+	}
+
+	result := findWalkJSONType(typ, unknownFields, map[reflect.Type]bool{})
+	walkJSONTypeCache.Store(key, result)
+
+	return result
+}
+
+func findWalkJSONType(typ reflect.Type, unknownFields bool, seen map[reflect.Type]bool) bool {
+	if seen[typ] {
+		return false
+	}
+	seen[typ] = true
+
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		return findWalkJSONType(typ.Elem(), unknownFields, seen)
+	case reflect.Struct:
+		if !inModule(typ) {
+			return false
+		}
+		if unknownFields || reflect.PointerTo(typ).Implements(optionsUnmarshalerType) {
+			return true
+		}
+
+		for i := 0; i < typ.NumField(); i++ {
+			if findWalkJSONType(typ.Field(i).Type, unknownFields, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// inModule returns true if typ is declared by this package, or by another
+// package of this module such as tfstate.
+func inModule(typ reflect.Type) bool {
+	pkg := typ.PkgPath()
+	return pkg == tfjsonPkgPath || strings.HasPrefix(pkg, tfjsonPkgPath+"/")
+}
+
+var interfaceValueCache sync.Map
+
+// hasInterfaceValue returns true if values of typ may contain a value of
+// interface type, which encoding/json decodes numbers within according to
+// json.Decoder.UseNumber.
+func hasInterfaceValue(typ reflect.Type) bool {
+	if result, ok := interfaceValueCache.Load(typ); ok {
+		return result.(bool)
+	}
+
+	result := findInterfaceValue(typ, map[reflect.Type]bool{})
+	interfaceValueCache.Store(typ, result)
+
+	return result
+}
+
+func findInterfaceValue(typ reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[typ] {
+		return false
+	}
+	seen[typ] = true
+
+	switch typ.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return findInterfaceValue(typ.Elem(), seen)
+	case reflect.Struct:
+		if reflect.PointerTo(typ).Implements(unmarshalerType) {
+			return false
+		}
+
+		for i := 0; i < typ.NumField(); i++ {
+			if findInterfaceValue(typ.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,326 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testNestedNumbersPlan = `{
+  "format_version": "1.2",
+  "planned_values": {
+    "root_module": {
+      "resources": [{"address": "example.test", "values": {"size": 12345678901234567891}}]
+    }
+  },
+  "prior_state": {
+    "format_version": "1.0",
+    "values": {
+      "outputs": {"id": {"sensitive": false, "value": 12345678901234567892}},
+      "root_module": {
+        "resources": [{"address": "example.test", "values": {"size": 12345678901234567893, "tags": {"port": 8080}}}]
+      }
+    }
+  },
+  "configuration": {
+    "root_module": {
+      "resources": [{
+        "address": "example.test",
+        "expressions": {
+          "size": {"constant_value": 12345678901234567894},
+          "ref": {"references": ["var.size"]},
+          "rule": [{"port": {"constant_value": [12345678901234567895]}}]
+        }
+      }],
+      "variables": {"size": {"default": 12345678901234567896}}
+    }
+  }
+}`
+
+func TestPlan_useJSONNumberNested(t *testing.T) {
+	testCases := map[string]struct {
+		opts     DecodeOptions
+		expected []interface{}
+	}{
+		"float64": {
+			DecodeOptions{},
+			[]interface{}{
+				1.2345678901234567891e19,
+				1.2345678901234567892e19,
+				1.2345678901234567893e19,
+				8080.0,
+				1.2345678901234567894e19,
+				1.2345678901234567895e19,
+				1.2345678901234567896e19,
+			},
+		},
+		"json.Number": {
+			DecodeOptions{UseJSONNumber: true},
+			[]interface{}{
+				json.Number("12345678901234567891"),
+				json.Number("12345678901234567892"),
+				json.Number("12345678901234567893"),
+				json.Number("8080"),
+				json.Number("12345678901234567894"),
+				json.Number("12345678901234567895"),
+				json.Number("12345678901234567896"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var plan Plan
+			plan.SetDecodeOptions(tc.opts)
+			if err := json.Unmarshal([]byte(testNestedNumbersPlan), &plan); err != nil {
+				t.Fatal(err)
+			}
+
+			priorValues := plan.PriorState.Values.RootModule.Resources[0].AttributeValues
+			expressions := plan.Config.RootModule.Resources[0].Expressions
+			actual := []interface{}{
+				plan.PlannedValues.RootModule.Resources[0].AttributeValues["size"],
+				plan.PriorState.Values.Outputs["id"].Value,
+				priorValues["size"],
+				priorValues["tags"].(map[string]interface{})["port"],
+				expressions["size"].ConstantValue,
+				expressions["rule"].NestedBlocks[0]["port"].ConstantValue.([]interface{})[0],
+				plan.Config.RootModule.Variables["size"].Default,
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Fatalf("unexpected values: %s", diff)
+			}
+
+			if expressions["ref"].ConstantValue != UnknownConstantValue {
+				t.Fatalf("expected unknown constant value, got %#v", expressions["ref"].ConstantValue)
+			}
+		})
+	}
+}
+
+func TestDecodePlan(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(testFixtureDir, "basic", testGoldenPlanFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DecodeOptions{MaxSize: int64(len(b))}
+
+	var expected Plan
+	expected.SetDecodeOptions(opts)
+	if err := json.Unmarshal(b, &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := DecodePlan(bytes.NewReader(b), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(&expected, actual, copyCmpOpts); diff != "" {
+		t.Fatalf("unexpected difference: %s", diff)
+	}
+}
+
+func TestDecodePlan_options(t *testing.T) {
+	testCases := map[string]struct {
+		raw         string
+		opts        DecodeOptions
+		expectedErr string
+	}{
+		"too large": {
+			raw:         `{"format_version":"1.2"}`,
+			opts:        DecodeOptions{MaxSize: 10},
+			expectedErr: "document too large: exceeds 10 bytes",
+		},
+		"unknown fields": {
+			raw:         `{"format_version":"1.2","new_field":1,"resource_changes":[{"address":"null_resource.foo","new_field":2}]}`,
+			opts:        DecodeOptions{DisallowUnknownFields: true},
+			expectedErr: "unknown fields: new_field, resource_changes[0].new_field",
+		},
+		"unsupported version": {
+			raw:         `{"format_version":"2.0"}`,
+			expectedErr: `unsupported plan format version: "2.0.0" does not satisfy ">= 0.1, < 2.0"`,
+		},
+		"skip validate": {
+			raw:  `{"format_version":"2.0"}`,
+			opts: DecodeOptions{SkipValidate: true},
+		},
+		"unsupported prior state version": {
+			raw:         `{"format_version":"1.2","prior_state":{"format_version":"2.0"}}`,
+			expectedErr: `unsupported state format version: "2.0.0" does not satisfy ">= 0.1, < 2.0"`,
+		},
+		"skip validate prior state": {
+			raw:  `{"format_version":"1.2","prior_state":{"format_version":"2.0"}}`,
+			opts: DecodeOptions{SkipValidate: true},
+		},
+		"unknown fields in expression": {
+			raw:         `{"format_version":"1.2","configuration":{"root_module":{"outputs":{"foo":{"expression":{"constant_value":1,"new_field":2}}}}}}`,
+			opts:        DecodeOptions{DisallowUnknownFields: true},
+			expectedErr: `unknown fields: configuration.root_module.outputs["foo"].expression.new_field`,
+		},
+		"invalid": {
+			raw:         `{"format_version":"1.2"} {}`,
+			expectedErr: "invalid character '{' after top-level value",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := DecodePlan(strings.NewReader(tc.raw), tc.opts)
+			switch {
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("expected error %q", tc.expectedErr)
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("unexpected error: %s", err)
+			case err != nil && err.Error() != tc.expectedErr:
+				t.Fatalf("expected error %q, got %q", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestDecodePlan_errorTypes(t *testing.T) {
+	_, err := DecodePlan(strings.NewReader(`{"format_version":"1.2"}`), DecodeOptions{MaxSize: 1})
+	if !errors.Is(err, ErrDocumentTooLarge) {
+		t.Fatalf("expected ErrDocumentTooLarge, got %v", err)
+	}
+
+	_, err = DecodePlan(strings.NewReader(`{"format_version":"1.2","new_field":1}`), DecodeOptions{DisallowUnknownFields: true})
+	var unknownErr *UnknownFieldsError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("expected *UnknownFieldsError, got %v", err)
+	}
+	if diff := cmp.Diff([]string{"new_field"}, unknownErr.Fields); diff != "" {
+		t.Fatalf("unexpected fields: %s", diff)
+	}
+}
+
+func TestDecode_documents(t *testing.T) {
+	opts := DecodeOptions{DisallowUnknownFields: true, UseJSONNumber: true}
+
+	t.Run("state", func(t *testing.T) {
+		f := testOpenFixture(t, "no_changes", testGoldenStateFileName)
+		if _, err := DecodeState(f, opts); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("schemas", func(t *testing.T) {
+		f := testOpenFixture(t, "basic", testGoldenSchemasFileName)
+		if _, err := DecodeSchemas(f, opts); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("metadata functions", func(t *testing.T) {
+		f := testOpenFixture(t, "basic", "functions.json")
+		if _, err := DecodeMetadataFunctions(f, opts); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("validate", func(t *testing.T) {
+		vo, err := DecodeValidate(strings.NewReader(`{"format_version":"1.0","valid":true,"error_count":0,"warning_count":0,"diagnostics":[]}`), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !vo.Valid {
+			t.Fatal("expected valid output")
+		}
+	})
+	t.Run("outputs", func(t *testing.T) {
+		f := testOpenFixture(t, "basic", testGoldenOutputsFileName)
+		o, err := DecodeOutputs(f, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := o.Outputs["map"].Value.(map[string]interface{})["number"].(json.Number); !ok {
+			t.Fatalf("expected json.Number, got %#v", o.Outputs["map"].Value)
+		}
+	})
+	t.Run("output value", func(t *testing.T) {
+		v, err := DecodeOutputValue(strings.NewReader(`12345678901234567890`), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Value != json.Number("12345678901234567890") {
+			t.Fatalf("expected json.Number, got %#v", v.Value)
+		}
+	})
+}
+
+func TestDecodeOutputs_options(t *testing.T) {
+	const input = `{"foo":{"sensitive":false,"type":"string","value":"bar","new_field":true}}`
+
+	if _, err := DecodeOutputs(strings.NewReader(input), DecodeOptions{MaxSize: 10}); !errors.Is(err, ErrDocumentTooLarge) {
+		t.Fatalf("expected ErrDocumentTooLarge, got %v", err)
+	}
+	if _, err := DecodeOutputValue(strings.NewReader(input), DecodeOptions{MaxSize: 10}); !errors.Is(err, ErrDocumentTooLarge) {
+		t.Fatalf("expected ErrDocumentTooLarge, got %v", err)
+	}
+
+	var unknownErr *UnknownFieldsError
+	_, err := DecodeOutputs(strings.NewReader(input), DecodeOptions{DisallowUnknownFields: true})
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("expected *UnknownFieldsError, got %v", err)
+	}
+	if diff := cmp.Diff([]string{`["foo"].new_field`}, unknownErr.Fields); diff != "" {
+		t.Fatalf("unexpected fields (-expected +actual):\n%s", diff)
+	}
+
+	o, err := DecodeOutputs(strings.NewReader(input), DecodeOptions{CollectUnknownFields: true, PreserveUnknownFields: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{`["foo"].new_field`}, o.UnknownFields()); diff != "" {
+		t.Fatalf("unexpected fields (-expected +actual):\n%s", diff)
+	}
+
+	b, err := json.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte(`"new_field":true`)) {
+		t.Fatalf("expected new_field to be preserved, got %s", b)
+	}
+}
+
+func testOpenFixture(t *testing.T, dir, filename string) *os.File {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join(testFixtureDir, dir, filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	return f
+}
+
+func BenchmarkDecodePlan(b *testing.B) {
+	data := testLargePlan(b, filepath.Join(testFixtureDir, "has_changes", testGoldenPlanFileName), 100)
+
+	for name, opts := range map[string]DecodeOptions{
+		"default":        {},
+		"json.Number":    {UseJSONNumber: true},
+		"unknown fields": {CollectUnknownFields: true, PreserveUnknownFields: true},
+		"all":            {UseJSONNumber: true, CollectUnknownFields: true, PreserveUnknownFields: true},
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := DecodePlan(bytes.NewReader(data), opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
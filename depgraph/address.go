@@ -0,0 +1,106 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package depgraph
+
+import (
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// splitResourceAddress splits a resource instance address into the module
+// calls it is nested within and a reference to the resource. The final
+// two steps always belong to the resource, even if they are named
+// "module".
+func splitResourceAddress(address string) ([]*tfjson.Reference, *tfjson.Reference, error) {
+	modules, rest, err := tfjson.SplitModuleAddress(address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if rest == "" && len(modules) > 0 {
+		// A resource of type "module" is indistinguishable from a
+		// module call.
+		rest = modules[len(modules)-1].Raw
+		modules = modules[:len(modules)-1]
+	}
+
+	resource, err := tfjson.ParseReference(rest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return modules, resource, nil
+}
+
+// stripInstanceKeys converts a resource instance address into the address
+// of the resource in configuration, for example converting
+// `module.a["x"].aws_instance.b[0]` into `module.a.aws_instance.b`.
+// Addresses which cannot be parsed are returned unchanged.
+func stripInstanceKeys(address string) string {
+	modules, resource, err := splitResourceAddress(address)
+	if err != nil {
+		return address
+	}
+
+	var result []string
+	for _, m := range modules {
+		result = append(result, m.Subject())
+	}
+
+	return strings.Join(append(result, resource.Subject()), ".")
+}
+
+// moduleAddressOf returns the module portion of a resource address, for
+// example `module.a["x"]` for `module.a["x"].aws_instance.b[0]`.
+func moduleAddressOf(address string) string {
+	modules, _, err := splitResourceAddress(address)
+	if err != nil {
+		return ""
+	}
+
+	result := make([]string, len(modules))
+	for i, m := range modules {
+		result[i] = m.Raw
+	}
+
+	return strings.Join(result, ".")
+}
+
+// referenceTarget returns the address of the configuration object that
+// a reference found in module refers to, relative to the root module. It
+// returns false for references to objects which are not represented in
+// a graph, such as local values and count.index.
+//
+// A reference to a whole module call, such as "module.network", returns
+// the address of the module call.
+func referenceTarget(module, reference string) (string, bool) {
+	ref, err := tfjson.ParseReference(reference)
+	if err != nil {
+		return "", false
+	}
+
+	var target string
+	switch ref.Kind {
+	case tfjson.ReferenceKindVariable, tfjson.ReferenceKindModule,
+		tfjson.ReferenceKindResource, tfjson.ReferenceKindDataSource,
+		tfjson.ReferenceKindEphemeralResource, tfjson.ReferenceKindAction:
+		target = ref.Subject()
+	case tfjson.ReferenceKindModuleOutput:
+		target = "module." + ref.Name + ".output." + ref.Output
+	default:
+		return "", false
+	}
+
+	return qualify(module, target), true
+}
+
+// qualify prefixes a relative address with the module address, if any.
+func qualify(module, address string) string {
+	if module == "" {
+		return address
+	}
+
+	return module + "." + address
+}
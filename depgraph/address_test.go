@@ -0,0 +1,66 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package depgraph
+
+import "testing"
+
+func TestStripInstanceKeys(t *testing.T) {
+	cases := map[string]string{
+		"aws_instance.web":                          "aws_instance.web",
+		"aws_instance.web[0]":                       "aws_instance.web",
+		`module.a["x.y"].aws_instance.web["k]"]`:    "module.a.aws_instance.web",
+		`module.a[0].module.b["c"].data.d.e`:        "module.a.module.b.data.d.e",
+		`module.a["quote \" ] ."].null_resource.nr`: "module.a.null_resource.nr",
+	}
+
+	for input, expected := range cases {
+		if actual := stripInstanceKeys(input); actual != expected {
+			t.Errorf("stripInstanceKeys(%q) = %q, expected %q", input, actual, expected)
+		}
+	}
+}
+
+func TestModuleAddressOf(t *testing.T) {
+	cases := map[string]string{
+		"aws_instance.web":                          "",
+		`module.a["x.y"].aws_instance.web[0]`:       `module.a["x.y"]`,
+		`module.a[0].module.b.data.d.e`:             "module.a[0].module.b",
+		`module.a["k"].module.module.module.module`: `module.a["k"].module.module`,
+	}
+
+	for input, expected := range cases {
+		if actual := moduleAddressOf(input); actual != expected {
+			t.Errorf("moduleAddressOf(%q) = %q, expected %q", input, actual, expected)
+		}
+	}
+}
+
+func TestReferenceTarget(t *testing.T) {
+	cases := []struct {
+		module, reference string
+		expected          string
+		ok                bool
+	}{
+		{"", "var.region", "var.region", true},
+		{"module.a", "var.region", "module.a.var.region", true},
+		{"", "aws_instance.web.id", "aws_instance.web", true},
+		{"", "aws_instance.web[0].id", "aws_instance.web", true},
+		{"", "data.aws_ami.ubuntu.id", "data.aws_ami.ubuntu", true},
+		{"", "module.net.vpc_id", "module.net.output.vpc_id", true},
+		{"", `module.net["a"].vpc_id`, "module.net.output.vpc_id", true},
+		{"module.a", "module.net", "module.a.module.net", true},
+		{"", "local.name", "", false},
+		{"", "each.value", "", false},
+		{"", "count.index", "", false},
+		{"", "path.module", "", false},
+		{"", "var", "", false},
+	}
+
+	for _, tc := range cases {
+		actual, ok := referenceTarget(tc.module, tc.reference)
+		if actual != tc.expected || ok != tc.ok {
+			t.Errorf("referenceTarget(%q, %q) = %q, %t, expected %q, %t", tc.module, tc.reference, actual, ok, tc.expected, tc.ok)
+		}
+	}
+}
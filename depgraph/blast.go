@@ -0,0 +1,282 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package depgraph
+
+import (
+	"errors"
+	"sort"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// RiskLevel is a coarse classification of a RiskScore.
+type RiskLevel string
+
+const (
+	RiskLow    RiskLevel = "low"
+	RiskMedium RiskLevel = "medium"
+	RiskHigh   RiskLevel = "high"
+)
+
+// Impact describes the blast radius of a single resource instance that a
+// plan will destroy, either outright or as part of a replacement.
+type Impact struct {
+	// Address is the address of the resource instance being destroyed.
+	Address string
+
+	// Actions and ActionReason are those of the resource change.
+	Actions      tfjson.Actions
+	ActionReason tfjson.ActionReason
+
+	// Dependents lists every resource instance and output that depends
+	// on the destroyed object, directly or indirectly, ordered by
+	// address. During apply these will either be updated to refer to the
+	// replacement object, and so see new or unknown values, or lose the
+	// object they depend on.
+	Dependents []Dependent
+
+	// RiskScore is an estimate between 0 and 100 of how risky the
+	// change is. See BlastRadius for how it is calculated.
+	RiskScore int
+}
+
+// RiskLevel classifies the RiskScore as low (below 40), medium (below
+// 70) or high.
+func (i Impact) RiskLevel() RiskLevel {
+	switch {
+	case i.RiskScore < 40:
+		return RiskLow
+	case i.RiskScore < 70:
+		return RiskMedium
+	}
+
+	return RiskHigh
+}
+
+// Dependent is a single object affected by the destruction of another.
+type Dependent struct {
+	// Address is the resource instance address or output address of the
+	// dependent object. For resources which have no instances in the
+	// plan, such as those whose count is not yet known, it is the
+	// address of the resource in configuration.
+	Address string
+
+	// Kind is the kind of object.
+	Kind NodeKind
+
+	// Distance is the length of the shortest chain of dependencies from
+	// the dependent object to the destroyed object, where 1 is a direct
+	// dependency.
+	Distance int
+
+	// Actions are the planned actions for the dependent resource
+	// instance, if the plan has a change for it.
+	Actions tfjson.Actions
+}
+
+// BlastRadius returns an Impact for each resource change in p which will
+// destroy an existing object, in the order of p.ResourceChanges.
+// Changes to deposed objects are not included, as nothing can depend on
+// them.
+//
+// Dependents are found by following references and depends_on arguments
+// in p.Config, and the dependencies recorded in p.PriorState. Either may
+// be absent, in which case only the other is used.
+//
+// RiskScore starts from a base determined by the actions: 60 for a
+// delete, 50 for a replacement which destroys before creating, and 30
+// for one which creates before destroying. It is then adjusted by the
+// action reason, increasing it for reasons suggesting the destruction was
+// not intended (replace_because_cannot_update, delete_because_no_module,
+// delete_because_no_move_target and similar) and decreasing it where it
+// was explicitly requested or the object is already broken
+// (replace_by_request, replace_because_tainted). Finally, 5 is added for
+// each dependent resource instance, up to 25, and 5 for any affected
+// output. The result is clamped to the range 0 to 100.
+func BlastRadius(p *tfjson.Plan) ([]Impact, error) {
+	if p == nil {
+		return nil, errors.New("nil plan supplied")
+	}
+
+	configGraph := FromConfig(p.Config)
+	stateGraph := FromState(p.PriorState)
+
+	actions := map[string]tfjson.Actions{}
+	instances := map[string][]string{}
+	addInstance := func(address string) {
+		if _, ok := actions[address]; ok {
+			return
+		}
+		actions[address] = nil
+		resource := stripInstanceKeys(address)
+		instances[resource] = append(instances[resource], address)
+	}
+	for _, rc := range p.ResourceChanges {
+		if rc == nil || rc.DeposedKey != "" {
+			continue
+		}
+		addInstance(rc.Address)
+		if rc.Change != nil {
+			actions[rc.Address] = rc.Change.Actions
+		}
+	}
+	p.PriorState.Walk(func(_ *tfjson.StateModule, r *tfjson.StateResource) bool {
+		if r.DeposedKey == "" {
+			addInstance(r.Address)
+		}
+		return true
+	})
+
+	result := []Impact{}
+	for _, rc := range p.ResourceChanges {
+		if rc == nil || rc.Change == nil || rc.DeposedKey != "" {
+			continue
+		}
+		if !rc.Change.Actions.Delete() && !rc.Change.Actions.Replace() {
+			continue
+		}
+
+		impact := Impact{
+			Address:      rc.Address,
+			Actions:      rc.Change.Actions,
+			ActionReason: rc.ActionReason,
+		}
+
+		resource := stripInstanceKeys(rc.Address)
+		distances := map[string]int{}
+		record := func(address string, distance int) {
+			if d, ok := distances[address]; !ok || distance < d {
+				distances[address] = distance
+			}
+		}
+
+		for address, distance := range dependentDistances(configGraph, resource) {
+			n := configGraph.Node(address)
+			switch n.Kind {
+			case KindResource, KindDataSource:
+				if address == resource {
+					continue
+				}
+				if len(instances[address]) == 0 {
+					record(address, distance)
+				}
+				for _, instance := range instances[address] {
+					record(instance, distance)
+				}
+			case KindOutput:
+				record(address, distance)
+			}
+		}
+		for address, distance := range dependentDistances(stateGraph, rc.Address) {
+			if stripInstanceKeys(address) != resource {
+				record(address, distance)
+			}
+		}
+
+		for address, distance := range distances {
+			d := Dependent{
+				Address:  address,
+				Kind:     KindResource,
+				Distance: distance,
+				Actions:  actions[address],
+			}
+			if n := configGraph.Node(address); n != nil {
+				d.Kind = n.Kind
+			} else if n := stateGraph.Node(address); n != nil {
+				d.Kind = n.Kind
+			} else if n := configGraph.Node(stripInstanceKeys(address)); n != nil {
+				d.Kind = n.Kind
+			}
+			impact.Dependents = append(impact.Dependents, d)
+		}
+		sort.Slice(impact.Dependents, func(i, j int) bool {
+			return impact.Dependents[i].Address < impact.Dependents[j].Address
+		})
+
+		impact.RiskScore = riskScore(impact)
+		result = append(result, impact)
+	}
+
+	return result, nil
+}
+
+// dependentDistances returns the shortest distance to each node which
+// depends on the node at address, directly or indirectly.
+func dependentDistances(g *Graph, address string) map[string]int {
+	result := map[string]int{}
+	if g.Node(address) == nil {
+		return result
+	}
+
+	queue := []string{address}
+	distance := map[string]int{address: 0}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, next := range g.DependentsOf(current) {
+			if _, ok := distance[next]; ok {
+				continue
+			}
+			distance[next] = distance[current] + 1
+			result[next] = distance[next]
+			queue = append(queue, next)
+		}
+	}
+
+	return result
+}
+
+// riskReasonAdjustments adjusts the base risk score of a destructive
+// change according to its reason.
+var riskReasonAdjustments = map[tfjson.ActionReason]int{
+	tfjson.ActionReasonReplaceBecauseCannotUpdate:    20,
+	tfjson.ActionReasonReplaceByTriggers:             10,
+	tfjson.ActionReasonReplaceBecauseTainted:         -10,
+	tfjson.ActionReasonReplaceByRequest:              -20,
+	tfjson.ActionReasonDeleteBecauseNoResourceConfig: 10,
+	tfjson.ActionReasonDeleteBecauseWrongRepetition:  10,
+	tfjson.ActionReasonDeleteBecauseNoModule:         15,
+	tfjson.ActionReasonDeleteBecauseNoMoveTarget:     20,
+}
+
+func riskScore(impact Impact) int {
+	var score int
+	switch {
+	case impact.Actions.Delete():
+		score = 60
+	case impact.Actions.DestroyBeforeCreate():
+		score = 50
+	default:
+		score = 30
+	}
+
+	score += riskReasonAdjustments[impact.ActionReason]
+
+	resources, outputs := 0, false
+	for _, d := range impact.Dependents {
+		switch d.Kind {
+		case KindOutput:
+			outputs = true
+		default:
+			resources++
+		}
+	}
+	if resources > 5 {
+		resources = 5
+	}
+	score += resources * 5
+	if outputs {
+		score += 5
+	}
+
+	switch {
+	case score < 0:
+		return 0
+	case score > 100:
+		return 100
+	}
+
+	return score
+}
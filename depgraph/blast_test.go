@@ -0,0 +1,155 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func testBlastPlan() *tfjson.Plan {
+	return &tfjson.Plan{
+		Config: &tfjson.Config{
+			RootModule: &tfjson.ConfigModule{
+				Resources: []*tfjson.ConfigResource{
+					{Address: "aws_vpc.main", Mode: tfjson.ManagedResourceMode},
+					{
+						Address: "aws_subnet.a",
+						Mode:    tfjson.ManagedResourceMode,
+						Expressions: map[string]*tfjson.Expression{
+							"vpc_id": refs("aws_vpc.main.id", "aws_vpc.main"),
+						},
+					},
+					{
+						Address: "aws_instance.web",
+						Mode:    tfjson.ManagedResourceMode,
+						Expressions: map[string]*tfjson.Expression{
+							"subnet_id": refs("aws_subnet.a.id", "aws_subnet.a"),
+						},
+					},
+					{Address: "aws_s3_bucket.logs", Mode: tfjson.ManagedResourceMode},
+				},
+				Outputs: map[string]*tfjson.ConfigOutput{
+					"web_ip": {Expression: refs("aws_instance.web[0].private_ip", "aws_instance.web[0]", "aws_instance.web")},
+				},
+			},
+		},
+		PriorState: &tfjson.State{
+			Values: &tfjson.StateValues{
+				RootModule: &tfjson.StateModule{
+					Resources: []*tfjson.StateResource{
+						{Address: "aws_vpc.main"},
+						{Address: "aws_subnet.a", DependsOn: []string{"aws_vpc.main"}},
+						{Address: "aws_instance.web[0]", DependsOn: []string{"aws_subnet.a"}},
+						{Address: "aws_instance.web[1]", DependsOn: []string{"aws_subnet.a"}},
+						{Address: "aws_s3_bucket.logs"},
+						// Only recorded in state, as its configuration was removed.
+						{Address: "aws_flow_log.main", DependsOn: []string{"aws_vpc.main"}},
+					},
+				},
+			},
+		},
+		ResourceChanges: []*tfjson.ResourceChange{
+			{
+				Address:      "aws_vpc.main",
+				Change:       &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate}},
+				ActionReason: tfjson.ActionReasonReplaceBecauseCannotUpdate,
+			},
+			{Address: "aws_subnet.a", Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionUpdate}}},
+			{Address: "aws_instance.web[0]", Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}}},
+			{Address: "aws_instance.web[1]", Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}}},
+			{
+				Address:      "aws_s3_bucket.logs",
+				Change:       &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionCreate, tfjson.ActionDelete}},
+				ActionReason: tfjson.ActionReasonReplaceByRequest,
+			},
+			{
+				Address:      "aws_flow_log.main",
+				Change:       &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionDelete}},
+				ActionReason: tfjson.ActionReasonDeleteBecauseNoResourceConfig,
+			},
+			{
+				Address:    "aws_instance.web[0]",
+				DeposedKey: "00000001",
+				Change:     &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionDelete}},
+			},
+		},
+	}
+}
+
+func TestBlastRadius(t *testing.T) {
+	impacts, err := BlastRadius(testBlastPlan())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Impact{
+		{
+			Address:      "aws_vpc.main",
+			Actions:      tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate},
+			ActionReason: tfjson.ActionReasonReplaceBecauseCannotUpdate,
+			Dependents: []Dependent{
+				{Address: "aws_flow_log.main", Kind: KindResource, Distance: 1, Actions: tfjson.Actions{tfjson.ActionDelete}},
+				{Address: "aws_instance.web[0]", Kind: KindResource, Distance: 2, Actions: tfjson.Actions{tfjson.ActionNoop}},
+				{Address: "aws_instance.web[1]", Kind: KindResource, Distance: 2, Actions: tfjson.Actions{tfjson.ActionNoop}},
+				{Address: "aws_subnet.a", Kind: KindResource, Distance: 1, Actions: tfjson.Actions{tfjson.ActionUpdate}},
+				{Address: "output.web_ip", Kind: KindOutput, Distance: 3},
+			},
+			// 50 for destroy-before-create, 20 for cannot update, 20 for
+			// four resources and 5 for the output.
+			RiskScore: 95,
+		},
+		{
+			Address:      "aws_s3_bucket.logs",
+			Actions:      tfjson.Actions{tfjson.ActionCreate, tfjson.ActionDelete},
+			ActionReason: tfjson.ActionReasonReplaceByRequest,
+			RiskScore:    10,
+		},
+		{
+			Address:      "aws_flow_log.main",
+			Actions:      tfjson.Actions{tfjson.ActionDelete},
+			ActionReason: tfjson.ActionReasonDeleteBecauseNoResourceConfig,
+			RiskScore:    70,
+		},
+	}
+
+	if diff := cmp.Diff(expected, impacts); diff != "" {
+		t.Errorf("BlastRadius() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	levels := []RiskLevel{RiskHigh, RiskLow, RiskHigh}
+	for i, impact := range impacts {
+		if impact.RiskLevel() != levels[i] {
+			t.Errorf("%s: expected risk level %s, got %s", impact.Address, levels[i], impact.RiskLevel())
+		}
+	}
+}
+
+func TestBlastRadius_stateOnly(t *testing.T) {
+	plan := testBlastPlan()
+	plan.Config = nil
+
+	impacts, err := BlastRadius(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []string
+	for _, d := range impacts[0].Dependents {
+		actual = append(actual, d.Address)
+	}
+
+	expected := []string{"aws_flow_log.main", "aws_instance.web[0]", "aws_instance.web[1]", "aws_subnet.a"}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("BlastRadius() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestBlastRadius_nil(t *testing.T) {
+	if _, err := BlastRadius(nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package depgraph
+
+import (
+	"sort"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// FromConfig builds a graph of the resources, variables, outputs and
+// module calls declared in c, including those within child modules.
+// Addresses are those of the configuration objects, without instance
+// keys.
+//
+// Dependencies are derived from expression references, depends_on
+// arguments, and the following implicit relationships:
+//
+// * Every object declared within a child module depends on the module
+// call, which in turn depends on the references in its count, for_each
+// and depends_on arguments.
+//
+// * Each variable within a child module depends on the references in the
+// corresponding argument of the module call.
+//
+// * A reference to a whole module call, such as "module.network", depends
+// on every output of that module. A depends_on entry for a whole module
+// call depends on every resource within it.
+//
+// References to objects which are not part of the graph, such as local
+// values, are ignored, as are references which cannot be resolved.
+func FromConfig(c *tfjson.Config) *Graph {
+	g := New()
+	if c == nil || c.RootModule == nil {
+		return g
+	}
+
+	b := &configBuilder{g: g}
+	b.addNodes(c.RootModule, "")
+	b.addEdges(c.RootModule, "")
+
+	return g
+}
+
+type configBuilder struct {
+	g *Graph
+}
+
+func (b *configBuilder) addNodes(m *tfjson.ConfigModule, module string) {
+	for name := range m.Variables {
+		b.g.AddNode(&Node{Address: qualify(module, "var."+name), Kind: KindVariable, Module: module})
+	}
+
+	for name := range m.Outputs {
+		b.g.AddNode(&Node{Address: qualify(module, "output."+name), Kind: KindOutput, Module: module})
+	}
+
+	for _, r := range m.Resources {
+		if r == nil {
+			continue
+		}
+
+		kind := KindResource
+		if r.Mode == tfjson.DataResourceMode {
+			kind = KindDataSource
+		}
+		b.g.AddNode(&Node{Address: qualify(module, r.Address), Kind: kind, Module: module})
+	}
+
+	for name, call := range m.ModuleCalls {
+		address := qualify(module, "module."+name)
+		b.g.AddNode(&Node{Address: address, Kind: KindModule, Module: module})
+		if call != nil && call.Module != nil {
+			b.addNodes(call.Module, address)
+		}
+	}
+}
+
+func (b *configBuilder) addEdges(m *tfjson.ConfigModule, module string) {
+	for _, r := range m.Resources {
+		if r == nil {
+			continue
+		}
+
+		address := qualify(module, r.Address)
+		b.dependsOnModuleCall(address, module)
+
+		refs := expressionReferences(r.Expressions)
+		refs = append(refs, expressionReferences(map[string]*tfjson.Expression{
+			"count":    r.CountExpression,
+			"for_each": r.ForEachExpression,
+		})...)
+		for _, p := range r.Provisioners {
+			if p != nil {
+				refs = append(refs, expressionReferences(p.Expressions)...)
+			}
+		}
+
+		b.references(address, module, refs)
+		b.dependsOn(address, module, r.DependsOn)
+	}
+
+	for name, o := range m.Outputs {
+		address := qualify(module, "output."+name)
+		b.dependsOnModuleCall(address, module)
+		if o == nil {
+			continue
+		}
+
+		b.references(address, module, expressionReferences(map[string]*tfjson.Expression{"value": o.Expression}))
+		b.dependsOn(address, module, o.DependsOn)
+	}
+
+	for name := range m.Variables {
+		b.dependsOnModuleCall(qualify(module, "var."+name), module)
+	}
+
+	for name, call := range m.ModuleCalls {
+		address := qualify(module, "module."+name)
+		b.dependsOnModuleCall(address, module)
+		if call == nil {
+			continue
+		}
+
+		b.references(address, module, expressionReferences(map[string]*tfjson.Expression{
+			"count":    call.CountExpression,
+			"for_each": call.ForEachExpression,
+		}))
+		b.dependsOn(address, module, call.DependsOn)
+
+		// The module's variables depend on the arguments passed to them,
+		// which are evaluated in the calling module.
+		for arg, expr := range call.Expressions {
+			variable := qualify(address, "var."+arg)
+			if b.g.Node(variable) == nil {
+				continue
+			}
+			b.references(variable, module, expressionReferences(map[string]*tfjson.Expression{arg: expr}))
+		}
+
+		if call.Module != nil {
+			b.addEdges(call.Module, address)
+		}
+	}
+}
+
+// dependsOnModuleCall adds a dependency from the node at address to the
+// call of the module containing it, if it is not the root module.
+func (b *configBuilder) dependsOnModuleCall(address, module string) {
+	if module != "" {
+		b.g.AddDependency(address, module)
+	}
+}
+
+// references adds a dependency from the node at address to the target of
+// each reference, which were found in module.
+func (b *configBuilder) references(address, module string, refs []string) {
+	for _, ref := range refs {
+		target, ok := referenceTarget(module, ref)
+		if !ok {
+			continue
+		}
+
+		n := b.g.Node(target)
+		switch {
+		case n == nil:
+			continue
+		case n.Kind == KindModule:
+			for _, output := range b.moduleNodes(target, KindOutput, false) {
+				b.g.AddDependency(address, output)
+			}
+		default:
+			b.g.AddDependency(address, target)
+		}
+	}
+}
+
+// dependsOn adds a dependency from the node at address to each entry of a
+// depends_on argument found in module.
+func (b *configBuilder) dependsOn(address, module string, entries []string) {
+	for _, entry := range entries {
+		target, ok := referenceTarget(module, entry)
+		if !ok {
+			continue
+		}
+
+		n := b.g.Node(target)
+		switch {
+		case n == nil:
+			continue
+		case n.Kind == KindModule:
+			for _, r := range b.moduleNodes(target, KindResource, true) {
+				b.g.AddDependency(address, r)
+			}
+			for _, r := range b.moduleNodes(target, KindDataSource, true) {
+				b.g.AddDependency(address, r)
+			}
+		default:
+			b.g.AddDependency(address, target)
+		}
+	}
+}
+
+// moduleNodes returns the addresses of the nodes of the given kind
+// declared within module, and optionally within its descendants.
+func (b *configBuilder) moduleNodes(module string, kind NodeKind, descendants bool) []string {
+	var result []string
+	for address, n := range b.g.nodes {
+		if n.Kind != kind {
+			continue
+		}
+		if n.Module == module || (descendants && strings.HasPrefix(n.Module, module+".")) {
+			result = append(result, address)
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// expressionReferences returns every reference within the expressions,
+// including those within nested blocks.
+func expressionReferences(exprs map[string]*tfjson.Expression) []string {
+	var result []string
+	for _, expr := range exprs {
+		if expr == nil || expr.ExpressionData == nil {
+			continue
+		}
+
+		result = append(result, expr.References...)
+		for _, block := range expr.NestedBlocks {
+			result = append(result, expressionReferences(block)...)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,171 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package depgraph
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func refs(references ...string) *tfjson.Expression {
+	return &tfjson.Expression{
+		ExpressionData: &tfjson.ExpressionData{
+			ConstantValue: tfjson.UnknownConstantValue,
+			References:    references,
+		},
+	}
+}
+
+func testConfig() *tfjson.Config {
+	return &tfjson.Config{
+		RootModule: &tfjson.ConfigModule{
+			Variables: map[string]*tfjson.ConfigVariable{
+				"cidr": {},
+			},
+			Resources: []*tfjson.ConfigResource{
+				{
+					Address: "aws_instance.web",
+					Mode:    tfjson.ManagedResourceMode,
+					Expressions: map[string]*tfjson.Expression{
+						"subnet_id": refs("module.network.subnet_id", "module.network"),
+						"ebs_block_device": {
+							ExpressionData: &tfjson.ExpressionData{
+								NestedBlocks: []map[string]*tfjson.Expression{
+									{"snapshot_id": refs("data.aws_ebs_snapshot.base.id", "data.aws_ebs_snapshot.base")},
+								},
+							},
+						},
+					},
+					CountExpression: refs("local.count"),
+				},
+				{
+					Address: "data.aws_ebs_snapshot.base",
+					Mode:    tfjson.DataResourceMode,
+				},
+				{
+					Address:   "null_resource.after",
+					Mode:      tfjson.ManagedResourceMode,
+					DependsOn: []string{"module.network"},
+				},
+			},
+			Outputs: map[string]*tfjson.ConfigOutput{
+				"network": {Expression: refs("module.network")},
+			},
+			ModuleCalls: map[string]*tfjson.ModuleCall{
+				"network": {
+					Expressions: map[string]*tfjson.Expression{
+						"cidr": refs("var.cidr"),
+					},
+					Module: &tfjson.ConfigModule{
+						Variables: map[string]*tfjson.ConfigVariable{
+							"cidr": {},
+						},
+						Resources: []*tfjson.ConfigResource{
+							{
+								Address: "aws_subnet.this",
+								Mode:    tfjson.ManagedResourceMode,
+								Expressions: map[string]*tfjson.Expression{
+									"cidr_block": refs("var.cidr"),
+								},
+							},
+						},
+						Outputs: map[string]*tfjson.ConfigOutput{
+							"subnet_id": {Expression: refs("aws_subnet.this.id", "aws_subnet.this")},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	g := FromConfig(testConfig())
+
+	expected := map[string][]string{
+		"aws_instance.web":                {"data.aws_ebs_snapshot.base", "module.network.output.subnet_id"},
+		"data.aws_ebs_snapshot.base":      nil,
+		"module.network":                  nil,
+		"module.network.aws_subnet.this":  {"module.network", "module.network.var.cidr"},
+		"module.network.output.subnet_id": {"module.network", "module.network.aws_subnet.this"},
+		"module.network.var.cidr":         {"module.network", "var.cidr"},
+		"null_resource.after":             {"module.network.aws_subnet.this"},
+		"output.network":                  {"module.network.output.subnet_id"},
+		"var.cidr":                        nil,
+	}
+
+	actual := map[string][]string{}
+	for _, n := range g.Nodes() {
+		actual[n.Address] = g.DependenciesOf(n.Address)
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("FromConfig() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	if n := g.Node("module.network.var.cidr"); n.Kind != KindVariable || n.Module != "module.network" {
+		t.Errorf("unexpected node: %#v", n)
+	}
+	if n := g.Node("data.aws_ebs_snapshot.base"); n.Kind != KindDataSource {
+		t.Errorf("unexpected node: %#v", n)
+	}
+
+	expectedDependents := []string{
+		"aws_instance.web",
+		"module.network.aws_subnet.this",
+		"module.network.output.subnet_id",
+		"module.network.var.cidr",
+		"null_resource.after",
+		"output.network",
+	}
+	if diff := cmp.Diff(expectedDependents, g.AllDependents("var.cidr")); diff != "" {
+		t.Errorf("AllDependents() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestFromConfig_nil(t *testing.T) {
+	if len(FromConfig(nil).Nodes()) != 0 {
+		t.Fatal("expected empty graph")
+	}
+}
+
+func TestFromConfig_fixture(t *testing.T) {
+	f, err := os.Open(filepath.Join("..", "testdata", "basic", "plan.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var plan *tfjson.Plan
+	if err := json.NewDecoder(f).Decode(&plan); err != nil {
+		t.Fatal(err)
+	}
+
+	g := FromConfig(plan.Config)
+	order, err := g.TopologicalOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	position := map[string]int{}
+	for i, address := range order {
+		position[address] = i
+	}
+	for _, n := range g.Nodes() {
+		for _, dep := range g.DependenciesOf(n.Address) {
+			if position[dep] > position[n.Address] {
+				t.Errorf("%s ordered before its dependency %s", n.Address, dep)
+			}
+		}
+	}
+
+	expected := []string{"data.null_data_source.baz", "null_resource.bar", "null_resource.baz"}
+	if diff := cmp.Diff(expected, g.DependentsOf("null_resource.foo")[:3]); diff != "" {
+		t.Errorf("DependentsOf() mismatch (-expected +actual):\n%s", diff)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package depgraph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT writes the graph to w in the Graphviz DOT language, with each
+// node identified by its address. Nodes and edges are written in order,
+// so the output is stable.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "digraph {")
+
+	for _, n := range g.Nodes() {
+		fmt.Fprintf(bw, "\t%s [shape = %q]\n", dotQuote(n.Address), dotShape(n.Kind))
+	}
+
+	for _, from := range g.addresses() {
+		for _, to := range g.DependenciesOf(from) {
+			fmt.Fprintf(bw, "\t%s -> %s\n", dotQuote(from), dotQuote(to))
+		}
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+// DOT returns the graph in the Graphviz DOT language, as written by
+// WriteDOT.
+func (g *Graph) DOT() string {
+	var sb strings.Builder
+	g.WriteDOT(&sb)
+
+	return sb.String()
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func dotShape(kind NodeKind) string {
+	switch kind {
+	case KindVariable, KindOutput:
+		return "note"
+	case KindModule:
+		return "folder"
+	case KindDataSource:
+		return "ellipse"
+	}
+
+	return "box"
+}
+
+// WriteMermaid writes the graph to w as a Mermaid flowchart. As Mermaid
+// node identifiers cannot contain most of the characters found in
+// addresses, nodes are given generated identifiers and labelled with
+// their addresses.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "flowchart LR")
+
+	addresses := g.addresses()
+	ids := make(map[string]string, len(addresses))
+	for i, address := range addresses {
+		ids[address] = fmt.Sprintf("n%d", i)
+
+		start, end := mermaidShape(g.nodes[address].Kind)
+		fmt.Fprintf(bw, "\t%s%s\"%s\"%s\n", ids[address], start, mermaidEscape(address), end)
+	}
+
+	for _, from := range addresses {
+		for _, to := range g.DependenciesOf(from) {
+			fmt.Fprintf(bw, "\t%s --> %s\n", ids[from], ids[to])
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart, as written by
+// WriteMermaid.
+func (g *Graph) Mermaid() string {
+	var sb strings.Builder
+	g.WriteMermaid(&sb)
+
+	return sb.String()
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+func mermaidShape(kind NodeKind) (string, string) {
+	switch kind {
+	case KindVariable, KindOutput:
+		return ">", "]"
+	case KindModule:
+		return "[[", "]]"
+	case KindDataSource:
+		return "([", "])"
+	}
+
+	return "[", "]"
+}
@@ -0,0 +1,311 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package depgraph builds dependency graphs from the configuration and
+// state representations in the tfjson package, and uses them to analyze
+// the blast radius of destructive changes in a plan.
+//
+// Edges point from a dependent node to the node it depends on, matching
+// the direction used by "terraform graph".
+package depgraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NodeKind describes the kind of object a Node represents.
+type NodeKind string
+
+const (
+	// KindResource is a managed resource, or a managed resource instance
+	// in a graph built from state.
+	KindResource NodeKind = "resource"
+
+	// KindDataSource is a data resource, or a data resource instance in
+	// a graph built from state.
+	KindDataSource NodeKind = "data"
+
+	// KindVariable is an input variable.
+	KindVariable NodeKind = "variable"
+
+	// KindOutput is an output value.
+	KindOutput NodeKind = "output"
+
+	// KindModule is a module call.
+	KindModule NodeKind = "module"
+)
+
+// Node is a single object within a Graph.
+type Node struct {
+	// Address is the absolute address of the object, which uniquely
+	// identifies the node within its graph. Variables and outputs within
+	// child modules are prefixed by the module address, for example
+	// "module.network.var.cidr" and "module.network.output.vpc_id".
+	Address string
+
+	// Kind is the kind of object the node represents.
+	Kind NodeKind
+
+	// Module is the address of the module containing the object, which
+	// is empty for the root module.
+	Module string
+}
+
+// Graph is a directed dependency graph. The zero value is not usable;
+// create graphs with New, FromConfig or FromState.
+type Graph struct {
+	nodes map[string]*Node
+
+	// dependencies and dependents hold the edges in each direction,
+	// keyed by node address.
+	dependencies map[string]map[string]struct{}
+	dependents   map[string]map[string]struct{}
+}
+
+// New returns an empty Graph.
+func New() *Graph {
+	return &Graph{
+		nodes:        map[string]*Node{},
+		dependencies: map[string]map[string]struct{}{},
+		dependents:   map[string]map[string]struct{}{},
+	}
+}
+
+// AddNode adds n to the graph. If a node with the same address already
+// exists it is replaced, retaining its edges.
+func (g *Graph) AddNode(n *Node) {
+	g.nodes[n.Address] = n
+}
+
+// AddDependency records that the node at from depends on the node at
+// to. Both nodes must already have been added.
+func (g *Graph) AddDependency(from, to string) error {
+	if _, ok := g.nodes[from]; !ok {
+		return fmt.Errorf("unknown node %q", from)
+	}
+	if _, ok := g.nodes[to]; !ok {
+		return fmt.Errorf("unknown node %q", to)
+	}
+
+	addEdge(g.dependencies, from, to)
+	addEdge(g.dependents, to, from)
+
+	return nil
+}
+
+func addEdge(edges map[string]map[string]struct{}, from, to string) {
+	if edges[from] == nil {
+		edges[from] = map[string]struct{}{}
+	}
+	edges[from][to] = struct{}{}
+}
+
+// Node returns the node at address, or nil if there is none.
+func (g *Graph) Node(address string) *Node {
+	return g.nodes[address]
+}
+
+// Nodes returns every node in the graph, ordered by address.
+func (g *Graph) Nodes() []*Node {
+	result := make([]*Node, 0, len(g.nodes))
+	for _, address := range g.addresses() {
+		result = append(result, g.nodes[address])
+	}
+
+	return result
+}
+
+func (g *Graph) addresses() []string {
+	result := make([]string, 0, len(g.nodes))
+	for address := range g.nodes {
+		result = append(result, address)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// DependenciesOf returns the addresses of the nodes that the node at
+// address directly depends on, in order.
+func (g *Graph) DependenciesOf(address string) []string {
+	return sortedKeys(g.dependencies[address])
+}
+
+// DependentsOf returns the addresses of the nodes that directly depend on
+// the node at address, in order.
+func (g *Graph) DependentsOf(address string) []string {
+	return sortedKeys(g.dependents[address])
+}
+
+// AllDependencies returns the addresses of every node that the node at
+// address depends on, directly or indirectly, in order.
+func (g *Graph) AllDependencies(address string) []string {
+	return g.reachable(g.dependencies, address)
+}
+
+// AllDependents returns the addresses of every node that depends on the
+// node at address, directly or indirectly, in order. This answers "what
+// is affected if this changes?".
+func (g *Graph) AllDependents(address string) []string {
+	return g.reachable(g.dependents, address)
+}
+
+func (g *Graph) reachable(edges map[string]map[string]struct{}, start string) []string {
+	seen := map[string]struct{}{}
+	stack := []string{start}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for next := range edges[current] {
+			if _, ok := seen[next]; ok {
+				continue
+			}
+			seen[next] = struct{}{}
+			stack = append(stack, next)
+		}
+	}
+
+	// A node within a cycle reaches itself, but is not reported as its
+	// own dependency.
+	delete(seen, start)
+
+	return sortedKeys(seen)
+}
+
+// CycleError is returned by TopologicalOrder when the graph contains one
+// or more cycles.
+type CycleError struct {
+	Cycles [][]string
+}
+
+func (e *CycleError) Error() string {
+	cycles := make([]string, len(e.Cycles))
+	for i, c := range e.Cycles {
+		cycles[i] = strings.Join(c, ", ")
+	}
+
+	return fmt.Sprintf("dependency cycle: %s", strings.Join(cycles, "; "))
+}
+
+// TopologicalOrder returns the address of every node, ordered so that
+// each node appears after all of its dependencies. Nodes which could
+// appear in either order are ordered by address, so the result is
+// deterministic.
+//
+// If the graph contains a cycle, a *CycleError is returned.
+func (g *Graph) TopologicalOrder() ([]string, error) {
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		return nil, &CycleError{Cycles: cycles}
+	}
+
+	remaining := make(map[string]int, len(g.nodes))
+	var ready []string
+	for _, address := range g.addresses() {
+		remaining[address] = len(g.dependencies[address])
+		if remaining[address] == 0 {
+			ready = append(ready, address)
+		}
+	}
+
+	result := make([]string, 0, len(g.nodes))
+	for len(ready) > 0 {
+		current := ready[0]
+		ready = ready[1:]
+		result = append(result, current)
+
+		var next []string
+		for dependent := range g.dependents[current] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				next = append(next, dependent)
+			}
+		}
+		if len(next) > 0 {
+			ready = append(ready, next...)
+			sort.Strings(ready)
+		}
+	}
+
+	return result, nil
+}
+
+// Cycles returns every cycle in the graph, as the set of addresses of
+// the nodes within each strongly connected component. Both the cycles
+// and the addresses within them are ordered.
+func (g *Graph) Cycles() [][]string {
+	// Tarjan's strongly connected components algorithm.
+	index := map[string]int{}
+	lowlink := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var result [][]string
+
+	var connect func(v string)
+	connect = func(v string) {
+		index[v] = len(index)
+		lowlink[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range g.DependenciesOf(v) {
+			if _, ok := index[w]; !ok {
+				connect(w)
+				if lowlink[w] < lowlink[v] {
+					lowlink[v] = lowlink[w]
+				}
+			} else if onStack[w] && index[w] < lowlink[v] {
+				lowlink[v] = index[w]
+			}
+		}
+
+		if lowlink[v] != index[v] {
+			return
+		}
+
+		var component []string
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+			if w == v {
+				break
+			}
+		}
+
+		_, selfLoop := g.dependencies[v][v]
+		if len(component) > 1 || selfLoop {
+			sort.Strings(component)
+			result = append(result, component)
+		}
+	}
+
+	for _, address := range g.addresses() {
+		if _, ok := index[address]; !ok {
+			connect(address)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i][0] < result[j][0]
+	})
+
+	return result
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	if len(m) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,137 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package depgraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testGraph(t *testing.T, edges map[string][]string) *Graph {
+	t.Helper()
+
+	g := New()
+	for from, tos := range edges {
+		g.AddNode(&Node{Address: from, Kind: KindResource})
+		for _, to := range tos {
+			g.AddNode(&Node{Address: to, Kind: KindResource})
+		}
+	}
+	for from, tos := range edges {
+		for _, to := range tos {
+			if err := g.AddDependency(from, to); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	return g
+}
+
+func TestGraph_TopologicalOrder(t *testing.T) {
+	g := testGraph(t, map[string][]string{
+		"d": {"b", "c"},
+		"b": {"a"},
+		"c": {"a"},
+		"e": nil,
+	})
+
+	order, err := g.TopologicalOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"a", "b", "c", "d", "e"}
+	if diff := cmp.Diff(expected, order); diff != "" {
+		t.Errorf("TopologicalOrder() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestGraph_cycles(t *testing.T) {
+	g := testGraph(t, map[string][]string{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"a"},
+		"d": {"d"},
+		"e": {"a"},
+	})
+
+	expected := [][]string{{"a", "b", "c"}, {"d"}}
+	if diff := cmp.Diff(expected, g.Cycles()); diff != "" {
+		t.Errorf("Cycles() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	_, err := g.TopologicalOrder()
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected *CycleError, got %v", err)
+	}
+	if diff := cmp.Diff(expected, cycleErr.Cycles); diff != "" {
+		t.Errorf("CycleError mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestGraph_dependents(t *testing.T) {
+	g := testGraph(t, map[string][]string{
+		"app":    {"subnet", "sg"},
+		"subnet": {"vpc"},
+		"sg":     {"vpc"},
+		"dns":    {"app"},
+	})
+
+	if diff := cmp.Diff([]string{"sg", "subnet"}, g.DependentsOf("vpc")); diff != "" {
+		t.Errorf("DependentsOf() mismatch (-expected +actual):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"app", "dns", "sg", "subnet"}, g.AllDependents("vpc")); diff != "" {
+		t.Errorf("AllDependents() mismatch (-expected +actual):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"app", "sg", "subnet", "vpc"}, g.AllDependencies("dns")); diff != "" {
+		t.Errorf("AllDependencies() mismatch (-expected +actual):\n%s", diff)
+	}
+	if g.AllDependents("dns") != nil {
+		t.Errorf("expected no dependents of dns")
+	}
+}
+
+func TestGraph_AddDependencyUnknownNode(t *testing.T) {
+	g := New()
+	g.AddNode(&Node{Address: "a"})
+	if err := g.AddDependency("a", "b"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestGraph_export(t *testing.T) {
+	g := New()
+	g.AddNode(&Node{Address: `module.app["web"]`, Kind: KindModule})
+	g.AddNode(&Node{Address: "aws_instance.web", Kind: KindResource})
+	g.AddNode(&Node{Address: "var.ami", Kind: KindVariable})
+	g.AddDependency("aws_instance.web", "var.ami")
+	g.AddDependency(`module.app["web"]`, "aws_instance.web")
+
+	expectedDOT := `digraph {
+	"aws_instance.web" [shape = "box"]
+	"module.app[\"web\"]" [shape = "folder"]
+	"var.ami" [shape = "note"]
+	"aws_instance.web" -> "var.ami"
+	"module.app[\"web\"]" -> "aws_instance.web"
+}
+`
+	if diff := cmp.Diff(expectedDOT, g.DOT()); diff != "" {
+		t.Errorf("DOT() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	expectedMermaid := `flowchart LR
+	n0["aws_instance.web"]
+	n1[["module.app[#quot;web#quot;]"]]
+	n2>"var.ami"]
+	n0 --> n2
+	n1 --> n0
+`
+	if diff := cmp.Diff(expectedMermaid, g.Mermaid()); diff != "" {
+		t.Errorf("Mermaid() mismatch (-expected +actual):\n%s", diff)
+	}
+}
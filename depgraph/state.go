@@ -0,0 +1,56 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package depgraph
+
+import tfjson "github.com/hashicorp/terraform-json"
+
+// FromState builds a graph of the resource instances in s, using the
+// dependencies recorded in the DependsOn of each instance. Nodes are
+// addressed by resource instance address, including instance keys.
+//
+// Terraform records dependencies by resource address, so an instance
+// depends on every instance of each resource it depends on. Dependencies
+// on resources which are not present in the state are ignored, as are
+// deposed objects.
+func FromState(s *tfjson.State) *Graph {
+	g := New()
+
+	// Instances of each resource, keyed by the resource address.
+	instances := map[string][]string{}
+
+	s.Walk(func(_ *tfjson.StateModule, r *tfjson.StateResource) bool {
+		if r.DeposedKey != "" {
+			return true
+		}
+
+		kind := KindResource
+		if r.Mode == tfjson.DataResourceMode {
+			kind = KindDataSource
+		}
+		g.AddNode(&Node{Address: r.Address, Kind: kind, Module: moduleAddressOf(r.Address)})
+
+		resource := stripInstanceKeys(r.Address)
+		instances[resource] = append(instances[resource], r.Address)
+
+		return true
+	})
+
+	s.Walk(func(_ *tfjson.StateModule, r *tfjson.StateResource) bool {
+		if r.DeposedKey != "" {
+			return true
+		}
+
+		for _, dep := range r.DependsOn {
+			for _, target := range instances[stripInstanceKeys(dep)] {
+				if target != r.Address {
+					g.AddDependency(r.Address, target)
+				}
+			}
+		}
+
+		return true
+	})
+
+	return g
+}
@@ -0,0 +1,83 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func TestFromState(t *testing.T) {
+	state := &tfjson.State{
+		Values: &tfjson.StateValues{
+			RootModule: &tfjson.StateModule{
+				Resources: []*tfjson.StateResource{
+					{Address: "aws_vpc.main", Mode: tfjson.ManagedResourceMode},
+					{Address: "data.aws_ami.ubuntu", Mode: tfjson.DataResourceMode},
+					{
+						Address:   "aws_instance.web[0]",
+						Mode:      tfjson.ManagedResourceMode,
+						DependsOn: []string{"module.net.aws_subnet.this", "data.aws_ami.ubuntu", "aws_gone.missing"},
+					},
+					{
+						Address:    "aws_instance.web[0]",
+						Mode:       tfjson.ManagedResourceMode,
+						DeposedKey: "00000001",
+						DependsOn:  []string{"aws_vpc.main"},
+					},
+				},
+				ChildModules: []*tfjson.StateModule{
+					{
+						Address: "module.net",
+						Resources: []*tfjson.StateResource{
+							{
+								Address:   `module.net.aws_subnet.this["a"]`,
+								Mode:      tfjson.ManagedResourceMode,
+								DependsOn: []string{"aws_vpc.main"},
+							},
+							{
+								Address:   `module.net.aws_subnet.this["b"]`,
+								Mode:      tfjson.ManagedResourceMode,
+								DependsOn: []string{"aws_vpc.main"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	g := FromState(state)
+
+	expected := map[string][]string{
+		"aws_instance.web[0]":             {"data.aws_ami.ubuntu", `module.net.aws_subnet.this["a"]`, `module.net.aws_subnet.this["b"]`},
+		"aws_vpc.main":                    nil,
+		"data.aws_ami.ubuntu":             nil,
+		`module.net.aws_subnet.this["a"]`: {"aws_vpc.main"},
+		`module.net.aws_subnet.this["b"]`: {"aws_vpc.main"},
+	}
+
+	actual := map[string][]string{}
+	for _, n := range g.Nodes() {
+		actual[n.Address] = g.DependenciesOf(n.Address)
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("FromState() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	if n := g.Node(`module.net.aws_subnet.this["a"]`); n.Module != "module.net" || n.Kind != KindResource {
+		t.Errorf("unexpected node: %#v", n)
+	}
+
+	expectedDependents := []string{
+		"aws_instance.web[0]",
+		`module.net.aws_subnet.this["a"]`,
+		`module.net.aws_subnet.this["b"]`,
+	}
+	if diff := cmp.Diff(expectedDependents, g.AllDependents("aws_vpc.main")); diff != "" {
+		t.Errorf("AllDependents() mismatch (-expected +actual):\n%s", diff)
+	}
+}
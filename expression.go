@@ -3,13 +3,17 @@
 
 package tfjson
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"reflect"
+)
 
 type unknownConstantValue struct{}
 
 // UnknownConstantValue is a singleton type that denotes that a
 // constant value is explicitly unknown. This is set during an
-// unmarshal when references are found in an expression to help more
+// unmarshal when references are found in an expression, or when its
+// constant value is absent altogether, to help more
 // explicitly differentiate between an explicit null and unknown
 // value.
 var UnknownConstantValue = &unknownConstantValue{}
@@ -25,6 +29,8 @@ type Expression struct {
 // ExpressionData describes the format for an individual key in a
 // Terraform configuration.
 type ExpressionData struct {
+	unknownMembers
+
 	// If the *entire* expression is a constant-defined value, this
 	// will contain the Go representation of the expression's data.
 	//
@@ -46,30 +52,48 @@ type ExpressionData struct {
 	NestedBlocks []map[string]*Expression `json:"-"`
 }
 
+// MarshalJSON implements json.Marshaler for ExpressionData.
+func (e *ExpressionData) MarshalJSON() ([]byte, error) {
+	type rawExpressionData ExpressionData
+	return marshalUnknownMembers((*rawExpressionData)(e))
+}
+
 // UnmarshalJSON implements json.Unmarshaler for Expression.
 //
 // As per established convention this method should only ever
 // be invoked *indirectly* via [encoding/json] library.
 func (e *Expression) UnmarshalJSON(b []byte) error {
+	_, err := DecodeOptions{}.Unmarshal(b, e)
+	return err
+}
+
+// unmarshalJSONOptions implements optionsUnmarshaler for Expression, so
+// that constant values are decoded according to the options of the
+// document containing the expression.
+func (e *Expression) unmarshalJSONOptions(d *jsonDecoder, b []byte) error {
 	result := new(ExpressionData)
 
-	// Check to see if this is an array first. If it is, this is more
-	// than likely a list of nested blocks.
-	var rawNested []map[string]json.RawMessage
-	if err := json.Unmarshal(b, &rawNested); err == nil {
-		result.NestedBlocks, err = unmarshalExpressionBlocks(rawNested)
-		if err != nil {
+	// An array is a list of nested blocks.
+	if b[0] == '[' {
+		if err := d.decode(b, &result.NestedBlocks); err != nil {
 			return err
 		}
 	} else {
 		// It's a non-nested expression block, parse normally
-		if err := json.Unmarshal(b, &result); err != nil {
+		var data jsonExpressionData
+		if err := d.decode(b, &data); err != nil {
 			return err
 		}
 
+		*result = data.ExpressionData
+		result.ConstantValue = data.ConstantValue.value
+
 		// If References is non-zero, then ConstantValue is unknown. Set
-		// this explicitly.
-		if len(result.References) > 0 {
+		// this explicitly. An expression which cannot be resolved at parse
+		// time but makes no references, such as a call to timestamp(), has
+		// no constant value at all, as opposed to an explicit null, and is
+		// unknown too.
+		if len(result.References) > 0 || !data.ConstantValue.present {
 			result.ConstantValue = UnknownConstantValue
 		}
 	}
@@ -78,24 +102,26 @@ func (e *Expression) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func unmarshalExpressionBlocks(raw []map[string]json.RawMessage) ([]map[string]*Expression, error) {
-	var result []map[string]*Expression
+// jsonExpressionData is the JSON representation of ExpressionData, which
+// records whether the constant value was present.
+type jsonExpressionData struct {
+	ExpressionData
 
-	for _, rawBlock := range raw {
-		block := make(map[string]*Expression)
-		for k, rawExpr := range rawBlock {
-			var expr *Expression
-			if err := json.Unmarshal(rawExpr, &expr); err != nil {
-				return nil, err
-			}
-
-			block[k] = expr
-		}
+	ConstantValue jsonConstantValue `json:"constant_value"`
+}
 
-		result = append(result, block)
-	}
+// jsonConstantValue is the constant value of an expression, and whether it
+// was present.
+type jsonConstantValue struct {
+	value   interface{}
+	present bool
+}
 
-	return result, nil
+// unmarshalJSONOptions implements optionsUnmarshaler for
+// jsonConstantValue, which is only called for a value that is present.
+func (v *jsonConstantValue) unmarshalJSONOptions(d *jsonDecoder, b []byte) error {
+	v.present = true
+	return d.leaf(b, reflect.ValueOf(&v.value).Elem())
 }
 
 // MarshalJSON implements json.Marshaler for Expression.
@@ -106,8 +132,18 @@ func (e *Expression) MarshalJSON() ([]byte, error) {
 
 	case e.ExpressionData.ConstantValue == UnknownConstantValue:
 		return json.Marshal(&ExpressionData{
-			References: e.ExpressionData.References,
+			unknownMembers: e.ExpressionData.unknownMembers,
+			References:     e.ExpressionData.References,
 		})
+
+	case e.ExpressionData.ConstantValue == nil:
+		// An explicit null is emitted, as an absent constant value is
+		// decoded as UnknownConstantValue.
+		type rawExpressionData ExpressionData
+		return marshalUnknownMembers(&struct {
+			*rawExpressionData
+			ConstantValue interface{} `json:"constant_value"`
+		}{rawExpressionData: (*rawExpressionData)(e.ExpressionData)})
 	}
 
 	return json.Marshal(e.ExpressionData)
@@ -118,6 +118,43 @@ func TestUnmarshalExpressions(t *testing.T) {
 				SchemaVersion: 0,
 			},
 		},
+		{
+			name: "null and absent constant values",
+			in: `
+{
+  "address": "null_resource.foo",
+  "mode": "managed",
+  "type": "null_resource",
+  "name": "foo",
+  "provider_config_key": "null",
+  "expressions": {
+    "created": {},
+    "triggers": {
+      "constant_value": null
+    }
+  },
+  "schema_version": 0
+}
+`,
+			expected: &ConfigResource{
+				Address:           "null_resource.foo",
+				Mode:              ManagedResourceMode,
+				Type:              "null_resource",
+				Name:              "foo",
+				ProviderConfigKey: "null",
+				Expressions: map[string]*Expression{
+					"created": {
+						ExpressionData: &ExpressionData{
+							ConstantValue: UnknownConstantValue,
+						},
+					},
+					"triggers": {
+						ExpressionData: &ExpressionData{},
+					},
+				},
+				SchemaVersion: 0,
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -133,3 +170,29 @@ func TestUnmarshalExpressions(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalExpressions_constantValue(t *testing.T) {
+	cases := map[string]string{
+		"absent":     `{}`,
+		"null":       `{"constant_value":null}`,
+		"value":      `{"constant_value":"foo"}`,
+		"references": `{"references":["var.foo"]}`,
+	}
+
+	for name, in := range cases {
+		t.Run(name, func(t *testing.T) {
+			var e Expression
+			if err := json.Unmarshal([]byte(in), &e); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := json.Marshal(&e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != in {
+				t.Fatalf("expected %s, got %s", in, out)
+			}
+		})
+	}
+}
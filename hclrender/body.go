@@ -0,0 +1,132 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package hclrender
+
+import (
+	"strings"
+)
+
+// bodyItem is an attribute or block within a body.
+type bodyItem interface {
+	isBodyItem()
+}
+
+// attribute is an attribute within a body. The value is rendered
+// expression source, which may span multiple lines.
+type attribute struct {
+	name  string
+	value string
+}
+
+func (attribute) isBodyItem() {}
+
+// block is a block within a body, or at the top level of a file.
+type block struct {
+	typ    string
+	labels []string
+	body   []bodyItem
+}
+
+func (block) isBodyItem() {}
+
+// separator is a blank line between groups of attributes.
+type separator struct{}
+
+func (separator) isBodyItem() {}
+
+// writeBlocks writes each block, separated by blank lines.
+func writeBlocks(b *strings.Builder, blocks []block) {
+	for i, blk := range blocks {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeBlock(b, blk, 0)
+	}
+}
+
+func writeBlock(b *strings.Builder, blk block, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	b.WriteString(indent)
+	b.WriteString(blk.typ)
+	for _, label := range blk.labels {
+		b.WriteString(" ")
+		b.WriteString(quoteString(label))
+	}
+
+	if len(blk.body) == 0 {
+		b.WriteString(" {}\n")
+		return
+	}
+
+	b.WriteString(" {\n")
+	writeBody(b, blk.body, depth+1)
+	b.WriteString(indent)
+	b.WriteString("}\n")
+}
+
+// writeBody writes the items of a body, aligning the equals signs of
+// consecutive single-line attributes as terraform fmt does. Blocks are
+// separated from the items around them by blank lines.
+func writeBody(b *strings.Builder, items []bodyItem, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	for i := 0; i < len(items); i++ {
+		if i > 0 && blankLineBetween(items[i-1], items[i], i-1 == 0) {
+			b.WriteString("\n")
+		}
+
+		switch item := items[i].(type) {
+		case block:
+			writeBlock(b, item, depth)
+		case attribute:
+			// Find the run of attributes aligned with this one, which
+			// ends after the first multi-line value.
+			end := i
+			width := 0
+			for ; end < len(items); end++ {
+				attr, ok := items[end].(attribute)
+				if !ok {
+					break
+				}
+				if len(attr.name) > width {
+					width = len(attr.name)
+				}
+				if strings.Contains(attr.value, "\n") {
+					end++
+					break
+				}
+			}
+
+			for j := i; j < end; j++ {
+				attr := items[j].(attribute)
+				b.WriteString(indent)
+				b.WriteString(attr.name)
+				b.WriteString(strings.Repeat(" ", width-len(attr.name)))
+				b.WriteString(" = ")
+				b.WriteString(indentLines(attr.value, indent))
+				b.WriteString("\n")
+			}
+			i = end - 1
+		}
+	}
+}
+
+func blankLineBetween(prev, cur bodyItem, prevIsFirst bool) bool {
+	if _, ok := cur.(separator); ok {
+		return false
+	}
+	if _, ok := prev.(separator); ok {
+		return !prevIsFirst
+	}
+	_, prevBlock := prev.(block)
+	_, curBlock := cur.(block)
+
+	return prevBlock || curBlock
+}
+
+// indentLines indents every line of s after the first.
+func indentLines(s, indent string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+indent)
+}
@@ -0,0 +1,615 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package hclrender
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// ImportOptions configures RenderImports.
+type ImportOptions struct {
+	// Module is the address of the module to import resources into, such
+	// as "module.migrated", or empty to import them into the root module.
+	// Each resource keeps its address relative to the root module of the
+	// state beneath this module, so aws_instance.web is imported to
+	// module.migrated.aws_instance.web.
+	Module string
+
+	// PreferID uses the id attribute to import resources even if they
+	// have an identity which could be used instead.
+	PreferID bool
+}
+
+// Imports is the configuration rendered by RenderImports.
+type Imports struct {
+	// Imports contains an import block for each resource instance.
+	// Terraform only accepts import blocks in the root module.
+	Imports []byte
+
+	// Resources maps the address of each module, such as
+	// "module.migrated.module.network", to the resource blocks to be added
+	// to it. The root module has an empty address.
+	Resources map[string][]byte
+}
+
+// RenderImports renders import blocks for every managed resource instance
+// in s, along with resource blocks containing their configurable
+// attributes, for importing the resources into another configuration.
+// Deposed objects are ignored, as they cannot be imported.
+//
+// A resource instance is imported by its identity, if it has one and the
+// provider declares an identity schema for the resource type, and
+// otherwise by its id attribute. Only the identity attributes which are
+// required or optional for import are included.
+//
+// Resource blocks include the attributes which are required or optional
+// in the schema of the resource type, excluding write-only attributes and
+// the id attribute if it is computed. Sensitive values are not rendered;
+// they are replaced with null and a comment, to be completed by hand. The
+// instances of a resource using count or for_each share a single block,
+// selecting values which differ between instances by their key. Nested
+// blocks which differ between instances cannot be rendered this way, and
+// cause an error.
+func RenderImports(s *tfjson.State, schemas *tfjson.ProviderSchemas, opts ImportOptions) (*Imports, error) {
+	if s == nil {
+		return nil, errors.New("state is nil")
+	}
+	if err := validateModuleAddress(opts.Module); err != nil {
+		return nil, err
+	}
+
+	result := &Imports{Resources: map[string][]byte{}}
+	if s.Values == nil || s.Values.RootModule == nil {
+		return result, nil
+	}
+
+	var imports []block
+	var groups []*importResource
+	byAddress := map[string]*importResource{}
+	var err error
+	s.Values.RootModule.Walk(func(module *tfjson.StateModule, r *tfjson.StateResource) bool {
+		if r.Mode != tfjson.ManagedResourceMode || r.DeposedKey != "" {
+			return true
+		}
+
+		var ps *tfjson.ProviderSchema
+		ps, err = importProviderSchema(schemas, r)
+		if err != nil {
+			return false
+		}
+
+		var imp block
+		imp, err = importBlock(r, ps, joinAddress(opts.Module, instanceAddress(module, r)), opts.PreferID)
+		if err != nil {
+			return false
+		}
+		imports = append(imports, imp)
+
+		var configModule string
+		configModule, err = stripInstanceKeys(module.Address)
+		if err != nil {
+			return false
+		}
+		configModule = joinAddress(opts.Module, configModule)
+		address := joinAddress(configModule, r.Type+"."+r.Name)
+		group, ok := byAddress[address]
+		if !ok {
+			schema := ps.ResourceSchemas[r.Type]
+			if schema == nil || schema.Block == nil {
+				err = fmt.Errorf("%s: no schema for resource type %q", r.Address, r.Type)
+				return false
+			}
+
+			group = &importResource{
+				address:  address,
+				module:   configModule,
+				resource: r,
+				schema:   schema.Block,
+			}
+			byAddress[address] = group
+			groups = append(groups, group)
+		}
+		group.instances = append(group.instances, r)
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	writeBlocks(&b, imports)
+	result.Imports = []byte(b.String())
+
+	var modules []string
+	blocks := map[string][]block{}
+	for _, group := range groups {
+		blk, err := group.block()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := blocks[group.module]; !ok {
+			modules = append(modules, group.module)
+		}
+		blocks[group.module] = append(blocks[group.module], blk)
+	}
+	for _, module := range modules {
+		var b strings.Builder
+		writeBlocks(&b, blocks[module])
+		result.Resources[module] = []byte(b.String())
+	}
+
+	return result, nil
+}
+
+func importProviderSchema(schemas *tfjson.ProviderSchemas, r *tfjson.StateResource) (*tfjson.ProviderSchema, error) {
+	addr, err := r.ProviderAddress()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", r.Address, err)
+	}
+
+	ps := schemas.ProviderSchema(addr)
+	if ps == nil {
+		return nil, fmt.Errorf("%s: no schema for provider %q", r.Address, r.ProviderName)
+	}
+
+	return ps, nil
+}
+
+func importBlock(r *tfjson.StateResource, ps *tfjson.ProviderSchema, to string, preferID bool) (block, error) {
+	blk := block{
+		typ:  "import",
+		body: []bodyItem{attribute{name: "to", value: to}},
+	}
+
+	if identity := ps.ResourceIdentitySchemas[r.Type]; !preferID && identity != nil && len(r.IdentityValues) > 0 {
+		values := map[string]interface{}{}
+		for name, attr := range identity.Attributes {
+			v := r.IdentityValues[name]
+			switch {
+			case attr == nil:
+			case attr.RequiredForImport && v == nil:
+				return block{}, fmt.Errorf("%s: identity attribute %q is required for import, but has no value", r.Address, name)
+			case (attr.RequiredForImport || attr.OptionalForImport) && v != nil:
+				values[name] = v
+			}
+		}
+
+		blk.body = append(blk.body, attribute{name: "identity", value: RenderValue(values)})
+		return blk, nil
+	}
+
+	id, ok := r.AttributeValues["id"].(string)
+	if !ok || id == "" {
+		return block{}, fmt.Errorf("%s: resource has neither an identity nor an id to import by", r.Address)
+	}
+	blk.body = append(blk.body, attribute{name: "id", value: quoteString(id)})
+
+	return blk, nil
+}
+
+// importResource is a resource in configuration, and the instances of it
+// being imported.
+type importResource struct {
+	address   string
+	module    string
+	resource  *tfjson.StateResource
+	schema    *tfjson.SchemaBlock
+	instances []*tfjson.StateResource
+}
+
+// block returns the resource block configuring every instance.
+func (ir *importResource) block() (block, error) {
+	blk := block{typ: "resource", labels: []string{ir.resource.Type, ir.resource.Name}}
+
+	type instanceBody struct {
+		key    string
+		attrs  map[string]string
+		blocks []bodyItem
+	}
+
+	// Each instance is identified in lookups by its key, which is the
+	// string key for for_each, and the index for count.
+	var forEach, count, single bool
+	maxIndex := -1
+	var names []string
+	bodies := make([]instanceBody, len(ir.instances))
+	for i, r := range ir.instances {
+		body := instanceBody{attrs: map[string]string{}}
+
+		switch index := r.Index.(type) {
+		case nil:
+			single = true
+		case string:
+			forEach = true
+			body.key = index
+		default:
+			n, ok := indexInt(index)
+			if !ok {
+				return block{}, fmt.Errorf("%s: unsupported instance key %#v", r.Address, r.Index)
+			}
+			count = true
+			body.key = strconv.Itoa(n)
+			if n > maxIndex {
+				maxIndex = n
+			}
+		}
+		if single && (forEach || count) || forEach && count {
+			return block{}, fmt.Errorf("%s: instances of the resource have inconsistent keys", ir.address)
+		}
+
+		var sensitive map[string]interface{}
+		if len(r.SensitiveValues) > 0 {
+			if err := json.Unmarshal(r.SensitiveValues, &sensitive); err != nil {
+				return block{}, fmt.Errorf("%s: invalid sensitive values: %w", r.Address, err)
+			}
+		}
+
+		items := configItems(ir.schema, r.AttributeValues, sensitive)
+		for _, item := range items {
+			switch item := item.(type) {
+			case attribute:
+				if !containsName(names, item.name) {
+					names = append(names, item.name)
+				}
+				body.attrs[item.name] = item.value
+			case block:
+				body.blocks = append(body.blocks, item)
+			}
+		}
+		bodies[i] = body
+	}
+	sort.Strings(names)
+
+	switch {
+	case forEach:
+		keys := make([]string, 0, len(bodies))
+		for _, body := range bodies {
+			if !containsName(keys, body.key) {
+				keys = append(keys, body.key)
+			}
+		}
+		sort.Strings(keys)
+
+		quoted := make([]string, len(keys))
+		for i, key := range keys {
+			quoted[i] = quoteString(key)
+		}
+		blk.body = append(blk.body, attribute{name: "for_each", value: "toset([" + strings.Join(quoted, ", ") + "])"}, separator{})
+	case count:
+		blk.body = append(blk.body, attribute{name: "count", value: strconv.Itoa(maxIndex + 1)}, separator{})
+	}
+
+	if provider, ok := importProviderArgument(ir.resource); ok {
+		blk.body = append(blk.body, attribute{name: "provider", value: provider}, separator{})
+	}
+
+	for _, name := range names {
+		var value string
+		values := map[string]string{}
+		same := true
+		for i, body := range bodies {
+			v, ok := body.attrs[name]
+			if !ok {
+				v = "null"
+			}
+			if prev, ok := values[body.key]; ok && prev != v {
+				return block{}, fmt.Errorf("%s: attribute %q differs between instances with the same key", ir.address, name)
+			}
+			values[body.key] = v
+
+			if i == 0 {
+				value = v
+			}
+			same = same && v == value
+		}
+
+		switch {
+		case same && (!count || len(values) == maxIndex+1):
+		case forEach:
+			value = lookupObject(values) + "[each.key]"
+		default:
+			elems := make([]string, maxIndex+1)
+			for i := range elems {
+				if elems[i] = values[strconv.Itoa(i)]; elems[i] == "" {
+					elems[i] = "null"
+				}
+			}
+			value = joinList(elems, true) + "[count.index]"
+		}
+		blk.body = append(blk.body, attribute{name: name, value: value})
+	}
+
+	// Nested blocks must be the same in every instance, as selecting them
+	// by key would require dynamic blocks.
+	var nested string
+	for i, body := range bodies {
+		var b strings.Builder
+		writeBody(&b, body.blocks, 0)
+		if i > 0 && b.String() != nested {
+			return block{}, fmt.Errorf("%s: nested blocks differ between instances, which is not supported", ir.address)
+		}
+		nested = b.String()
+	}
+	blk.body = append(blk.body, bodies[0].blocks...)
+
+	return blk, nil
+}
+
+// configItems returns the configurable attributes and nested blocks of a
+// block with the given schema, from its values in state.
+func configItems(schema *tfjson.SchemaBlock, values, sensitive map[string]interface{}) []bodyItem {
+	var attrs, blocks []bodyItem
+	for _, name := range sortedKeys(schema.Attributes) {
+		attr := schema.Attributes[name]
+		if attr == nil || !configurable(name, attr) {
+			continue
+		}
+
+		v := values[name]
+		if v == nil {
+			continue
+		}
+		if attr.Sensitive || containsTrue(sensitive[name]) {
+			attrs = append(attrs, attribute{name: objectKey(name), value: "null /* sensitive */"})
+			continue
+		}
+
+		attrs = append(attrs, attribute{name: objectKey(name), value: RenderValue(configValue(attr.AttributeNestedType, v))})
+	}
+
+	for _, name := range sortedKeys(schema.NestedBlocks) {
+		bt := schema.NestedBlocks[name]
+		if bt == nil || bt.Block == nil {
+			continue
+		}
+
+		switch bt.NestingMode {
+		case tfjson.SchemaNestingModeList, tfjson.SchemaNestingModeSet:
+			l, _ := values[name].([]interface{})
+			sl, _ := sensitive[name].([]interface{})
+			for i, elem := range l {
+				obj, _ := elem.(map[string]interface{})
+				var s map[string]interface{}
+				if i < len(sl) {
+					s, _ = sl[i].(map[string]interface{})
+				}
+				blocks = append(blocks, block{typ: name, body: configItems(bt.Block, obj, s)})
+			}
+		case tfjson.SchemaNestingModeMap:
+			m, _ := values[name].(map[string]interface{})
+			sm, _ := sensitive[name].(map[string]interface{})
+			for _, key := range sortedKeys(m) {
+				obj, _ := m[key].(map[string]interface{})
+				s, _ := sm[key].(map[string]interface{})
+				blocks = append(blocks, block{typ: name, labels: []string{key}, body: configItems(bt.Block, obj, s)})
+			}
+		default:
+			obj, ok := values[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			s, _ := sensitive[name].(map[string]interface{})
+			blocks = append(blocks, block{typ: name, body: configItems(bt.Block, obj, s)})
+		}
+	}
+
+	return append(attrs, blocks...)
+}
+
+// configurable returns true if the attribute can be set in configuration.
+// The id attribute is computed by the provider, but is optional in the
+// schemas of many older providers for historical reasons.
+func configurable(name string, attr *tfjson.SchemaAttribute) bool {
+	if attr.WriteOnly || name == "id" && attr.Computed {
+		return false
+	}
+
+	return attr.Required || attr.Optional
+}
+
+// configValue returns v without any nested attributes which are not
+// configurable.
+func configValue(nested *tfjson.SchemaNestedAttributeType, v interface{}) interface{} {
+	if nested == nil || v == nil {
+		return v
+	}
+
+	object := func(v interface{}) interface{} {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+
+		result := map[string]interface{}{}
+		for name, attr := range nested.Attributes {
+			if attr != nil && configurable(name, attr) && obj[name] != nil {
+				result[name] = configValue(attr.AttributeNestedType, obj[name])
+			}
+		}
+		return result
+	}
+
+	switch nested.NestingMode {
+	case tfjson.SchemaNestingModeList, tfjson.SchemaNestingModeSet:
+		l, ok := v.([]interface{})
+		if !ok {
+			return v
+		}
+		result := make([]interface{}, len(l))
+		for i, elem := range l {
+			result[i] = object(elem)
+		}
+		return result
+	case tfjson.SchemaNestingModeMap:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		result := make(map[string]interface{}, len(m))
+		for key, elem := range m {
+			result[key] = object(elem)
+		}
+		return result
+	default:
+		return object(v)
+	}
+}
+
+// containsTrue returns true if a sensitivity structure from
+// StateResource.SensitiveValues marks any value as sensitive.
+func containsTrue(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case []interface{}:
+		for _, elem := range v {
+			if containsTrue(elem) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, elem := range v {
+			if containsTrue(elem) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// importProviderArgument returns the value of the provider meta-argument
+// of the resource, if its provider is not the one implied by its type.
+// The provider is assumed to use its type as its local name.
+func importProviderArgument(r *tfjson.StateResource) (string, bool) {
+	addr, err := r.ProviderAddress()
+	if err != nil {
+		return "", false
+	}
+
+	implied, _, _ := strings.Cut(r.Type, "_")
+	if addr.Type == implied {
+		return "", false
+	}
+
+	return addr.Type, true
+}
+
+// lookupObject renders an object of rendered values, keyed by instance
+// key.
+func lookupObject(values map[string]string) string {
+	keys := sortedKeys(values)
+	items := make([]bodyItem, len(keys))
+	for i, key := range keys {
+		items[i] = attribute{name: objectKey(key), value: values[key]}
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	writeBody(&b, items, 1)
+	b.WriteString("}")
+
+	return b.String()
+}
+
+func indexInt(v interface{}) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case float64:
+		if v >= 0 && v == float64(int(v)) {
+			return int(v), true
+		}
+	case json.Number:
+		if n, err := strconv.Atoi(v.String()); err == nil && n >= 0 {
+			return n, true
+		}
+	}
+
+	return 0, false
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// instanceAddress returns the absolute address of the resource instance.
+// This is built from its parts, as Terraform 0.12 does not include the
+// module or instance key in StateResource.Address.
+func instanceAddress(module *tfjson.StateModule, r *tfjson.StateResource) string {
+	address := r.Type + "." + r.Name
+	switch index := r.Index.(type) {
+	case nil:
+	case string:
+		address += "[" + quoteString(index) + "]"
+	default:
+		address += "[" + RenderValue(index) + "]"
+	}
+
+	return joinAddress(module.Address, address)
+}
+
+// joinAddress joins an address to the module address prefix.
+func joinAddress(prefix, address string) string {
+	switch {
+	case prefix == "":
+		return address
+	case address == "":
+		return prefix
+	}
+
+	return prefix + "." + address
+}
+
+// stripInstanceKeys removes the instance keys from a module instance
+// address, such as `module.a["x"].module.b[0]`, giving the address of the
+// module in configuration.
+func stripInstanceKeys(address string) (string, error) {
+	modules, rest, err := tfjson.SplitModuleAddress(address)
+	if err != nil {
+		return "", err
+	}
+	if rest != "" {
+		return "", fmt.Errorf("invalid module address %q", address)
+	}
+
+	result := make([]string, len(modules))
+	for i, m := range modules {
+		result[i] = m.Subject()
+	}
+
+	return strings.Join(result, "."), nil
+}
+
+func validateModuleAddress(address string) error {
+	if address == "" {
+		return nil
+	}
+
+	steps := strings.Split(address, ".")
+	if len(steps)%2 != 0 {
+		return fmt.Errorf("invalid module address %q", address)
+	}
+	for i := 0; i < len(steps); i += 2 {
+		if steps[i] != "module" || !identifierRe.MatchString(steps[i+1]) {
+			return fmt.Errorf("invalid module address %q", address)
+		}
+	}
+
+	return nil
+}
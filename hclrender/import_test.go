@@ -0,0 +1,391 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package hclrender
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/sebdah/goldie"
+)
+
+func testReadJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testRenderImports(t *testing.T, imports *Imports) []byte {
+	t.Helper()
+
+	var b strings.Builder
+	b.Write(imports.Imports)
+	for _, module := range sortedKeys(imports.Resources) {
+		name := module
+		if name == "" {
+			name = "root module"
+		}
+		b.WriteString("\n# " + name + "\n")
+		b.Write(imports.Resources[module])
+	}
+
+	return []byte(b.String())
+}
+
+func TestRenderImports(t *testing.T) {
+	dir := filepath.Join("..", "testdata", "no_changes")
+
+	var state *tfjson.State
+	testReadJSON(t, filepath.Join(dir, "state.json"), &state)
+	var schemas *tfjson.ProviderSchemas
+	testReadJSON(t, filepath.Join(dir, "schemas.json"), &schemas)
+
+	for name, module := range map[string]string{
+		"no_changes_imports":        "",
+		"no_changes_imports_module": "module.migrated",
+	} {
+		t.Run(name, func(t *testing.T) {
+			imports, err := RenderImports(state, schemas, ImportOptions{Module: module})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goldie.Assert(t, name, testRenderImports(t, imports))
+		})
+	}
+}
+
+func testImportSchemas() *tfjson.ProviderSchemas {
+	return &tfjson.ProviderSchemas{
+		Schemas: map[string]*tfjson.ProviderSchema{
+			"registry.terraform.io/hashicorp/example": {
+				ResourceSchemas: map[string]*tfjson.Schema{
+					"example_thing": {Block: &tfjson.SchemaBlock{
+						Attributes: map[string]*tfjson.SchemaAttribute{
+							"id":       {Computed: true, Optional: true},
+							"name":     {Required: true},
+							"size":     {Optional: true, Computed: true},
+							"arn":      {Computed: true},
+							"password": {Optional: true, Sensitive: true},
+							"token":    {Optional: true, WriteOnly: true},
+							"tags":     {Optional: true},
+							"settings": {
+								Optional: true,
+								AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+									NestingMode: tfjson.SchemaNestingModeSingle,
+									Attributes: map[string]*tfjson.SchemaAttribute{
+										"mode":   {Optional: true},
+										"status": {Computed: true},
+									},
+								},
+							},
+						},
+						NestedBlocks: map[string]*tfjson.SchemaBlockType{
+							"rule": {
+								NestingMode: tfjson.SchemaNestingModeList,
+								Block: &tfjson.SchemaBlock{
+									Attributes: map[string]*tfjson.SchemaAttribute{
+										"port":  {Required: true},
+										"state": {Computed: true},
+									},
+								},
+							},
+						},
+					}},
+				},
+				ResourceIdentitySchemas: map[string]*tfjson.IdentitySchema{
+					"example_thing": {
+						Attributes: map[string]*tfjson.IdentityAttribute{
+							"name":   {RequiredForImport: true},
+							"region": {OptionalForImport: true},
+							"other":  {},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func testImportState(resources ...*tfjson.StateResource) *tfjson.State {
+	for _, r := range resources {
+		r.Mode = tfjson.ManagedResourceMode
+		r.Type = "example_thing"
+		r.ProviderName = "registry.terraform.io/hashicorp/example"
+	}
+
+	return &tfjson.State{
+		Values: &tfjson.StateValues{
+			RootModule: &tfjson.StateModule{Resources: resources},
+		},
+	}
+}
+
+func TestRenderImports_identity(t *testing.T) {
+	state := testImportState(&tfjson.StateResource{
+		Address: "example_thing.a",
+		Name:    "a",
+		AttributeValues: map[string]interface{}{
+			"id":       "a-123",
+			"name":     "a",
+			"size":     float64(2),
+			"arn":      "arn:a",
+			"password": "hunter2",
+			"tags":     map[string]interface{}{"env": "dev"},
+			"settings": map[string]interface{}{"mode": "fast", "status": "ok"},
+			"rule": []interface{}{
+				map[string]interface{}{"port": float64(80), "state": "on"},
+			},
+		},
+		IdentityValues: map[string]interface{}{"name": "a", "region": "us-east-1", "other": "x"},
+	})
+
+	imports, err := RenderImports(state, testImportSchemas(), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `import {
+  to       = example_thing.a
+  identity = {
+    name   = "a"
+    region = "us-east-1"
+  }
+}
+
+# root module
+resource "example_thing" "a" {
+  name     = "a"
+  password = null /* sensitive */
+  settings = {
+    mode = "fast"
+  }
+  size = 2
+  tags = {
+    env = "dev"
+  }
+
+  rule {
+    port = 80
+  }
+}
+`
+	if diff := cmp.Diff(expected, string(testRenderImports(t, imports))); diff != "" {
+		t.Fatalf("unexpected output (-want +got):\n%s", diff)
+	}
+
+	imports, err = RenderImports(state, testImportSchemas(), ImportOptions{PreferID: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(imports.Imports), `id = "a-123"`) {
+		t.Fatalf("expected import by id, got:\n%s", imports.Imports)
+	}
+}
+
+func TestRenderImports_forEach(t *testing.T) {
+	state := testImportState(
+		&tfjson.StateResource{
+			Address:         `example_thing.each["x"]`,
+			Name:            "each",
+			Index:           "x",
+			AttributeValues: map[string]interface{}{"id": "1", "name": "x", "size": float64(1)},
+		},
+		&tfjson.StateResource{
+			Address:         `example_thing.each["y"]`,
+			Name:            "each",
+			Index:           "y",
+			AttributeValues: map[string]interface{}{"id": "2", "name": "y", "size": float64(1)},
+		},
+	)
+	state.Values.RootModule.ChildModules = []*tfjson.StateModule{
+		{
+			Address: `module.child["a"]`,
+			Resources: []*tfjson.StateResource{
+				{
+					Address:         `module.child["a"].example_thing.count[1]`,
+					Mode:            tfjson.ManagedResourceMode,
+					Type:            "example_thing",
+					Name:            "count",
+					Index:           json.Number("1"),
+					ProviderName:    "registry.terraform.io/hashicorp/example",
+					AttributeValues: map[string]interface{}{"id": "3", "name": "c"},
+				},
+			},
+		},
+	}
+
+	imports, err := RenderImports(state, testImportSchemas(), ImportOptions{Module: "module.moved"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `import {
+  to = module.moved.example_thing.each["x"]
+  id = "1"
+}
+
+import {
+  to = module.moved.example_thing.each["y"]
+  id = "2"
+}
+
+import {
+  to = module.moved.module.child["a"].example_thing.count[1]
+  id = "3"
+}
+
+# module.moved
+resource "example_thing" "each" {
+  for_each = toset(["x", "y"])
+
+  name = {
+    x = "x"
+    y = "y"
+  }[each.key]
+  size = 1
+}
+
+# module.moved.module.child
+resource "example_thing" "count" {
+  count = 2
+
+  name = [
+    null,
+    "c",
+  ][count.index]
+}
+`
+	if diff := cmp.Diff(expected, string(testRenderImports(t, imports))); diff != "" {
+		t.Fatalf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestRenderImports_countSensitive(t *testing.T) {
+	state := testImportState(
+		&tfjson.StateResource{
+			Address:         "example_thing.count[0]",
+			Name:            "count",
+			Index:           json.Number("0"),
+			AttributeValues: map[string]interface{}{"id": "1", "name": "secret"},
+			SensitiveValues: json.RawMessage(`{"name":true}`),
+		},
+		&tfjson.StateResource{
+			Address:         "example_thing.count[1]",
+			Name:            "count",
+			Index:           json.Number("1"),
+			AttributeValues: map[string]interface{}{"id": "2", "name": "plain"},
+		},
+	)
+
+	imports, err := RenderImports(state, testImportSchemas(), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goldie.Assert(t, "count_sensitive_imports", testRenderImports(t, imports))
+}
+
+func TestRenderImports_errors(t *testing.T) {
+	cases := map[string]struct {
+		state *tfjson.State
+		opts  ImportOptions
+	}{
+		"invalid module": {
+			state: testImportState(),
+			opts:  ImportOptions{Module: "migrated"},
+		},
+		"no id": {
+			state: testImportState(&tfjson.StateResource{
+				Address:         "example_thing.a",
+				Name:            "a",
+				AttributeValues: map[string]interface{}{"name": "a"},
+			}),
+		},
+		"missing required identity": {
+			state: testImportState(&tfjson.StateResource{
+				Address:         "example_thing.a",
+				Name:            "a",
+				AttributeValues: map[string]interface{}{"id": "a"},
+				IdentityValues:  map[string]interface{}{"region": "us-east-1"},
+			}),
+		},
+		"differing nested blocks": {
+			state: testImportState(
+				&tfjson.StateResource{
+					Address: "example_thing.a[0]",
+					Name:    "a",
+					Index:   float64(0),
+					AttributeValues: map[string]interface{}{
+						"id":   "0",
+						"rule": []interface{}{map[string]interface{}{"port": float64(80)}},
+					},
+				},
+				&tfjson.StateResource{
+					Address: "example_thing.a[1]",
+					Name:    "a",
+					Index:   float64(1),
+					AttributeValues: map[string]interface{}{
+						"id":   "1",
+						"rule": []interface{}{map[string]interface{}{"port": float64(443)}},
+					},
+				},
+			),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := RenderImports(tc.state, testImportSchemas(), tc.opts); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+
+	state := testImportState(&tfjson.StateResource{
+		Address:         "example_thing.a",
+		Name:            "a",
+		AttributeValues: map[string]interface{}{"id": "a"},
+	})
+	if _, err := RenderImports(state, nil, ImportOptions{}); err == nil {
+		t.Fatal("expected error without schemas")
+	}
+}
+
+func TestStripInstanceKeys(t *testing.T) {
+	cases := map[string]string{
+		"":                              "",
+		"module.a":                      "module.a",
+		`module.a["x"].module.b[0]`:     "module.a.module.b",
+		`module.a["x]\"."].module.b[1]`: "module.a.module.b",
+	}
+
+	for address, expected := range cases {
+		got, err := stripInstanceKeys(address)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", address, err)
+		} else if got != expected {
+			t.Errorf("%s: expected %q, got %q", address, expected, got)
+		}
+	}
+
+	for _, address := range []string{"module", "module.a.aws_instance.b", `module.a["x`} {
+		if _, err := stripInstanceKeys(address); err == nil {
+			t.Errorf("%s: expected error", address)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package hclrender renders the configuration representation in the
+// tfjson package back into HCL source, for reports and review.
+//
+// The JSON representation of configuration does not preserve the
+// original source of expressions, only their constant values or the
+// references they make, so the result is an approximation of the
+// original configuration. Constant values are rendered exactly, and
+// anything else is followed by a comment beginning with
+// UnrenderableMarker: expressions which only make a single reference are
+// rendered as that reference, and the rest as null.
+//
+// RenderImports renders import blocks and matching resource configuration
+// for the resources in a state, for moving them to another configuration.
+package hclrender
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// RenderConfig renders the module at the given address within c, such as
+// "module.network", or the root module if module is empty. In addition to
+// the contents rendered by RenderModule, this includes the provider
+// configurations declared in the module, and their requirements.
+func RenderConfig(c *tfjson.Config, module string) ([]byte, error) {
+	if c == nil {
+		return nil, errors.New("config is nil")
+	}
+
+	m, err := findModule(c.RootModule, module)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []block
+	if blk, ok := requiredProvidersBlock(c, module); ok {
+		blocks = append(blocks, blk)
+	}
+	blocks = append(blocks, providerBlocks(c, module)...)
+	blocks = append(blocks, moduleBlocks(m)...)
+
+	var b strings.Builder
+	writeBlocks(&b, blocks)
+
+	return []byte(b.String()), nil
+}
+
+// RenderModule renders the variables, resources, module calls and
+// outputs of m. Child modules are not included, but can be rendered
+// separately from their ModuleCall.
+func RenderModule(m *tfjson.ConfigModule) ([]byte, error) {
+	if m == nil {
+		return nil, errors.New("module is nil")
+	}
+
+	var b strings.Builder
+	writeBlocks(&b, moduleBlocks(m))
+
+	return []byte(b.String()), nil
+}
+
+func findModule(root *tfjson.ConfigModule, address string) (*tfjson.ConfigModule, error) {
+	if root == nil {
+		return nil, errors.New("root module is nil")
+	}
+	if address == "" {
+		return root, nil
+	}
+
+	steps := strings.Split(address, ".")
+	if len(steps)%2 != 0 {
+		return nil, fmt.Errorf("invalid module address %q", address)
+	}
+
+	m := root
+	for i := 0; i < len(steps); i += 2 {
+		if steps[i] != "module" {
+			return nil, fmt.Errorf("invalid module address %q", address)
+		}
+		call, ok := m.ModuleCalls[steps[i+1]]
+		if !ok || call == nil || call.Module == nil {
+			return nil, fmt.Errorf("module %q not found", address)
+		}
+		m = call.Module
+	}
+
+	return m, nil
+}
+
+// moduleProviderConfigs returns the provider configurations declared in
+// the module at address, ordered by key.
+func moduleProviderConfigs(c *tfjson.Config, address string) []*tfjson.ProviderConfig {
+	keys := make([]string, 0, len(c.ProviderConfigs))
+	for key, pc := range c.ProviderConfigs {
+		if pc != nil && pc.Ref().Module == address {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	result := make([]*tfjson.ProviderConfig, len(keys))
+	for i, key := range keys {
+		result[i] = c.ProviderConfigs[key]
+	}
+
+	return result
+}
+
+func requiredProvidersBlock(c *tfjson.Config, address string) (block, bool) {
+	var names []string
+	requirements := map[string]map[string]interface{}{}
+	for _, pc := range moduleProviderConfigs(c, address) {
+		req := requirements[pc.Name]
+		if req == nil {
+			req = map[string]interface{}{}
+		}
+
+		if addr, err := pc.ProviderAddress(); err == nil && !addr.IsLegacy() {
+			req["source"] = addr.ForDisplay()
+		}
+		if pc.VersionConstraint != "" {
+			req["version"] = pc.VersionConstraint
+		}
+
+		if len(req) > 0 && requirements[pc.Name] == nil {
+			names = append(names, pc.Name)
+			requirements[pc.Name] = req
+		}
+	}
+	if len(names) == 0 {
+		return block{}, false
+	}
+	sort.Strings(names)
+
+	var body []bodyItem
+	for _, name := range names {
+		body = append(body, attribute{name: name, value: RenderValue(requirements[name])})
+	}
+
+	return block{
+		typ: "terraform",
+		body: []bodyItem{
+			block{typ: "required_providers", body: body},
+		},
+	}, true
+}
+
+// providerBlocks returns a provider block for each configuration declared
+// in the module. Terraform also records a configuration for providers
+// which are only required, which have neither an alias nor any
+// arguments; these are not rendered as blocks.
+func providerBlocks(c *tfjson.Config, address string) []block {
+	var blocks []block
+	for _, pc := range moduleProviderConfigs(c, address) {
+		if pc.Alias == "" && len(pc.Expressions) == 0 {
+			continue
+		}
+
+		var body []bodyItem
+		if pc.Alias != "" {
+			body = append(body, attribute{name: "alias", value: quoteString(pc.Alias)}, separator{})
+		}
+		body = append(body, expressionItems(pc.Expressions)...)
+
+		blocks = append(blocks, block{
+			typ:    "provider",
+			labels: []string{pc.Name},
+			body:   body,
+		})
+	}
+
+	return blocks
+}
+
+func moduleBlocks(m *tfjson.ConfigModule) []block {
+	var blocks []block
+
+	for _, name := range sortedKeys(m.Variables) {
+		blocks = append(blocks, variableBlock(name, m.Variables[name]))
+	}
+
+	for _, r := range m.Resources {
+		if r != nil {
+			blocks = append(blocks, resourceBlock(r))
+		}
+	}
+
+	for _, name := range sortedKeys(m.ModuleCalls) {
+		blocks = append(blocks, moduleCallBlock(name, m.ModuleCalls[name]))
+	}
+
+	for _, name := range sortedKeys(m.Outputs) {
+		blocks = append(blocks, outputBlock(name, m.Outputs[name]))
+	}
+
+	return blocks
+}
+
+func variableBlock(name string, v *tfjson.ConfigVariable) block {
+	blk := block{typ: "variable", labels: []string{name}}
+	if v == nil {
+		return blk
+	}
+
+	if v.Description != "" {
+		blk.body = append(blk.body, attribute{name: "description", value: quoteString(v.Description)})
+	}
+	if v.Default != nil {
+		blk.body = append(blk.body, attribute{name: "default", value: RenderValue(v.Default)})
+	}
+	if v.Sensitive {
+		blk.body = append(blk.body, attribute{name: "sensitive", value: "true"})
+	}
+
+	return blk
+}
+
+func resourceBlock(r *tfjson.ConfigResource) block {
+	blk := block{typ: "resource", labels: []string{r.Type, r.Name}}
+	if r.Mode == tfjson.DataResourceMode {
+		blk.typ = "data"
+	}
+
+	blk.body = append(blk.body, metaArguments(r.CountExpression, r.ForEachExpression)...)
+	if provider, ok := providerArgument(r); ok {
+		blk.body = append(blk.body, attribute{name: "provider", value: provider}, separator{})
+	}
+
+	blk.body = append(blk.body, expressionItems(r.Expressions)...)
+
+	if len(r.DependsOn) > 0 {
+		blk.body = append(blk.body, separator{}, attribute{name: "depends_on", value: renderTraversals(r.DependsOn)})
+	}
+
+	for _, p := range r.Provisioners {
+		if p == nil {
+			continue
+		}
+		blk.body = append(blk.body, block{
+			typ:    "provisioner",
+			labels: []string{p.Type},
+			body:   expressionItems(p.Expressions),
+		})
+	}
+
+	return blk
+}
+
+// providerArgument returns the value of the provider meta-argument of the
+// resource, if it does not use the default configuration of the provider
+// implied by its type.
+func providerArgument(r *tfjson.ConfigResource) (string, bool) {
+	if r.ProviderConfigKey == "" {
+		return "", false
+	}
+
+	ref, err := tfjson.ParseProviderConfigKey(r.ProviderConfigKey)
+	if err != nil {
+		return "", false
+	}
+
+	implied, _, _ := strings.Cut(r.Type, "_")
+	if ref.Alias == "" && ref.LocalName == implied {
+		return "", false
+	}
+
+	provider := ref.LocalName
+	if ref.Alias != "" {
+		provider += "." + ref.Alias
+	}
+
+	return provider, true
+}
+
+func moduleCallBlock(name string, mc *tfjson.ModuleCall) block {
+	blk := block{typ: "module", labels: []string{name}}
+	if mc == nil {
+		return blk
+	}
+
+	blk.body = append(blk.body, attribute{name: "source", value: quoteString(mc.Source)})
+	if mc.VersionConstraint != "" {
+		blk.body = append(blk.body, attribute{name: "version", value: quoteString(mc.VersionConstraint)})
+	}
+	blk.body = append(blk.body, separator{})
+
+	blk.body = append(blk.body, metaArguments(mc.CountExpression, mc.ForEachExpression)...)
+	blk.body = append(blk.body, expressionItems(mc.Expressions)...)
+
+	if len(mc.DependsOn) > 0 {
+		blk.body = append(blk.body, separator{}, attribute{name: "depends_on", value: renderTraversals(mc.DependsOn)})
+	}
+
+	return blk
+}
+
+func outputBlock(name string, o *tfjson.ConfigOutput) block {
+	blk := block{typ: "output", labels: []string{name}}
+	if o == nil {
+		return blk
+	}
+
+	if o.Description != "" {
+		blk.body = append(blk.body, attribute{name: "description", value: quoteString(o.Description)})
+	}
+	blk.body = append(blk.body, attribute{name: "value", value: RenderExpression(o.Expression)})
+	if o.Sensitive {
+		blk.body = append(blk.body, attribute{name: "sensitive", value: "true"})
+	}
+
+	if len(o.DependsOn) > 0 {
+		blk.body = append(blk.body, separator{}, attribute{name: "depends_on", value: renderTraversals(o.DependsOn)})
+	}
+
+	return blk
+}
+
+// metaArguments returns the count and for_each arguments, followed by a
+// separator from the remaining arguments.
+func metaArguments(count, forEach *tfjson.Expression) []bodyItem {
+	var items []bodyItem
+	if count != nil {
+		items = append(items, attribute{name: "count", value: RenderExpression(count)})
+	}
+	if forEach != nil {
+		items = append(items, attribute{name: "for_each", value: RenderExpression(forEach)})
+	}
+	if len(items) > 0 {
+		items = append(items, separator{})
+	}
+
+	return items
+}
+
+// expressionItems returns the attributes and nested blocks of a body,
+// with attributes first, each ordered by name.
+func expressionItems(exprs map[string]*tfjson.Expression) []bodyItem {
+	var attrs, blocks []bodyItem
+	for _, name := range sortedKeys(exprs) {
+		e := exprs[name]
+		if e != nil && e.ExpressionData != nil && len(e.NestedBlocks) > 0 {
+			for _, nested := range e.NestedBlocks {
+				blocks = append(blocks, block{typ: name, body: expressionItems(nested)})
+			}
+			continue
+		}
+
+		attrs = append(attrs, attribute{name: objectKey(name), value: RenderExpression(e)})
+	}
+
+	return append(attrs, blocks...)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
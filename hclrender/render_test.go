@@ -0,0 +1,241 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package hclrender
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/sebdah/goldie"
+)
+
+const testDataDir = "testdata"
+
+func init() {
+	goldie.FixtureDir = testDataDir
+}
+
+func testReadPlan(t *testing.T, name string) *tfjson.Plan {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("..", "testdata", name, "plan.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var plan *tfjson.Plan
+	if err := json.NewDecoder(f).Decode(&plan); err != nil {
+		t.Fatal(err)
+	}
+
+	return plan
+}
+
+func TestRenderConfig(t *testing.T) {
+	cases := []struct {
+		fixture string
+		module  string
+		golden  string
+	}{
+		{fixture: "120_basic", golden: "120_basic"},
+		{fixture: "basic", golden: "basic"},
+		{fixture: "basic", module: "module.foo", golden: "basic_module_foo"},
+		{fixture: "has_checks", module: "module.files", golden: "has_checks_module_files"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			plan := testReadPlan(t, tc.fixture)
+
+			got, err := RenderConfig(plan.Config, tc.module)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goldie.Assert(t, tc.golden, got)
+		})
+	}
+}
+
+func TestRenderConfig_moduleNotFound(t *testing.T) {
+	plan := testReadPlan(t, "basic")
+
+	for _, module := range []string{"module.bar", "module.foo.module.bar", "foo"} {
+		if _, err := RenderConfig(plan.Config, module); err == nil {
+			t.Errorf("%q: expected error", module)
+		}
+	}
+}
+
+func TestRenderModule(t *testing.T) {
+	m := &tfjson.ConfigModule{
+		Resources: []*tfjson.ConfigResource{
+			{
+				Address:           "aws_instance.web",
+				Mode:              tfjson.ManagedResourceMode,
+				Type:              "aws_instance",
+				Name:              "web",
+				ProviderConfigKey: "aws.west",
+				ForEachExpression: &tfjson.Expression{ExpressionData: &tfjson.ExpressionData{
+					ConstantValue: tfjson.UnknownConstantValue,
+					References:    []string{"var.instances"},
+				}},
+				Expressions: map[string]*tfjson.Expression{
+					"ami": {ExpressionData: &tfjson.ExpressionData{
+						ConstantValue: tfjson.UnknownConstantValue,
+						References:    []string{"each.value.ami", "each.value"},
+					}},
+					"instance_type": {ExpressionData: &tfjson.ExpressionData{ConstantValue: "t3.micro"}},
+					"user_data": {ExpressionData: &tfjson.ExpressionData{
+						ConstantValue: tfjson.UnknownConstantValue,
+						References:    []string{"var.a", "local.b"},
+					}},
+					"ebs_block_device": {ExpressionData: &tfjson.ExpressionData{
+						NestedBlocks: []map[string]*tfjson.Expression{
+							{"device_name": {ExpressionData: &tfjson.ExpressionData{ConstantValue: "/dev/sda1"}}},
+							{"device_name": {ExpressionData: &tfjson.ExpressionData{ConstantValue: "/dev/sdb"}}},
+						},
+					}},
+				},
+				DependsOn: []string{"aws_vpc.main"},
+			},
+		},
+	}
+
+	got, err := RenderModule(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `resource "aws_instance" "web" {
+  for_each = var.instances /* tfjson: expression not reconstructed (best-effort reference) */
+
+  provider = aws.west
+
+  ami           = each.value.ami /* tfjson: expression not reconstructed (best-effort reference) */
+  instance_type = "t3.micro"
+  user_data     = null /* tfjson: expression not reconstructed (references: var.a, local.b) */
+
+  ebs_block_device {
+    device_name = "/dev/sda1"
+  }
+
+  ebs_block_device {
+    device_name = "/dev/sdb"
+  }
+
+  depends_on = [aws_vpc.main]
+}
+`
+	if string(got) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestRenderValue(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, "null"},
+		{true, "true"},
+		{float64(1.5), "1.5"},
+		{float64(1e21), "1000000000000000000000"},
+		{"plain", `"plain"`},
+		{"say \"hi\"\n\tC:\\", `"say \"hi\"\n\tC:\\"`},
+		{"${var.a} and %{ if true }", `"$${var.a} and %%{ if true }"`},
+		{"$5 and 100%", `"$5 and 100%"`},
+		{[]interface{}{}, "[]"},
+		{[]interface{}{"a", float64(1), nil}, `["a", 1, null]`},
+		{map[string]interface{}{}, "{}"},
+		{
+			map[string]interface{}{"b": "x", "long_key": []interface{}{"y"}, "with space": true},
+			"{\n  b            = \"x\"\n  long_key     = [\"y\"]\n  \"with space\" = true\n}",
+		},
+		{
+			[]interface{}{map[string]interface{}{"a": float64(1)}},
+			"[\n  {\n    a = 1\n  },\n]",
+		},
+	}
+
+	for _, tc := range cases {
+		if got := RenderValue(tc.value); got != tc.expected {
+			t.Errorf("%#v: expected:\n%s\ngot:\n%s", tc.value, tc.expected, got)
+		}
+	}
+}
+
+func TestRenderExpression(t *testing.T) {
+	cases := []struct {
+		expr     *tfjson.Expression
+		expected string
+	}{
+		{nil, "null"},
+		{
+			&tfjson.Expression{ExpressionData: &tfjson.ExpressionData{ConstantValue: "foo"}},
+			`"foo"`,
+		},
+		{
+			&tfjson.Expression{ExpressionData: &tfjson.ExpressionData{
+				ConstantValue: tfjson.UnknownConstantValue,
+				References:    []string{`module.network["east"].vpc_id`, `module.network["east"]`, "module.network"},
+			}},
+			`module.network["east"].vpc_id ` + UnrenderableMarker + ` (best-effort reference) */`,
+		},
+		{
+			// A template makes the same references as the traversal it
+			// interpolates, so is indistinguishable from it.
+			testUnmarshalExpression(t, `{"references":["var.x"]}`),
+			"var.x " + UnrenderableMarker + " (best-effort reference) */",
+		},
+		{
+			// A function call such as timestamp() has no constant value
+			// and makes no references.
+			testUnmarshalExpression(t, `{}`),
+			"null " + UnrenderableMarker + " (no references) */",
+		},
+		{
+			testUnmarshalExpression(t, `{"constant_value":null}`),
+			"null",
+		},
+		{
+			// aws_instance.webserver is not a prefix of the traversal
+			// aws_instance.web.id, despite being a string prefix.
+			&tfjson.Expression{ExpressionData: &tfjson.ExpressionData{
+				ConstantValue: tfjson.UnknownConstantValue,
+				References:    []string{"aws_instance.webserver", "aws_instance.web"},
+			}},
+			"null " + UnrenderableMarker + " (references: aws_instance.webserver, aws_instance.web) */",
+		},
+	}
+
+	for _, tc := range cases {
+		if got := RenderExpression(tc.expr); got != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, got)
+		}
+	}
+
+	nested := &tfjson.Expression{ExpressionData: &tfjson.ExpressionData{
+		NestedBlocks: []map[string]*tfjson.Expression{{}},
+	}}
+	if got := RenderExpression(nested); !strings.Contains(got, UnrenderableMarker) {
+		t.Errorf("expected marker for nested blocks, got %s", got)
+	}
+}
+
+func testUnmarshalExpression(t *testing.T, raw string) *tfjson.Expression {
+	t.Helper()
+
+	var e tfjson.Expression
+	if err := json.Unmarshal([]byte(raw), &e); err != nil {
+		t.Fatal(err)
+	}
+
+	return &e
+}
@@ -0,0 +1,224 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package hclrender
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// UnrenderableMarker begins the comment which RenderExpression uses to
+// flag an expression which cannot be reconstructed from its JSON
+// representation. The comment follows either a null literal or a
+// best-effort reference, so that the result is still valid HCL, and lists
+// the references made by the expression.
+const UnrenderableMarker = "/* tfjson: expression not reconstructed"
+
+var identifierRe = regexp.MustCompile(`^[A-Za-z_][0-9A-Za-z_-]*$`)
+
+// RenderValue returns the HCL literal representing v, a value as decoded
+// from JSON: nil, a bool, a float64 or json.Number, a string, or a slice
+// or map of such values. Objects and lists containing other collections
+// are rendered across multiple lines.
+func RenderValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case string:
+		return quoteString(v)
+	case []interface{}:
+		return renderList(v)
+	case map[string]interface{}:
+		return renderObject(v)
+	}
+
+	// Anything else is converted through its JSON representation.
+	b, err := json.Marshal(v)
+	if err != nil {
+		return unrenderable(fmt.Sprintf("unsupported value of type %T", v))
+	}
+	var decoded interface{}
+	dec := json.NewDecoder(strings.NewReader(string(b)))
+	dec.UseNumber()
+	if err := dec.Decode(&decoded); err != nil {
+		return unrenderable(fmt.Sprintf("unsupported value of type %T", v))
+	}
+
+	return RenderValue(decoded)
+}
+
+func renderList(l []interface{}) string {
+	if len(l) == 0 {
+		return "[]"
+	}
+
+	elems := make([]string, len(l))
+	multiline := false
+	for i, v := range l {
+		elems[i] = RenderValue(v)
+		switch v.(type) {
+		case []interface{}, map[string]interface{}:
+			multiline = true
+		}
+	}
+
+	return joinList(elems, multiline)
+}
+
+// joinList renders a list of rendered elements, either on a single line or
+// with an element on each line.
+func joinList(elems []string, multiline bool) string {
+	if !multiline {
+		return "[" + strings.Join(elems, ", ") + "]"
+	}
+
+	var b strings.Builder
+	b.WriteString("[\n")
+	for _, elem := range elems {
+		b.WriteString("  ")
+		b.WriteString(indentLines(elem, "  "))
+		b.WriteString(",\n")
+	}
+	b.WriteString("]")
+
+	return b.String()
+}
+
+func renderObject(m map[string]interface{}) string {
+	if len(m) == 0 {
+		return "{}"
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]bodyItem, len(keys))
+	for i, k := range keys {
+		items[i] = attribute{name: objectKey(k), value: RenderValue(m[k])}
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	writeBody(&b, items, 1)
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// objectKey returns k as an object key, quoting it unless it is a valid
+// identifier.
+func objectKey(k string) string {
+	if identifierRe.MatchString(k) {
+		return k
+	}
+
+	return quoteString(k)
+}
+
+// quoteString returns s as a quoted HCL string literal, escaping template
+// sequences so that they are not interpreted.
+func quoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '%':
+			b.WriteRune(r)
+			if strings.HasPrefix(s[i+1:], "{") {
+				b.WriteRune(r)
+			}
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// RenderExpression returns HCL source for the expression. Constant values
+// are rendered as literals.
+//
+// The JSON representation of any other expression records only the
+// objects it refers to, and so does not distinguish var.name from an
+// expression such as "${var.name}-suffix" or timestamp(). Such
+// expressions are rendered followed by a comment beginning with
+// UnrenderableMarker. When the expression refers to a single object, such
+// as var.name or aws_instance.web.id, it is rendered as that reference as
+// a best-effort reconstruction, and otherwise as null.
+//
+// Expressions consisting of nested blocks cannot be rendered as a single
+// expression, and are also rendered with UnrenderableMarker. Use
+// RenderModule or RenderConfig to render them as blocks.
+func RenderExpression(e *tfjson.Expression) string {
+	if e == nil || e.ExpressionData == nil {
+		return "null"
+	}
+
+	if len(e.NestedBlocks) > 0 {
+		return unrenderable("nested blocks")
+	}
+
+	if e.ConstantValue != tfjson.UnknownConstantValue {
+		return RenderValue(e.ConstantValue)
+	}
+
+	if len(e.References) == 0 {
+		return unrenderable("no references")
+	}
+
+	// Terraform reports both a traversal such as aws_instance.web.id and
+	// the objects it traverses, so a single traversal reduces to one
+	// reference.
+	if refs := tfjson.ReduceReferences(e.References); len(refs) == 1 {
+		return refs[0] + " " + unrenderableComment("best-effort reference")
+	}
+
+	return unrenderable("references: " + strings.Join(e.References, ", "))
+}
+
+func unrenderable(detail string) string {
+	return "null " + unrenderableComment(detail)
+}
+
+func unrenderableComment(detail string) string {
+	return UnrenderableMarker + " (" + strings.ReplaceAll(detail, "*/", "* /") + ") */"
+}
+
+// renderTraversals renders a list of references, such as those of
+// depends_on.
+func renderTraversals(refs []string) string {
+	return "[" + strings.Join(refs, ", ") + "]"
+}
@@ -0,0 +1,191 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// jsonNode is a JSON value which, unlike the result of decoding into an
+// interface{}, retains the order of the members of objects and the exact
+// representation of numbers.
+type jsonNode struct {
+	// delim is '{' for objects, '[' for arrays, and zero for any other
+	// value.
+	delim json.Delim
+
+	// members are the members of an object, in order.
+	members []*jsonMember
+
+	// elems are the elements of an array.
+	elems []*jsonNode
+
+	// raw is the encoding of any value which is neither an object nor an
+	// array.
+	raw []byte
+}
+
+type jsonMember struct {
+	name  string
+	value *jsonNode
+}
+
+// MarshalJSON implements json.Marshaler for jsonNode.
+func (n *jsonNode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := n.encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (n *jsonNode) encode(buf *bytes.Buffer) error {
+	switch n.delim {
+	case '{':
+		buf.WriteByte('{')
+		for i, m := range n.members {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			name, err := json.Marshal(m.name)
+			if err != nil {
+				return err
+			}
+			buf.Write(name)
+			buf.WriteByte(':')
+			if err := m.value.encode(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case '[':
+		buf.WriteByte('[')
+		for i, elem := range n.elems {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := elem.encode(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		buf.Write(n.raw)
+	}
+
+	return nil
+}
+
+// eachJSONMember calls fn with the name and value of each member of the
+// JSON object b, in order. b must be valid JSON.
+func eachJSONMember(b []byte, fn func(name string, value []byte) error) error {
+	i := skipJSONSpace(b, 1)
+	for i < len(b) && b[i] != '}' {
+		end := jsonValueEnd(b, i)
+		name, err := unquoteJSONString(b[i:end])
+		if err != nil {
+			return err
+		}
+
+		// Skip the colon separating the name from the value.
+		i = skipJSONSpace(b, skipJSONSpace(b, end)+1)
+		end = jsonValueEnd(b, i)
+		if err := fn(name, b[i:end]); err != nil {
+			return err
+		}
+
+		i = skipJSONSpace(b, end)
+		if i < len(b) && b[i] == ',' {
+			i = skipJSONSpace(b, i+1)
+		}
+	}
+
+	return nil
+}
+
+// eachJSONElem calls fn with each element of the JSON array b, in order.
+// b must be valid JSON.
+func eachJSONElem(b []byte, fn func(value []byte) error) error {
+	i := skipJSONSpace(b, 1)
+	for i < len(b) && b[i] != ']' {
+		end := jsonValueEnd(b, i)
+		if err := fn(b[i:end]); err != nil {
+			return err
+		}
+
+		i = skipJSONSpace(b, end)
+		if i < len(b) && b[i] == ',' {
+			i = skipJSONSpace(b, i+1)
+		}
+	}
+
+	return nil
+}
+
+// jsonValueEnd returns the offset within b of the end of the JSON value
+// starting at offset i.
+func jsonValueEnd(b []byte, i int) int {
+	depth := 0
+	inString := false
+	for ; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case inString:
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case depth == 0 && (c == ',' || c == ' ' || c == '\t' || c == '\r' || c == '\n'):
+			return i
+		}
+	}
+
+	return i
+}
+
+// skipJSONSpace returns the offset of the first character of b at or
+// after offset i which is not whitespace.
+func skipJSONSpace(b []byte, i int) int {
+	for i < len(b) {
+		switch b[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+		default:
+			return i
+		}
+	}
+
+	return i
+}
+
+// unquoteJSONString returns the value of the JSON string b.
+func unquoteJSONString(b []byte) (string, error) {
+	if len(b) >= 2 && bytes.IndexByte(b, '\\') < 0 {
+		return string(b[1 : len(b)-1]), nil
+	}
+
+	var s string
+	err := json.Unmarshal(b, &s)
+	return s, err
+}
@@ -5,6 +5,7 @@ package tfjson
 import (
 	"bytes"
 	"encoding/json"
+	"reflect"
 	"time"
 )
 
@@ -82,3 +83,25 @@ func UnmarshalLogMessage(b []byte) (LogMsg, error) {
 	v, err := unmarshalByType(mt.Type, b)
 	return v, err
 }
+
+// UnmarshalLogMessageWithUnknownFields is like UnmarshalLogMessage, but
+// also returns the paths of the JSON object members of the message which
+// have no corresponding field in the decoded message, such as
+// "diagnostic.new_field", so that messages emitted by newer versions of
+// Terraform can be detected. The "type" and "@module" members common to
+// all messages are not reported. Every member other than the level,
+// message and timestamp of a message of an unrecognised type is
+// reported.
+func UnmarshalLogMessageWithUnknownFields(b []byte) (LogMsg, []string, error) {
+	msg, err := UnmarshalLogMessage(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields, err := decodeUnknownFields(b, reflect.TypeOf(msg), "type", "@module")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return msg, fields, nil
+}
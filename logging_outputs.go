@@ -0,0 +1,59 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+package tfjson
+
+import (
+	"encoding/json"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	MessageOutputs LogMessageType = "outputs"
+)
+
+// OutputsLogMessage represents the root module outputs emitted by plan,
+// apply and refresh. This is a message of type "outputs".
+type OutputsLogMessage struct {
+	baseLogMessage
+	Outputs map[string]OutputLogValue `json:"outputs"`
+}
+
+// OutputLogValue represents a single output within an "outputs" message.
+//
+// After apply, Value is the output value as rendered by Terraform, which
+// includes the values of outputs marked as sensitive. During plan, only
+// Action is set.
+type OutputLogValue struct {
+	Sensitive bool        `json:"sensitive"`
+	Type      cty.Type    `json:"type,omitempty"`
+	Value     interface{} `json:"value,omitempty"`
+	Action    string      `json:"action,omitempty"`
+}
+
+// jsonOutputLogValue is the JSON representation of OutputLogValue, which
+// omits the type when it is cty.NilType, as it is during plan, rather
+// than failing to marshal it.
+type jsonOutputLogValue struct {
+	Sensitive bool            `json:"sensitive"`
+	Type      json.RawMessage `json:"type,omitempty"`
+	Value     interface{}     `json:"value,omitempty"`
+	Action    string          `json:"action,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler for OutputLogValue.
+func (v OutputLogValue) MarshalJSON() ([]byte, error) {
+	jsonValue := &jsonOutputLogValue{
+		Sensitive: v.Sensitive,
+		Value:     v.Value,
+		Action:    v.Action,
+	}
+	if v.Type != cty.NilType {
+		outputType, err := v.Type.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		jsonValue.Type = outputType
+	}
+	return json.Marshal(jsonValue)
+}
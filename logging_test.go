@@ -10,6 +10,8 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/go-version"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
 )
 
 var cmpOpts = cmp.AllowUnexported(allLogMessageTypes...)
@@ -94,7 +96,7 @@ func TestLogging_generic(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		if diff := cmp.Diff(tc.expectedMessage, msg, cmpOpts); diff != "" {
+		if diff := cmp.Diff(tc.expectedMessage, msg, cmpOpts, testCmpUnexported); diff != "" {
 			t.Fatalf("unexpected message: %s", diff)
 		}
 	}
@@ -346,3 +348,83 @@ func TestLogging_init(t *testing.T) {
 		}
 	}
 }
+
+func TestLogging_outputs(t *testing.T) {
+	testCases := []struct {
+		rawMessage      string
+		expectedMessage LogMsg
+	}{
+		{
+			`{"@level":"info","@message":"Outputs: 2","@module":"terraform.ui","@timestamp":"2025-08-11T15:09:25.001542+00:00","outputs":{"count":{"sensitive":false,"type":"number","value":3},"password":{"sensitive":true,"type":"string","value":"hunter2"}},"type":"outputs"}`,
+			OutputsLogMessage{
+				baseLogMessage: baseLogMessage{
+					Lvl:  Info,
+					Msg:  "Outputs: 2",
+					Time: time.Date(2025, 8, 11, 15, 9, 25, 1542000, time.UTC),
+				},
+				Outputs: map[string]OutputLogValue{
+					"count": {
+						Type:  cty.Number,
+						Value: json.Number("3"),
+					},
+					"password": {
+						Sensitive: true,
+						Type:      cty.String,
+						Value:     "hunter2",
+					},
+				},
+			},
+		},
+		{
+			`{"@level":"info","@message":"Outputs: 1","@module":"terraform.ui","@timestamp":"2025-08-11T15:09:20.502731+00:00","outputs":{"password":{"sensitive":true,"action":"create"}},"type":"outputs"}`,
+			OutputsLogMessage{
+				baseLogMessage: baseLogMessage{
+					Lvl:  Info,
+					Msg:  "Outputs: 1",
+					Time: time.Date(2025, 8, 11, 15, 9, 20, 502731000, time.UTC),
+				},
+				Outputs: map[string]OutputLogValue{
+					"password": {
+						Sensitive: true,
+						Action:    "create",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		msg, err := UnmarshalLogMessage([]byte(tc.rawMessage))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(tc.expectedMessage, msg, cmpOpts, ctydebug.CmpOptions); diff != "" {
+			t.Fatalf("unexpected message: %s", diff)
+		}
+	}
+}
+
+func TestLogging_outputsMarshal(t *testing.T) {
+	testCases := map[string]string{
+		"typed":   `{"count":{"sensitive":false,"type":"number","value":3},"password":{"sensitive":true,"type":"string","value":"hunter2"}}`,
+		"planned": `{"password":{"sensitive":true,"action":"create"}}`,
+	}
+
+	for name, rawOutputs := range testCases {
+		t.Run(name, func(t *testing.T) {
+			raw := `{"@level":"info","@message":"Outputs","@module":"terraform.ui","@timestamp":"2025-08-11T15:09:20.502731+00:00","outputs":` + rawOutputs + `,"type":"outputs"}`
+			msg, err := UnmarshalLogMessage([]byte(raw))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := json.Marshal(msg.(OutputsLogMessage).Outputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(rawOutputs, string(b)); diff != "" {
+				t.Fatalf("unexpected outputs: %s", diff)
+			}
+		})
+	}
+}
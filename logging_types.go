@@ -26,6 +26,9 @@ var allLogMessageTypes = []any{
 	// init
 	InitOutputMessage{},
 
+	// outputs
+	OutputsLogMessage{},
+
 	// query
 	ListStartMessage{},
 	ListResourceFoundMessage{},
@@ -54,7 +57,12 @@ func unmarshalByType(t LogMessageType, b []byte) (LogMsg, error) {
 	// init
 	case InitOutput:
 		v := InitOutputMessage{}
-		return v, json.Unmarshal(b, &v)
+		return v, d.Decode(&v)
+
+	// outputs
+	case MessageOutputs:
+		v := OutputsLogMessage{}
+		return v, d.Decode(&v)
 
 	// query
 	case MessageListStart:
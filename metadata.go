@@ -4,7 +4,6 @@
 package tfjson
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -19,6 +18,22 @@ var MetadataFunctionsFormatVersionConstraints = "~> 1.0"
 // MetadataFunctions is the top-level object returned when exporting function
 // signatures
 type MetadataFunctions struct {
+	// decodeOptions are the options used when decoding the function
+	// signatures. Set them using MetadataFunctions.SetDecodeOptions, or
+	// individually using MetadataFunctions.UseJSONNumber,
+	// MetadataFunctions.CollectUnknownFields and
+	// MetadataFunctions.PreserveUnknownFields.
+	decodeOptions DecodeOptions
+
+	// unknownFields are the paths recorded when decoding with
+	// DecodeOptions.CollectUnknownFields set, returned by
+	// MetadataFunctions.UnknownFields.
+	unknownFields []string
+
+	// unknownMembers are the members retained when decoding with
+	// DecodeOptions.PreserveUnknownFields set.
+	unknownMembers
+
 	// The version of the format. This should always match the
 	// MetadataFunctionsFormatVersionConstraints in this package, else
 	// unmarshaling will fail.
@@ -28,6 +43,38 @@ type MetadataFunctions struct {
 	Signatures map[string]*FunctionSignature `json:"function_signatures,omitempty"`
 }
 
+// SetDecodeOptions sets the options used when decoding the
+// MetadataFunctions, replacing any set previously.
+func (f *MetadataFunctions) SetDecodeOptions(opts DecodeOptions) {
+	f.decodeOptions = opts
+}
+
+// UseJSONNumber controls whether the MetadataFunctions will be decoded
+// using the json.Number behavior or the float64 behavior for values of
+// arbitrary type.
+func (f *MetadataFunctions) UseJSONNumber(b bool) {
+	f.decodeOptions.UseJSONNumber = b
+}
+
+// CollectUnknownFields sets DecodeOptions.CollectUnknownFields for
+// decoding the MetadataFunctions. The paths found are returned by UnknownFields.
+func (f *MetadataFunctions) CollectUnknownFields(b bool) {
+	f.decodeOptions.CollectUnknownFields = b
+}
+
+// UnknownFields returns the paths of the unknown fields collected when
+// decoding the MetadataFunctions, such as "function_signatures["abs"].new_field".
+func (f *MetadataFunctions) UnknownFields() []string {
+	return f.unknownFields
+}
+
+// PreserveUnknownFields sets DecodeOptions.PreserveUnknownFields for
+// decoding the MetadataFunctions, so that unknown fields are emitted again when
+// it is encoded.
+func (f *MetadataFunctions) PreserveUnknownFields(b bool) {
+	f.decodeOptions.PreserveUnknownFields = b
+}
+
 // Validate checks to ensure that MetadataFunctions is present, and the
 // version matches the version supported by this library.
 func (f *MetadataFunctions) Validate() error {
@@ -65,18 +112,29 @@ func (f *MetadataFunctions) UnmarshalJSON(b []byte) error {
 	type rawFunctions MetadataFunctions
 	var functions rawFunctions
 
-	err := json.Unmarshal(b, &functions)
+	opts := f.decodeOptions
+	unknownFields, err := opts.Unmarshal(b, &functions)
 	if err != nil {
 		return err
 	}
 
 	*f = *(*MetadataFunctions)(&functions)
+	f.decodeOptions = opts
+	f.unknownFields = unknownFields
+
+	return opts.validate(f)
+}
 
-	return f.Validate()
+// MarshalJSON implements json.Marshaler for MetadataFunctions.
+func (f *MetadataFunctions) MarshalJSON() ([]byte, error) {
+	type rawMetadataFunctions MetadataFunctions
+	return marshalUnknownMembers((*rawMetadataFunctions)(f))
 }
 
 // FunctionSignature represents a function signature.
 type FunctionSignature struct {
+	unknownMembers
+
 	// Description is an optional human-readable description
 	// of the function
 	Description string `json:"description,omitempty"`
@@ -102,8 +160,16 @@ type FunctionSignature struct {
 	VariadicParameter *FunctionParameter `json:"variadic_parameter,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for FunctionSignature.
+func (s *FunctionSignature) MarshalJSON() ([]byte, error) {
+	type rawFunctionSignature FunctionSignature
+	return marshalUnknownMembers((*rawFunctionSignature)(s))
+}
+
 // FunctionParameter represents a parameter to a function.
 type FunctionParameter struct {
+	unknownMembers
+
 	// Name is an optional name for the argument.
 	Name string `json:"name,omitempty"`
 
@@ -117,3 +183,9 @@ type FunctionParameter struct {
 	// A type that any argument for this parameter must conform to.
 	Type cty.Type `json:"type"`
 }
+
+// MarshalJSON implements json.Marshaler for FunctionParameter.
+func (p *FunctionParameter) MarshalJSON() ([]byte, error) {
+	type rawFunctionParameter FunctionParameter
+	return marshalUnknownMembers((*rawFunctionParameter)(p))
+}
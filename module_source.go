@@ -0,0 +1,326 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DefaultModuleRegistryHost is the hostname of the public Terraform
+// registry, which is assumed for registry module sources that do not
+// include a hostname.
+const DefaultModuleRegistryHost = "registry.terraform.io"
+
+// ModuleSourceType describes how a module is installed.
+type ModuleSourceType string
+
+const (
+	// ModuleSourceLocal is a module in a directory relative to the
+	// calling module, such as "./network".
+	ModuleSourceLocal ModuleSourceType = "local"
+
+	// ModuleSourceRegistry is a module installed from a module registry,
+	// such as "hashicorp/consul/aws".
+	ModuleSourceRegistry ModuleSourceType = "registry"
+
+	// ModuleSourceRemote is a module installed directly from a remote
+	// location, such as a git repository or an archive served over HTTP.
+	ModuleSourceRemote ModuleSourceType = "remote"
+)
+
+// ModuleSource is a parsed module source address, as found in
+// ModuleCall.Source.
+type ModuleSource struct {
+	// Type is the type of the source, which determines which of the
+	// other fields are set.
+	Type ModuleSourceType
+
+	// Path is the normalized path of a local module, which always begins
+	// with "./" or "../".
+	Path string
+
+	// Host, Namespace, Name and TargetSystem are the components of a
+	// registry module address. Host is DefaultModuleRegistryHost if the
+	// address does not include one.
+	Host         string
+	Namespace    string
+	Name         string
+	TargetSystem string
+
+	// Getter is the installation method of a remote module: "git", "hg",
+	// "s3", "gcs" or "http".
+	Getter string
+
+	// URL is the location of the package containing a remote module,
+	// including any query string but excluding Subdir. Shorthand forms,
+	// such as "github.com/org/repo" and "git@github.com:org/repo.git",
+	// are expanded to the full URL Terraform would use.
+	URL string
+
+	// Ref is the value of the "ref" argument of a remote git module, or
+	// the "rev" argument of a remote hg module, selecting the revision to
+	// install.
+	Ref string
+
+	// Subdir is the path of the module within a registry or remote
+	// package, given after "//" in the source address.
+	Subdir string
+}
+
+// String returns the normalized form of the source address.
+func (s *ModuleSource) String() string {
+	switch s.Type {
+	case ModuleSourceLocal:
+		return s.Path
+	case ModuleSourceRegistry:
+		addr := s.Host + "/" + s.Namespace + "/" + s.Name + "/" + s.TargetSystem
+		if s.Subdir != "" {
+			addr += "//" + s.Subdir
+		}
+		return addr
+	}
+
+	addr := s.URL
+	if s.Subdir != "" {
+		base, query, found := strings.Cut(addr, "?")
+		addr = base + "//" + s.Subdir
+		if found {
+			addr += "?" + query
+		}
+	}
+	if s.Getter != "http" || !strings.HasPrefix(s.URL, "http") {
+		addr = s.Getter + "::" + addr
+	}
+
+	return addr
+}
+
+// IsPublicRegistry returns true if the source is a module on the public
+// Terraform registry.
+func (s *ModuleSource) IsPublicRegistry() bool {
+	return s.Type == ModuleSourceRegistry && s.Host == DefaultModuleRegistryHost
+}
+
+// ParseSource parses the source address of the module call. See
+// ParseModuleSource.
+func (mc *ModuleCall) ParseSource() (*ModuleSource, error) {
+	if mc == nil {
+		return nil, errors.New("module call is nil")
+	}
+
+	return ParseModuleSource(mc.Source)
+}
+
+var (
+	moduleRegistryNameRe   = regexp.MustCompile(`^[0-9A-Za-z](?:[0-9A-Za-z-_]{0,62}[0-9A-Za-z])?$`)
+	moduleRegistrySystemRe = regexp.MustCompile(`^[0-9a-z]{1,64}$`)
+	hostnameRe             = regexp.MustCompile(`^[0-9a-z](?:[0-9a-z-]*[0-9a-z])?(?:\.[0-9a-z](?:[0-9a-z-]*[0-9a-z])?)*(?::[0-9]+)?$`)
+	forcedGetterRe         = regexp.MustCompile(`^([A-Za-z0-9]+)::(.+)$`)
+	scpLikeRe              = regexp.MustCompile(`^([A-Za-z0-9_.-]+@[A-Za-z0-9_.-]+):(.+)$`)
+)
+
+// remoteModuleGetters are the installation methods Terraform supports for
+// remote modules, and the name by which this package reports them.
+var remoteModuleGetters = map[string]string{
+	"git":   "git",
+	"hg":    "hg",
+	"s3":    "s3",
+	"gcs":   "gcs",
+	"http":  "http",
+	"https": "http",
+}
+
+// ParseModuleSource parses a module source address, following the same
+// rules as Terraform:
+//
+//   - Addresses beginning with "./" or "../" are local paths.
+//   - Addresses which are valid registry addresses, of the form
+//     [HOSTNAME/]NAMESPACE/NAME/SYSTEM, are registry modules. Hosts
+//     reserved for version control, github.com and bitbucket.org, are
+//     not registries.
+//   - Anything else is a remote address, which may select an
+//     installation method explicitly with a prefix such as "git::", or
+//     use one of the supported shorthands: GitHub repositories, SCP-style
+//     git addresses, and S3 and GCS URLs.
+//
+// Registry and remote addresses may select a subdirectory of the package
+// after "//", such as "hashicorp/consul/aws//modules/consul-cluster".
+func ParseModuleSource(raw string) (*ModuleSource, error) {
+	if isLocalModuleSource(raw) {
+		return &ModuleSource{
+			Type: ModuleSourceLocal,
+			Path: normalizeLocalModulePath(raw),
+		}, nil
+	}
+
+	// As in Terraform, an address is a registry address only if it can
+	// be parsed as one, and otherwise falls through to remote parsing.
+	if s, ok := parseRegistryModuleSource(raw); ok {
+		return s, nil
+	}
+
+	return parseRemoteModuleSource(raw)
+}
+
+func isLocalModuleSource(raw string) bool {
+	for _, prefix := range []string{"./", "../", ".\\", "..\\"} {
+		if strings.HasPrefix(raw, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeLocalModulePath(raw string) string {
+	p := path.Clean(strings.ReplaceAll(raw, "\\", "/"))
+	if p != "." && p != ".." && !strings.HasPrefix(p, "../") {
+		p = "./" + p
+	}
+
+	return p
+}
+
+func parseRegistryModuleSource(raw string) (*ModuleSource, bool) {
+	pkg, subdir := splitModuleSubdir(raw)
+
+	s := &ModuleSource{
+		Type:   ModuleSourceRegistry,
+		Host:   DefaultModuleRegistryHost,
+		Subdir: subdir,
+	}
+
+	parts := strings.Split(pkg, "/")
+	switch len(parts) {
+	case 3:
+	case 4:
+		host := strings.ToLower(parts[0])
+		if !hostnameRe.MatchString(host) || host == "github.com" || host == "bitbucket.org" {
+			return nil, false
+		}
+		s.Host = host
+		parts = parts[1:]
+	default:
+		return nil, false
+	}
+
+	if !moduleRegistryNameRe.MatchString(parts[0]) ||
+		!moduleRegistryNameRe.MatchString(parts[1]) ||
+		!moduleRegistrySystemRe.MatchString(parts[2]) {
+		return nil, false
+	}
+	s.Namespace, s.Name, s.TargetSystem = parts[0], parts[1], parts[2]
+
+	return s, true
+}
+
+func parseRemoteModuleSource(raw string) (*ModuleSource, error) {
+	getter, addr := "", raw
+	if m := forcedGetterRe.FindStringSubmatch(raw); m != nil {
+		getter, addr = m[1], m[2]
+	}
+
+	addr, subdir := splitModuleSubdir(addr)
+	addr, detected, err := detectRemoteModuleSource(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid module source %q: %w", raw, err)
+	}
+	if getter == "" {
+		getter = detected
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid module source %q: %w", raw, err)
+	}
+	if getter == "" {
+		getter = u.Scheme
+	}
+
+	name, ok := remoteModuleGetters[getter]
+	if !ok {
+		if getter == "file" {
+			return nil, fmt.Errorf("invalid module source %q: local paths must begin with ./ or ../", raw)
+		}
+		return nil, fmt.Errorf("invalid module source %q: unsupported installation method %q", raw, getter)
+	}
+
+	s := &ModuleSource{
+		Type:   ModuleSourceRemote,
+		Getter: name,
+		URL:    addr,
+		Subdir: subdir,
+	}
+	switch name {
+	case "git":
+		s.Ref = u.Query().Get("ref")
+	case "hg":
+		s.Ref = u.Query().Get("rev")
+	}
+
+	return s, nil
+}
+
+// detectRemoteModuleSource expands the shorthand forms of remote module
+// addresses, returning the full URL and the installation method it
+// implies, if any.
+func detectRemoteModuleSource(addr string) (string, string, error) {
+	if strings.Contains(addr, "://") {
+		return addr, "", nil
+	}
+
+	if m := scpLikeRe.FindStringSubmatch(addr); m != nil {
+		return "ssh://" + m[1] + "/" + m[2], "git", nil
+	}
+
+	switch {
+	case strings.HasPrefix(addr, "github.com/"):
+		repo, query, found := strings.Cut(addr, "?")
+		if strings.Count(repo, "/") < 2 {
+			return "", "", errors.New("GitHub shorthand must be of the form github.com/OWNER/REPO")
+		}
+		if !strings.HasSuffix(repo, ".git") {
+			repo += ".git"
+		}
+		if found {
+			repo += "?" + query
+		}
+		return "https://" + repo, "git", nil
+	case strings.Contains(addr, ".amazonaws.com/"):
+		return "https://" + addr, "s3", nil
+	case strings.HasPrefix(addr, "www.googleapis.com/storage/"):
+		return "https://" + addr, "gcs", nil
+	case strings.HasPrefix(addr, "/"):
+		return "", "", errors.New("absolute filesystem paths are not supported")
+	}
+
+	return "", "", fmt.Errorf("cannot determine the installation method; if this is a local path, use %q", "./"+addr)
+}
+
+// splitModuleSubdir separates the package address from the subdirectory
+// given after "//", retaining any query string in the package address.
+func splitModuleSubdir(addr string) (string, string) {
+	start := 0
+	if i := strings.Index(addr, "://"); i != -1 {
+		start = i + len("://")
+	}
+
+	i := strings.Index(addr[start:], "//")
+	if i == -1 {
+		return addr, ""
+	}
+	i += start
+
+	pkg, subdir := addr[:i], addr[i+len("//"):]
+	if subdir, query, found := strings.Cut(subdir, "?"); found {
+		return pkg + "?" + query, subdir
+	}
+
+	return pkg, subdir
+}
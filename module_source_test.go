@@ -0,0 +1,214 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseModuleSource(t *testing.T) {
+	cases := []struct {
+		raw      string
+		expected *ModuleSource
+		display  string
+	}{
+		{
+			raw:      "./network",
+			expected: &ModuleSource{Type: ModuleSourceLocal, Path: "./network"},
+			display:  "./network",
+		},
+		{
+			raw:      "../modules/../network/",
+			expected: &ModuleSource{Type: ModuleSourceLocal, Path: "../network"},
+			display:  "../network",
+		},
+		{
+			raw:      `.\modules\network`,
+			expected: &ModuleSource{Type: ModuleSourceLocal, Path: "./modules/network"},
+			display:  "./modules/network",
+		},
+		{
+			raw: "hashicorp/consul/aws",
+			expected: &ModuleSource{
+				Type:         ModuleSourceRegistry,
+				Host:         "registry.terraform.io",
+				Namespace:    "hashicorp",
+				Name:         "consul",
+				TargetSystem: "aws",
+			},
+			display: "registry.terraform.io/hashicorp/consul/aws",
+		},
+		{
+			raw: "App.Terraform.io/example-org/vpc/aws//modules/private-subnet",
+			expected: &ModuleSource{
+				Type:         ModuleSourceRegistry,
+				Host:         "app.terraform.io",
+				Namespace:    "example-org",
+				Name:         "vpc",
+				TargetSystem: "aws",
+				Subdir:       "modules/private-subnet",
+			},
+			display: "app.terraform.io/example-org/vpc/aws//modules/private-subnet",
+		},
+		{
+			raw: "localhost:8443/example/vpc/aws",
+			expected: &ModuleSource{
+				Type:         ModuleSourceRegistry,
+				Host:         "localhost:8443",
+				Namespace:    "example",
+				Name:         "vpc",
+				TargetSystem: "aws",
+			},
+			display: "localhost:8443/example/vpc/aws",
+		},
+		{
+			raw: "github.com/hashicorp/example?ref=v1.2.0",
+			expected: &ModuleSource{
+				Type:   ModuleSourceRemote,
+				Getter: "git",
+				URL:    "https://github.com/hashicorp/example.git?ref=v1.2.0",
+				Ref:    "v1.2.0",
+			},
+			display: "git::https://github.com/hashicorp/example.git?ref=v1.2.0",
+		},
+		{
+			// github.com is reserved for version control, so this is not
+			// a registry address.
+			raw: "github.com/hashicorp/example/aws",
+			expected: &ModuleSource{
+				Type:   ModuleSourceRemote,
+				Getter: "git",
+				URL:    "https://github.com/hashicorp/example/aws.git",
+			},
+			display: "git::https://github.com/hashicorp/example/aws.git",
+		},
+		{
+			raw: "git@github.com:hashicorp/example.git//modules/vpc?ref=main",
+			expected: &ModuleSource{
+				Type:   ModuleSourceRemote,
+				Getter: "git",
+				URL:    "ssh://git@github.com/hashicorp/example.git?ref=main",
+				Ref:    "main",
+				Subdir: "modules/vpc",
+			},
+			display: "git::ssh://git@github.com/hashicorp/example.git//modules/vpc?ref=main",
+		},
+		{
+			raw: "git::https://example.com/vpc.git//sub?ref=51d462976d84fdea54b47d80dcabbf680badcdb8&depth=1",
+			expected: &ModuleSource{
+				Type:   ModuleSourceRemote,
+				Getter: "git",
+				URL:    "https://example.com/vpc.git?ref=51d462976d84fdea54b47d80dcabbf680badcdb8&depth=1",
+				Ref:    "51d462976d84fdea54b47d80dcabbf680badcdb8",
+				Subdir: "sub",
+			},
+			display: "git::https://example.com/vpc.git//sub?ref=51d462976d84fdea54b47d80dcabbf680badcdb8&depth=1",
+		},
+		{
+			raw: "hg::http://example.com/vpc.hg?rev=v1.2.0",
+			expected: &ModuleSource{
+				Type:   ModuleSourceRemote,
+				Getter: "hg",
+				URL:    "http://example.com/vpc.hg?rev=v1.2.0",
+				Ref:    "v1.2.0",
+			},
+			display: "hg::http://example.com/vpc.hg?rev=v1.2.0",
+		},
+		{
+			raw: "https://example.com/vpc-module.zip",
+			expected: &ModuleSource{
+				Type:   ModuleSourceRemote,
+				Getter: "http",
+				URL:    "https://example.com/vpc-module.zip",
+			},
+			display: "https://example.com/vpc-module.zip",
+		},
+		{
+			raw: "s3::https://s3-eu-west-1.amazonaws.com/examplecorp-terraform-modules/vpc.zip",
+			expected: &ModuleSource{
+				Type:   ModuleSourceRemote,
+				Getter: "s3",
+				URL:    "https://s3-eu-west-1.amazonaws.com/examplecorp-terraform-modules/vpc.zip",
+			},
+			display: "s3::https://s3-eu-west-1.amazonaws.com/examplecorp-terraform-modules/vpc.zip",
+		},
+		{
+			raw: "examplecorp-terraform-modules.s3.amazonaws.com/vpc.zip",
+			expected: &ModuleSource{
+				Type:   ModuleSourceRemote,
+				Getter: "s3",
+				URL:    "https://examplecorp-terraform-modules.s3.amazonaws.com/vpc.zip",
+			},
+			display: "s3::https://examplecorp-terraform-modules.s3.amazonaws.com/vpc.zip",
+		},
+		{
+			raw: "gcs::https://www.googleapis.com/storage/v1/modules/foomodule.zip",
+			expected: &ModuleSource{
+				Type:   ModuleSourceRemote,
+				Getter: "gcs",
+				URL:    "https://www.googleapis.com/storage/v1/modules/foomodule.zip",
+			},
+			display: "gcs::https://www.googleapis.com/storage/v1/modules/foomodule.zip",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.raw, func(t *testing.T) {
+			got, err := ParseModuleSource(tc.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			if got.String() != tc.display {
+				t.Fatalf("expected %q, got %q", tc.display, got.String())
+			}
+		})
+	}
+}
+
+func TestParseModuleSource_errors(t *testing.T) {
+	cases := map[string]string{
+		"modules/network":          `use "./modules/network"`,
+		"/opt/modules/network":     "absolute filesystem paths",
+		"file:///opt/modules":      "local paths must begin with ./ or ../",
+		"svn::https://example.com": `unsupported installation method "svn"`,
+		"github.com/hashicorp":     "github.com/OWNER/REPO",
+	}
+
+	for raw, expected := range cases {
+		t.Run(raw, func(t *testing.T) {
+			_, err := ParseModuleSource(raw)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), expected) {
+				t.Fatalf("expected error containing %q, got %q", expected, err)
+			}
+		})
+	}
+}
+
+func TestModuleCall_ParseSource(t *testing.T) {
+	plan := testReadPlan(t, filepath.Join(testFixtureDir, "registry_module", testGoldenPlanFileName))
+
+	source, err := plan.Config.RootModule.ModuleCalls["module"].ParseSource()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !source.IsPublicRegistry() {
+		t.Errorf("expected %s to be a public registry module", source)
+	}
+	if source.Namespace != "vancluever" || source.Name != "module" || source.TargetSystem != "null" {
+		t.Errorf("unexpected source %s", source)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+)
+
+// ModulesOutputFormatVersionConstraints defines the versions of the JSON
+// modules output format that are supported by this package.
+var ModulesOutputFormatVersionConstraints = "~> 1.0"
+
+// ModulesOutput represents JSON output from terraform modules -json
+// (available from 1.10 onwards), which lists the modules declared in the
+// configuration along with the version of each that is installed.
+type ModulesOutput struct {
+	// The version of the format. This should always match the
+	// ModulesOutputFormatVersionConstraints in this package, else
+	// unmarshaling will fail.
+	FormatVersion string `json:"format_version"`
+
+	// The modules declared in the configuration.
+	Modules []*ModulesOutputEntry `json:"modules"`
+}
+
+// ModulesOutputEntry is a single module within ModulesOutput.
+type ModulesOutputEntry struct {
+	// The module key, which is the path of module call names leading to
+	// the module separated by dots, such as "network.subnets".
+	Key string `json:"key"`
+
+	// The source address of the module.
+	Source string `json:"source"`
+
+	// The installed version, for modules that come from the registry.
+	Version string `json:"version"`
+}
+
+// Validate checks to ensure that ModulesOutput is present, and the
+// version matches the version supported by this library.
+func (o *ModulesOutput) Validate() error {
+	if o == nil {
+		return errors.New("modules output is nil")
+	}
+
+	if o.FormatVersion == "" {
+		return errors.New("unexpected modules output, format version is missing")
+	}
+
+	constraint, err := version.NewConstraint(ModulesOutputFormatVersionConstraints)
+	if err != nil {
+		return fmt.Errorf("invalid version constraint: %w", err)
+	}
+
+	version, err := version.NewVersion(o.FormatVersion)
+	if err != nil {
+		return fmt.Errorf("invalid format version %q: %w", o.FormatVersion, err)
+	}
+
+	if !constraint.Check(version) {
+		return fmt.Errorf("unsupported modules output format version: %q does not satisfy %q",
+			version, constraint)
+	}
+
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for ModulesOutput.
+//
+// As per established convention this method should only ever
+// be invoked *indirectly* via [encoding/json] library.
+func (o *ModulesOutput) UnmarshalJSON(b []byte) error {
+	type rawOutput ModulesOutput
+	var output rawOutput
+
+	err := json.Unmarshal(b, &output)
+	if err != nil {
+		return err
+	}
+
+	*o = *(*ModulesOutput)(&output)
+
+	return o.Validate()
+}
+
+// AnnotateConfig sets the ResolvedVersion of each ModuleCall in c,
+// including those within child modules, from the entry with the same key.
+// The output does not record where modules are installed, so ResolvedDir
+// is left unchanged. Module calls with no entry are left unchanged.
+func (o *ModulesOutput) AnnotateConfig(c *Config) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	versions := make(map[string]string, len(o.Modules))
+	for _, m := range o.Modules {
+		if m != nil {
+			versions[m.Key] = m.Version
+		}
+	}
+
+	return walkModuleCalls(c, func(key string, call *ModuleCall) {
+		if v, ok := versions[key]; ok {
+			call.ResolvedVersion = v
+		}
+	})
+}
+
+// ModuleManifest is the manifest of installed modules which Terraform
+// writes to .terraform/modules/modules.json when running terraform init.
+//
+// Unlike most of the formats in this package, the manifest is an internal
+// detail of Terraform, and is not covered by any compatibility promises.
+type ModuleManifest struct {
+	// The installed modules, including an entry for the root module.
+	Modules []*ModuleManifestEntry `json:"Modules"`
+}
+
+// ModuleManifestEntry is a single module within ModuleManifest.
+type ModuleManifestEntry struct {
+	// The module key, which is the path of module call names leading to
+	// the module separated by dots, such as "network.subnets". The key of
+	// the root module is empty.
+	Key string `json:"Key"`
+
+	// The source address of the module.
+	Source string `json:"Source"`
+
+	// The installed version, for modules that come from the registry.
+	Version string `json:"Version,omitempty"`
+
+	// The directory the module is installed in, relative to the working
+	// directory.
+	Dir string `json:"Dir"`
+}
+
+// Validate checks to ensure that ModuleManifest is present.
+func (m *ModuleManifest) Validate() error {
+	if m == nil {
+		return errors.New("module manifest is nil")
+	}
+
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for ModuleManifest.
+//
+// As per established convention this method should only ever
+// be invoked *indirectly* via [encoding/json] library.
+func (m *ModuleManifest) UnmarshalJSON(b []byte) error {
+	type rawManifest ModuleManifest
+	var manifest rawManifest
+
+	err := json.Unmarshal(b, &manifest)
+	if err != nil {
+		return err
+	}
+
+	*m = *(*ModuleManifest)(&manifest)
+
+	return m.Validate()
+}
+
+// AnnotateConfig sets the ResolvedVersion and ResolvedDir of each
+// ModuleCall in c, including those within child modules, from the
+// manifest entry with the same key. Module calls with no entry, such as
+// those added since terraform init was last run, are left unchanged.
+func (m *ModuleManifest) AnnotateConfig(c *Config) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	entries := make(map[string]*ModuleManifestEntry, len(m.Modules))
+	for _, e := range m.Modules {
+		if e != nil {
+			entries[e.Key] = e
+		}
+	}
+
+	return walkModuleCalls(c, func(key string, call *ModuleCall) {
+		if e, ok := entries[key]; ok {
+			call.ResolvedVersion = e.Version
+			call.ResolvedDir = e.Dir
+		}
+	})
+}
+
+// walkModuleCalls calls fn for every ModuleCall in c, including those
+// within child modules, along with the module key identifying it in
+// ModuleManifest and ModulesOutput.
+func walkModuleCalls(c *Config, fn func(key string, call *ModuleCall)) error {
+	if c == nil {
+		return errors.New("config is nil")
+	}
+
+	walkModuleCallsIn(c.RootModule, "", fn)
+
+	return nil
+}
+
+func walkModuleCallsIn(m *ConfigModule, prefix string, fn func(key string, call *ModuleCall)) {
+	if m == nil {
+		return
+	}
+
+	for name, call := range m.ModuleCalls {
+		if call == nil {
+			continue
+		}
+
+		key := prefix + name
+		fn(key, call)
+		walkModuleCallsIn(call.Module, key+".", fn)
+	}
+}
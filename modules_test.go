@@ -0,0 +1,127 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testReadModuleManifest(t *testing.T, dir string) *ModuleManifest {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join(testFixtureDir, dir, testGoldenModuleManifestFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var manifest ModuleManifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	return &manifest
+}
+
+func TestModuleManifest_AnnotateConfig(t *testing.T) {
+	plan := testReadPlan(t, filepath.Join(testFixtureDir, "deep_module", testGoldenPlanFileName))
+	manifest := testReadModuleManifest(t, "deep_module")
+
+	if err := manifest.AnnotateConfig(plan.Config); err != nil {
+		t.Fatal(err)
+	}
+
+	foo := plan.Config.RootModule.ModuleCalls["foo"]
+	if foo.ResolvedDir != "foo" || foo.ResolvedVersion != "" {
+		t.Errorf("unexpected annotation of foo: dir %q, version %q", foo.ResolvedDir, foo.ResolvedVersion)
+	}
+
+	bar := foo.Module.ModuleCalls["bar"]
+	if bar.ResolvedDir != "foo/bar" {
+		t.Errorf("unexpected annotation of foo.bar: dir %q", bar.ResolvedDir)
+	}
+}
+
+func TestModuleManifest_AnnotateConfig_registry(t *testing.T) {
+	plan := testReadPlan(t, filepath.Join(testFixtureDir, "registry_module", testGoldenPlanFileName))
+	manifest := testReadModuleManifest(t, "registry_module")
+
+	if err := manifest.AnnotateConfig(plan.Config); err != nil {
+		t.Fatal(err)
+	}
+
+	call := plan.Config.RootModule.ModuleCalls["module"]
+	if call.ResolvedVersion != "1.0.2" {
+		t.Errorf("expected version 1.0.2, got %q", call.ResolvedVersion)
+	}
+	if call.ResolvedDir != ".terraform/modules/module" {
+		t.Errorf("expected dir .terraform/modules/module, got %q", call.ResolvedDir)
+	}
+}
+
+func TestModuleManifest_AnnotateConfig_missing(t *testing.T) {
+	plan := testReadPlan(t, filepath.Join(testFixtureDir, "deep_module", testGoldenPlanFileName))
+	manifest := &ModuleManifest{
+		Modules: []*ModuleManifestEntry{
+			{Key: "foo", Source: "./foo", Dir: "foo"},
+		},
+	}
+
+	if err := manifest.AnnotateConfig(plan.Config); err != nil {
+		t.Fatal(err)
+	}
+
+	bar := plan.Config.RootModule.ModuleCalls["foo"].Module.ModuleCalls["bar"]
+	if bar.ResolvedDir != "" {
+		t.Errorf("expected foo.bar to be left unannotated, got dir %q", bar.ResolvedDir)
+	}
+
+	if err := manifest.AnnotateConfig(nil); err == nil {
+		t.Error("expected error for nil config")
+	}
+}
+
+func TestModulesOutput_AnnotateConfig(t *testing.T) {
+	plan := testReadPlan(t, filepath.Join(testFixtureDir, "registry_module", testGoldenPlanFileName))
+
+	b, err := os.ReadFile(filepath.Join(testFixtureDir, "registry_module", testGoldenModulesOutputFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var output ModulesOutput
+	if err := json.Unmarshal(b, &output); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := output.AnnotateConfig(plan.Config); err != nil {
+		t.Fatal(err)
+	}
+
+	call := plan.Config.RootModule.ModuleCalls["module"]
+	if call.ResolvedVersion != "1.0.2" {
+		t.Errorf("expected version 1.0.2, got %q", call.ResolvedVersion)
+	}
+	if call.ResolvedDir != "" {
+		t.Errorf("expected no dir, got %q", call.ResolvedDir)
+	}
+}
+
+func TestModulesOutput_Validate(t *testing.T) {
+	cases := map[string]string{
+		"missing":     `{"modules": []}`,
+		"unsupported": `{"format_version": "2.0", "modules": []}`,
+	}
+
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			var output ModulesOutput
+			if err := json.Unmarshal([]byte(input), &output); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
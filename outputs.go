@@ -0,0 +1,145 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"encoding/json"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Outputs is the top-level representation of the output of
+// "terraform output -json", which lists every root module output.
+type Outputs struct {
+	// decodeOptions are the options used when decoding the outputs. Set
+	// them using Outputs.SetDecodeOptions, or individually using
+	// Outputs.UseJSONNumber, Outputs.CollectUnknownFields and
+	// Outputs.PreserveUnknownFields.
+	decodeOptions DecodeOptions
+
+	// unknownFields are the paths recorded when decoding with
+	// DecodeOptions.CollectUnknownFields set, returned by
+	// Outputs.UnknownFields.
+	unknownFields []string
+
+	// Outputs are the root module outputs, keyed by name.
+	Outputs map[string]*StateOutput
+}
+
+// SetDecodeOptions sets the options used when decoding the Outputs,
+// replacing any set previously. As the output of "terraform output -json"
+// has no format version, DecodeOptions.SkipValidate has no effect.
+func (o *Outputs) SetDecodeOptions(opts DecodeOptions) {
+	o.decodeOptions = opts
+}
+
+// UseJSONNumber controls whether the Outputs will be decoded using the
+// json.Number behavior or the float64 behavior. When b is true, the
+// Outputs will represent numbers in output values as json.Numbers. When
+// b is false, they will be represented as float64s.
+func (o *Outputs) UseJSONNumber(b bool) {
+	o.decodeOptions.UseJSONNumber = b
+}
+
+// CollectUnknownFields sets DecodeOptions.CollectUnknownFields for
+// decoding the Outputs. The paths found are returned by UnknownFields.
+func (o *Outputs) CollectUnknownFields(b bool) {
+	o.decodeOptions.CollectUnknownFields = b
+}
+
+// UnknownFields returns the paths of the unknown fields collected when
+// decoding the Outputs, such as `["foo"].new_field`.
+func (o *Outputs) UnknownFields() []string {
+	return o.unknownFields
+}
+
+// PreserveUnknownFields sets DecodeOptions.PreserveUnknownFields for
+// decoding the Outputs, so that unknown fields are emitted again when
+// it is encoded.
+func (o *Outputs) PreserveUnknownFields(b bool) {
+	o.decodeOptions.PreserveUnknownFields = b
+}
+
+// UnmarshalJSON implements json.Unmarshaler for Outputs.
+//
+// As per established convention this method should only ever
+// be invoked *indirectly* via [encoding/json] library.
+func (o *Outputs) UnmarshalJSON(b []byte) error {
+	var outputs map[string]*StateOutput
+
+	opts := o.decodeOptions
+	unknownFields, err := opts.Unmarshal(b, &outputs)
+	if err != nil {
+		return err
+	}
+
+	o.Outputs = outputs
+	o.decodeOptions = opts
+	o.unknownFields = unknownFields
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for Outputs.
+func (o *Outputs) MarshalJSON() ([]byte, error) {
+	if o.Outputs == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(o.Outputs)
+}
+
+// OutputValue is the representation of a single output value, as produced
+// by "terraform output -json NAME". Unlike Outputs, this form records
+// neither the type of the value nor whether it is sensitive.
+type OutputValue struct {
+	// decodeOptions are the options used when decoding the value. Set them
+	// using OutputValue.SetDecodeOptions, or using OutputValue.UseJSONNumber.
+	decodeOptions DecodeOptions
+
+	// Value is the output value.
+	Value interface{}
+}
+
+// SetDecodeOptions sets the options used when decoding the OutputValue,
+// replacing any set previously. As the value is of arbitrary type, only
+// DecodeOptions.UseJSONNumber and, when decoded using DecodeOutputValue,
+// DecodeOptions.MaxSize have any effect.
+func (v *OutputValue) SetDecodeOptions(opts DecodeOptions) {
+	v.decodeOptions = opts
+}
+
+// UseJSONNumber controls whether the OutputValue will be decoded using
+// the json.Number behavior or the float64 behavior.
+func (v *OutputValue) UseJSONNumber(b bool) {
+	v.decodeOptions.UseJSONNumber = b
+}
+
+// UnmarshalJSON implements json.Unmarshaler for OutputValue.
+//
+// As per established convention this method should only ever
+// be invoked *indirectly* via [encoding/json] library.
+func (v *OutputValue) UnmarshalJSON(b []byte) error {
+	var value interface{}
+	if _, err := v.decodeOptions.Unmarshal(b, &value); err != nil {
+		return err
+	}
+
+	v.Value = value
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for OutputValue.
+func (v *OutputValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Value)
+}
+
+// CtyValue returns the value as a cty.Value of type ty. As the type is not
+// recorded by "terraform output -json NAME", it must be supplied by the
+// caller; if ty is cty.NilType the type is inferred as in
+// StateOutput.CtyValue.
+func (v *OutputValue) CtyValue(ty cty.Type) (cty.Value, error) {
+	return ctyValue(v.Value, ty, nil, nil)
+}
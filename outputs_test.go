@@ -0,0 +1,121 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestOutputs_UnmarshalJSON(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(testFixtureDir, "basic", testGoldenOutputsFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var outputs Outputs
+	outputs.UseJSONNumber(true)
+	if err := json.Unmarshal(b, &outputs); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(outputs.Outputs) != 8 {
+		t.Fatalf("expected 8 outputs, got %d", len(outputs.Outputs))
+	}
+
+	expectedFoo := &StateOutput{
+		Sensitive: true,
+		Type:      cty.String,
+		Value:     "bar",
+	}
+	if diff := cmp.Diff(expectedFoo, outputs.Outputs["foo"], ctydebug.CmpOptions, testCmpUnexported); diff != "" {
+		t.Errorf("foo mismatch (-expected +actual):\n%s", diff)
+	}
+
+	expectedMap := &StateOutput{
+		Type: cty.Object(map[string]cty.Type{
+			"foo":    cty.String,
+			"number": cty.Number,
+		}),
+		Value: map[string]interface{}{
+			"foo":    "bar",
+			"number": json.Number("42"),
+		},
+	}
+	if diff := cmp.Diff(expectedMap, outputs.Outputs["map"], ctydebug.CmpOptions, testCmpUnexported); diff != "" {
+		t.Errorf("map mismatch (-expected +actual):\n%s", diff)
+	}
+
+	val, err := outputs.Outputs["interpolated_deep"].CtyValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := val.GetAttr("map").GetAttr("id"); !id.RawEquals(cty.StringVal("4032416458149524829")) {
+		t.Errorf("unexpected id: %#v", id)
+	}
+
+	// Round-trip through MarshalJSON.
+	out, err := json.Marshal(&outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roundTripped Outputs
+	roundTripped.UseJSONNumber(true)
+	if err := json.Unmarshal(out, &roundTripped); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(outputs.Outputs, roundTripped.Outputs, ctydebug.CmpOptions, testCmpUnexported); diff != "" {
+		t.Errorf("round trip mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestOutputs_UnmarshalJSONInvalidType(t *testing.T) {
+	var outputs Outputs
+	err := json.Unmarshal([]byte(`{"foo":{"sensitive":false,"type":"strin","value":"bar"}}`), &outputs)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestOutputValue_UnmarshalJSON(t *testing.T) {
+	var value OutputValue
+	value.UseJSONNumber(true)
+	if err := json.Unmarshal([]byte(`{"foo":"bar","number":12345678901234567890}`), &value); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"foo":    "bar",
+		"number": json.Number("12345678901234567890"),
+	}
+	if diff := cmp.Diff(expected, value.Value); diff != "" {
+		t.Errorf("Value mismatch (-expected +actual):\n%s", diff)
+	}
+
+	val, err := value.CtyValue(cty.Map(cty.String))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedVal := cty.MapVal(map[string]cty.Value{
+		"foo":    cty.StringVal("bar"),
+		"number": cty.StringVal("12345678901234567890"),
+	})
+	if diff := cmp.Diff(expectedVal, val, ctydebug.CmpOptions); diff != "" {
+		t.Errorf("CtyValue() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	out, err := json.Marshal(&value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"foo":"bar","number":12345678901234567890}` {
+		t.Errorf("unexpected marshaled value: %s", out)
+	}
+}
@@ -18,8 +18,17 @@ const testFixtureDir = "testdata"
 const testGoldenPlanFileName = "plan.json"
 const testGoldenStateFileName = "state.json"
 const testGoldenSchemasFileName = "schemas.json"
+const testGoldenOutputsFileName = "outputs.json"
+const testGoldenModuleManifestFileName = "modules.json"
+const testGoldenModulesOutputFileName = "modules_output.json"
 const testInvalidDir = "invalid"
 
+// testCmpUnexported allows cmp to compare the unexported fields of the types
+// of this package, such as those holding preserved unknown members.
+var testCmpUnexported = cmp.Exporter(func(t reflect.Type) bool {
+	return t.PkgPath() == tfjsonPkgPath
+})
+
 func testParse(t *testing.T, filename string, typ reflect.Type) {
 	entries, err := os.ReadDir(testFixtureDir)
 	if err != nil {
@@ -56,19 +65,31 @@ func testParse(t *testing.T, filename string, typ reflect.Type) {
 				t.Fatal(err)
 			}
 
-			// Add a newline at the end
-			actual = append(actual, byte('\n'))
-
-			// TODO: Compare the actual struct instead of byte slice
-			// because JSON does not guarantee consistent key ordering
-
-			if diff := cmp.Diff(expected, actual); diff != "" {
+			// Compare the decoded documents rather than the bytes, as
+			// Terraform does not order the keys of every object as they
+			// are ordered in the structs of this package.
+			if diff := cmp.Diff(testDecodeJSON(t, expected), testDecodeJSON(t, actual)); diff != "" {
 				t.Fatalf("unexpected: %s", diff)
 			}
 		})
 	}
 }
 
+// testDecodeJSON decodes b, retaining the text of numbers so that they are
+// compared exactly.
+func testDecodeJSON(t *testing.T, b []byte) interface{} {
+	t.Helper()
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
+
 func TestParsePlan(t *testing.T) {
 	testParse(t, testGoldenPlanFileName, reflect.TypeOf(Plan{}))
 }
@@ -81,6 +102,14 @@ func TestParseState(t *testing.T) {
 	testParse(t, testGoldenStateFileName, reflect.TypeOf(State{}))
 }
 
+func TestParseModuleManifest(t *testing.T) {
+	testParse(t, testGoldenModuleManifestFileName, reflect.TypeOf(ModuleManifest{}))
+}
+
+func TestParseModulesOutput(t *testing.T) {
+	testParse(t, testGoldenModulesOutputFileName, reflect.TypeOf(ModulesOutput{}))
+}
+
 func lineAt(text []byte, offs int) []byte {
 	i := offs
 	for i < len(text) && text[i] != '\n' {
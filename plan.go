@@ -4,7 +4,6 @@
 package tfjson
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -26,15 +25,27 @@ const (
 
 	// ManagedResourceMode is the resource mode for managed resources.
 	ManagedResourceMode ResourceMode = "managed"
+
+	// EphemeralResourceMode is the resource mode for ephemeral resources.
+	EphemeralResourceMode ResourceMode = "ephemeral"
 )
 
 // Plan represents the entire contents of an output Terraform plan.
 type Plan struct {
-	// useJSONNumber opts into the behavior of calling
-	// json.Decoder.UseNumber prior to decoding the plan, which turns
-	// numbers into json.Numbers instead of float64s. Set it using
-	// Plan.UseJSONNumber.
-	useJSONNumber bool
+	// decodeOptions are the options used when decoding the plan. Set them
+	// using Plan.SetDecodeOptions, or individually using
+	// Plan.UseJSONNumber, Plan.CollectUnknownFields and
+	// Plan.PreserveUnknownFields.
+	decodeOptions DecodeOptions
+
+	// unknownFields are the paths recorded when decoding with
+	// DecodeOptions.CollectUnknownFields set, returned by
+	// Plan.UnknownFields.
+	unknownFields []string
+
+	// unknownMembers are the members retained when decoding with
+	// DecodeOptions.PreserveUnknownFields set.
+	unknownMembers
 
 	// The version of the plan format. This should always match the
 	// PlanFormatVersion constant in this package, or else an unmarshal
@@ -71,6 +82,20 @@ type Plan struct {
 	// Terraform versions.
 	Complete *bool `json:"complete,omitempty"`
 
+	// Applyable indicates that the plan can be applied, which is the case
+	// if it has changes and did not error.
+	//
+	// Applyable was introduced in Terraform 1.8 and will be nil for all
+	// previous Terraform versions.
+	Applyable *bool `json:"applyable,omitempty"`
+
+	// Errored indicates that errors occurred during the plan operation, in
+	// which case the plan may be incomplete and cannot be applied.
+	//
+	// Errored was introduced in Terraform 1.6 and will be nil for all
+	// previous Terraform versions.
+	Errored *bool `json:"errored,omitempty"`
+
 	// The change operations for outputs within this plan.
 	OutputChanges map[string]*Change `json:"output_changes,omitempty"`
 
@@ -98,6 +123,8 @@ type Plan struct {
 
 // ResourceAttribute describes a full path to a resource attribute
 type ResourceAttribute struct {
+	unknownMembers
+
 	// Resource describes resource instance address (e.g. null_resource.foo)
 	Resource string `json:"resource"`
 	// Attribute describes the attribute path using a lossy representation
@@ -105,12 +132,45 @@ type ResourceAttribute struct {
 	Attribute []json.RawMessage `json:"attribute"`
 }
 
+// MarshalJSON implements json.Marshaler for ResourceAttribute.
+func (ra *ResourceAttribute) MarshalJSON() ([]byte, error) {
+	type rawResourceAttribute ResourceAttribute
+	return marshalUnknownMembers((*rawResourceAttribute)(ra))
+}
+
+// SetDecodeOptions sets the options used when decoding the Plan, replacing
+// any set previously.
+func (p *Plan) SetDecodeOptions(opts DecodeOptions) {
+	p.decodeOptions = opts
+}
+
 // UseJSONNumber controls whether the Plan will be decoded using the
 // json.Number behavior or the float64 behavior. When b is true, the Plan will
-// represent numbers in PlanOutputs as json.Numbers. When b is false, the
-// Plan will represent numbers in PlanOutputs as float64s.
+// represent numbers in values of arbitrary type as json.Numbers, including
+// those within its configuration and prior state, such as the constant
+// values of expressions. When b is false, the Plan will represent them as
+// float64s.
 func (p *Plan) UseJSONNumber(b bool) {
-	p.useJSONNumber = b
+	p.decodeOptions.UseJSONNumber = b
+}
+
+// CollectUnknownFields sets DecodeOptions.CollectUnknownFields for
+// decoding the Plan. The paths found are returned by UnknownFields.
+func (p *Plan) CollectUnknownFields(b bool) {
+	p.decodeOptions.CollectUnknownFields = b
+}
+
+// UnknownFields returns the paths of the unknown fields collected when
+// decoding the Plan, such as "resource_changes[0].change.new_field".
+func (p *Plan) UnknownFields() []string {
+	return p.unknownFields
+}
+
+// PreserveUnknownFields sets DecodeOptions.PreserveUnknownFields for
+// decoding the Plan, so that unknown fields are emitted again when
+// it is encoded.
+func (p *Plan) PreserveUnknownFields(b bool) {
+	p.decodeOptions.PreserveUnknownFields = b
 }
 
 // Validate checks to ensure that the plan is present, and the
@@ -150,24 +210,31 @@ func (p *Plan) UnmarshalJSON(b []byte) error {
 	type rawPlan Plan
 	var plan rawPlan
 
-	dec := json.NewDecoder(bytes.NewReader(b))
-	if p.useJSONNumber {
-		dec.UseNumber()
-	}
-	err := dec.Decode(&plan)
+	opts := p.decodeOptions
+	unknownFields, err := opts.Unmarshal(b, &plan)
 	if err != nil {
 		return err
 	}
 
 	*p = *(*Plan)(&plan)
+	p.decodeOptions = opts
+	p.unknownFields = unknownFields
 
-	return p.Validate()
+	return opts.validate(p)
+}
+
+// MarshalJSON implements json.Marshaler for Plan.
+func (p *Plan) MarshalJSON() ([]byte, error) {
+	type rawPlan Plan
+	return marshalUnknownMembers((*rawPlan)(p))
 }
 
 // ResourceChange is a description of an individual change action
 // that Terraform plans to use to move from the prior state to a new
 // state matching the configuration.
 type ResourceChange struct {
+	unknownMembers
+
 	// The absolute resource address.
 	Address string `json:"address,omitempty"`
 
@@ -217,6 +284,12 @@ type ResourceChange struct {
 	ActionReason ActionReason `json:"action_reason,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for ResourceChange.
+func (rc *ResourceChange) MarshalJSON() ([]byte, error) {
+	type rawResourceChange ResourceChange
+	return marshalUnknownMembers((*rawResourceChange)(rc))
+}
+
 // ActionReason is a keyword representing the optional reason Terraform reports
 // for the actions proposed in a ResourceChange. The set of possible values may
 // grow in future Terraform versions, so consumers should treat unrecognized
@@ -289,6 +362,8 @@ const (
 
 // Change is the representation of a proposed change for an object.
 type Change struct {
+	unknownMembers
+
 	// The action to be carried out by this change.
 	Actions Actions `json:"actions,omitempty"`
 
@@ -347,8 +422,16 @@ type Change struct {
 	AfterIdentity  interface{} `json:"after_identity,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for Change.
+func (c *Change) MarshalJSON() ([]byte, error) {
+	type rawChange Change
+	return marshalUnknownMembers((*rawChange)(c))
+}
+
 // Importing is a nested object for the resource import metadata.
 type Importing struct {
+	unknownMembers
+
 	// The original ID of this resource used to target it as part of planned
 	// import operation.
 	ID string `json:"id,omitempty"`
@@ -364,15 +447,31 @@ type Importing struct {
 	Identity interface{} `json:"identity,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for Importing.
+func (i *Importing) MarshalJSON() ([]byte, error) {
+	type rawImporting Importing
+	return marshalUnknownMembers((*rawImporting)(i))
+}
+
 // PlanVariable is a top-level variable in the Terraform plan.
 type PlanVariable struct {
+	unknownMembers
+
 	// The value for this variable at plan time.
 	Value interface{} `json:"value,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for PlanVariable.
+func (v *PlanVariable) MarshalJSON() ([]byte, error) {
+	type rawPlanVariable PlanVariable
+	return marshalUnknownMembers((*rawPlanVariable)(v))
+}
+
 // DeferredResourceChange is a description of a resource change that has been
 // deferred for some reason.
 type DeferredResourceChange struct {
+	unknownMembers
+
 	// Reason is the reason why this resource change was deferred.
 	Reason string `json:"reason,omitempty"`
 
@@ -380,7 +479,15 @@ type DeferredResourceChange struct {
 	ResourceChange *ResourceChange `json:"resource_change,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for DeferredResourceChange.
+func (d *DeferredResourceChange) MarshalJSON() ([]byte, error) {
+	type rawDeferredResourceChange DeferredResourceChange
+	return marshalUnknownMembers((*rawDeferredResourceChange)(d))
+}
+
 type ActionInvocation struct {
+	unknownMembers
+
 	// Address is the absolute action address
 	Address string `json:"address,omitempty"`
 	// Type is the type of the action
@@ -403,11 +510,33 @@ type ActionInvocation struct {
 	InvokeActionTrigger    *InvokeActionTrigger    `json:"invoke_action_trigger,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for ActionInvocation.
+func (a *ActionInvocation) MarshalJSON() ([]byte, error) {
+	type rawActionInvocation ActionInvocation
+	return marshalUnknownMembers((*rawActionInvocation)(a))
+}
+
 type LifecycleActionTrigger struct {
+	unknownMembers
+
 	TriggeringResourceAddress string `json:"triggering_resource_address,omitempty"`
 	ActionTriggerEvent        string `json:"action_trigger_event,omitempty"`
 	ActionTriggerBlockIndex   int    `json:"action_trigger_block_index"`
 	ActionsListIndex          int    `json:"actions_list_index"`
 }
 
-type InvokeActionTrigger struct{}
+// MarshalJSON implements json.Marshaler for LifecycleActionTrigger.
+func (t *LifecycleActionTrigger) MarshalJSON() ([]byte, error) {
+	type rawLifecycleActionTrigger LifecycleActionTrigger
+	return marshalUnknownMembers((*rawLifecycleActionTrigger)(t))
+}
+
+type InvokeActionTrigger struct {
+	unknownMembers
+}
+
+// MarshalJSON implements json.Marshaler for InvokeActionTrigger.
+func (t *InvokeActionTrigger) MarshalJSON() ([]byte, error) {
+	type rawInvokeActionTrigger InvokeActionTrigger
+	return marshalUnknownMembers((*rawInvokeActionTrigger)(t))
+}
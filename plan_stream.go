@@ -0,0 +1,434 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// PlanStreamOptions control how StreamPlan decodes a plan.
+//
+// The sections of the plan which have a callback are passed to it one
+// element at a time, rather than being retained in the Plan returned by
+// StreamPlan, so only a single element of each is held in memory at
+// once.
+type PlanStreamOptions struct {
+	// DecodeOptions are the options used when decoding the plan.
+	// UseJSONNumber, SkipValidate and MaxSize are supported; the options
+	// relating to unknown fields are not.
+	DecodeOptions DecodeOptions
+
+	// ResourceChange, if set, is called for each element of
+	// Plan.ResourceChanges, in order.
+	ResourceChange func(rc *ResourceChange) error
+
+	// ResourceDrift, if set, is called for each element of
+	// Plan.ResourceDrift, in order.
+	ResourceDrift func(rc *ResourceChange) error
+
+	// PlannedResource, if set, is called for each resource within
+	// Plan.PlannedValues, with the resources of each module visited before
+	// those of its child modules. The returned Plan then has no
+	// PlannedValues.RootModule.
+	PlannedResource func(r *StateResource) error
+
+	// OutputChange, if set, is called for each element of
+	// Plan.OutputChanges, in the order they appear within the plan.
+	OutputChange func(name string, change *Change) error
+
+	// Skip are the names of top-level members of the plan, such as
+	// "prior_state" and "configuration", which are discarded without
+	// being decoded.
+	Skip []string
+}
+
+// StreamPlan decodes the plan read from r, as produced by
+// "terraform show -json PLANFILE", without holding the whole document in
+// memory. Resource changes, drift, planned resources and output changes
+// are passed to the callbacks in opts as they are read, and the sections
+// of the plan named in opts.Skip are discarded. The remaining sections are
+// decoded into the returned Plan.
+//
+// The format version of the plan is checked as soon as it is read, unless
+// opts.DecodeOptions.SkipValidate is set, so callbacks are not called for
+// plans in an unsupported format provided the format version precedes the
+// other sections, as it does in plans produced by Terraform.
+//
+// If a callback returns an error, StreamPlan stops and returns that error.
+func StreamPlan(r io.Reader, opts PlanStreamOptions) (*Plan, error) {
+	decodeOpts := opts.DecodeOptions
+	if decodeOpts.CollectUnknownFields || decodeOpts.PreserveUnknownFields || decodeOpts.DisallowUnknownFields {
+		return nil, errors.New("unknown fields options are not supported when streaming a plan")
+	}
+
+	if decodeOpts.MaxSize > 0 {
+		r = &sizeLimitReader{r: r, max: decodeOpts.MaxSize}
+	}
+
+	dec := json.NewDecoder(r)
+	if decodeOpts.UseJSONNumber {
+		dec.UseNumber()
+	}
+
+	s := &planStreamer{
+		dec:  dec,
+		opts: opts,
+		plan: &Plan{decodeOptions: decodeOpts},
+	}
+	if err := s.stream(); err != nil {
+		return nil, err
+	}
+
+	return s.plan, nil
+}
+
+type planStreamer struct {
+	dec  *json.Decoder
+	opts PlanStreamOptions
+	plan *Plan
+}
+
+func (s *planStreamer) stream() error {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return errors.New("plan must be a JSON object")
+	}
+
+	planType := reflect.TypeOf(s.plan).Elem()
+	for s.dec.More() {
+		key, err := s.key()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case containsString(s.opts.Skip, key):
+			err = skipJSONValue(s.dec)
+		case key == "resource_changes" && s.opts.ResourceChange != nil:
+			err = s.resourceChanges(s.opts.ResourceChange)
+		case key == "resource_drift" && s.opts.ResourceDrift != nil:
+			err = s.resourceChanges(s.opts.ResourceDrift)
+		case key == "planned_values" && s.opts.PlannedResource != nil:
+			err = s.plannedValues()
+		case key == "output_changes" && s.opts.OutputChange != nil:
+			err = s.outputChanges()
+		default:
+			index, ok := jsonFieldIndex(planType, key)
+			if !ok {
+				err = skipJSONValue(s.dec)
+				break
+			}
+			err = s.decode(reflect.ValueOf(s.plan).Elem().FieldByIndex(index).Addr().Interface())
+			if err == nil && key == "format_version" {
+				err = s.opts.DecodeOptions.validate(s.plan)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing brace, and ensure nothing follows it.
+	if _, err := s.dec.Token(); err != nil {
+		return err
+	}
+	if _, err := s.dec.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("unexpected data after top-level value")
+		}
+		return err
+	}
+
+	return s.opts.DecodeOptions.validate(s.plan)
+}
+
+// decode decodes the next value into v, which may contain values decoded
+// by the json.Unmarshaler implementations of this package, such as
+// Config, so is decoded according to the options.
+func (s *planStreamer) decode(v interface{}) error {
+	if !walkJSONType(reflect.TypeOf(v), false) {
+		return s.dec.Decode(v)
+	}
+
+	var raw json.RawMessage
+	if err := s.dec.Decode(&raw); err != nil {
+		return err
+	}
+
+	_, err := s.opts.DecodeOptions.Unmarshal(raw, v)
+	return err
+}
+
+func (s *planStreamer) resourceChanges(fn func(rc *ResourceChange) error) error {
+	return s.array(func() error {
+		var rc *ResourceChange
+		if err := s.decode(&rc); err != nil {
+			return err
+		}
+		if rc == nil {
+			return nil
+		}
+
+		return fn(rc)
+	})
+}
+
+func (s *planStreamer) plannedValues() error {
+	values := &StateValues{}
+	ok, err := s.object(func(key string) error {
+		switch key {
+		case "outputs":
+			return s.decode(&values.Outputs)
+		case "root_module":
+			return s.module()
+		default:
+			return skipJSONValue(s.dec)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		s.plan.PlannedValues = values
+	}
+
+	return nil
+}
+
+func (s *planStreamer) module() error {
+	_, err := s.object(func(key string) error {
+		switch key {
+		case "resources":
+			return s.array(func() error {
+				var r *StateResource
+				if err := s.decode(&r); err != nil {
+					return err
+				}
+				if r == nil {
+					return nil
+				}
+
+				return s.opts.PlannedResource(r)
+			})
+		case "child_modules":
+			return s.array(s.module)
+		default:
+			return skipJSONValue(s.dec)
+		}
+	})
+
+	return err
+}
+
+func (s *planStreamer) outputChanges() error {
+	_, err := s.object(func(name string) error {
+		var change *Change
+		if err := s.decode(&change); err != nil {
+			return err
+		}
+
+		return s.opts.OutputChange(name, change)
+	})
+
+	return err
+}
+
+// object calls fn with the key of each member of the next value, which
+// must be an object or null, with the decoder positioned at the value of
+// the member. It returns false if the value is null.
+func (s *planStreamer) object(fn func(key string) error) (bool, error) {
+	ok, err := s.expectDelim('{')
+	if err != nil || !ok {
+		return false, err
+	}
+
+	for s.dec.More() {
+		key, err := s.key()
+		if err != nil {
+			return false, err
+		}
+		if err := fn(key); err != nil {
+			return false, err
+		}
+	}
+
+	_, err = s.dec.Token()
+	return true, err
+}
+
+// array calls fn once for each element of the next value, which must be an
+// array or null, with the decoder positioned at the element.
+func (s *planStreamer) array(fn func() error) error {
+	ok, err := s.expectDelim('[')
+	if err != nil || !ok {
+		return err
+	}
+
+	for s.dec.More() {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.dec.Token()
+	return err
+}
+
+// expectDelim reads the opening delimiter of the next value, returning
+// false if the value is null.
+func (s *planStreamer) expectDelim(delim json.Delim) (bool, error) {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return false, err
+	}
+
+	switch tok {
+	case delim:
+		return true, nil
+	case nil:
+		return false, nil
+	default:
+		return false, fmt.Errorf("expected %s, got %v", delim, tok)
+	}
+}
+
+func (s *planStreamer) key() (string, error) {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return "", err
+	}
+
+	return tok.(string), nil
+}
+
+// skipJSONValue discards the next value read by dec. Values held entirely
+// within the buffer of dec are discarded without being decoded, while
+// larger objects and arrays are walked a token at a time, so that they are
+// not read into memory as a whole.
+func skipJSONValue(dec *json.Decoder) error {
+	// objects records whether each of the enclosing objects and arrays
+	// being walked is an object.
+	var objects []bool
+	for {
+		if len(objects) > 0 {
+			if !dec.More() {
+				if _, err := dec.Token(); err != nil {
+					return err
+				}
+				objects = objects[:len(objects)-1]
+				if len(objects) == 0 {
+					return nil
+				}
+				continue
+			}
+			if objects[len(objects)-1] {
+				if _, err := dec.Token(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if bufferedJSONValue(dec) {
+			if err := dec.Decode(&discardJSONValue{}); err != nil {
+				return err
+			}
+		} else {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if delim, ok := tok.(json.Delim); ok {
+				objects = append(objects, delim == '{')
+				continue
+			}
+		}
+
+		if len(objects) == 0 {
+			return nil
+		}
+	}
+}
+
+// bufferedJSONValue returns true if the next value read by dec is a string,
+// number or literal, or is an object or array held entirely within the
+// buffer of dec.
+func bufferedJSONValue(dec *json.Decoder) bool {
+	r, ok := dec.Buffered().(io.ByteReader)
+	if !ok {
+		return false
+	}
+
+	depth := 0
+	inString, escaped := false, false
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return false
+		}
+
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth <= 0 {
+				return depth == 0
+			}
+		case depth > 0:
+			inString = c == '"'
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == ',' || c == ':':
+			// Whitespace and the separator preceding the value.
+		default:
+			return true
+		}
+	}
+}
+
+// discardJSONValue discards any value decoded into it.
+type discardJSONValue struct{}
+
+func (*discardJSONValue) UnmarshalJSON([]byte) error {
+	return nil
+}
+
+// sizeLimitReader reads from r, failing with an error wrapping
+// ErrDocumentTooLarge once more than max bytes have been read.
+type sizeLimitReader struct {
+	r    io.Reader
+	max  int64
+	read int64
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	// Read one byte more than permitted, so that a document of exactly
+	// the maximum size is distinguished from a larger one.
+	if remaining := l.max + 1 - l.read; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		return 0, fmt.Errorf("%w: exceeds %d bytes", ErrDocumentTooLarge, l.max)
+	}
+
+	return n, err
+}
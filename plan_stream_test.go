@@ -0,0 +1,453 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestStreamPlan_fixtures(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(testFixtureDir, "*", testGoldenPlanFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range paths {
+		if filepath.Base(filepath.Dir(path)) == testInvalidDir {
+			continue
+		}
+
+		for name, opts := range map[string]DecodeOptions{
+			"float64":     {},
+			"json.Number": {UseJSONNumber: true},
+		} {
+			t.Run(path+"/"+name, func(t *testing.T) {
+				b, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var expected Plan
+				expected.SetDecodeOptions(opts)
+				if err := json.Unmarshal(b, &expected); err != nil {
+					t.Fatal(err)
+				}
+
+				var changes, drift []*ResourceChange
+				var resources []*StateResource
+				var outputs map[string]*Change
+				actual, err := StreamPlan(bytes.NewReader(b), PlanStreamOptions{
+					DecodeOptions: opts,
+					ResourceChange: func(rc *ResourceChange) error {
+						changes = append(changes, rc)
+						return nil
+					},
+					ResourceDrift: func(rc *ResourceChange) error {
+						drift = append(drift, rc)
+						return nil
+					},
+					PlannedResource: func(r *StateResource) error {
+						resources = append(resources, r)
+						return nil
+					},
+					OutputChange: func(name string, change *Change) error {
+						if outputs == nil {
+							outputs = make(map[string]*Change)
+						}
+						outputs[name] = change
+						return nil
+					},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var expectedResources []*StateResource
+				if expected.PlannedValues != nil {
+					expectedResources = testModuleResources(expected.PlannedValues.RootModule)
+					expected.PlannedValues.RootModule = nil
+				}
+
+				cmpOpts := cmp.Options{copyCmpOpts, cmpopts.EquateEmpty()}
+				for _, c := range []struct {
+					name             string
+					expected, actual interface{}
+				}{
+					{"resource changes", expected.ResourceChanges, changes},
+					{"resource drift", expected.ResourceDrift, drift},
+					{"planned resources", expectedResources, resources},
+					{"output changes", expected.OutputChanges, outputs},
+				} {
+					if diff := cmp.Diff(c.expected, c.actual, cmpOpts); diff != "" {
+						t.Fatalf("unexpected %s: %s", c.name, diff)
+					}
+				}
+
+				expected.ResourceChanges = nil
+				expected.ResourceDrift = nil
+				expected.OutputChanges = nil
+				if diff := cmp.Diff(&expected, actual, cmpOpts); diff != "" {
+					t.Fatalf("unexpected plan: %s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestStreamPlan_skip(t *testing.T) {
+	f := testOpenFixture(t, "basic", testGoldenPlanFileName)
+
+	var changes int
+	plan, err := StreamPlan(f, PlanStreamOptions{
+		ResourceChange: func(rc *ResourceChange) error {
+			changes++
+			return nil
+		},
+		Skip: []string{"prior_state", "configuration", "resource_changes"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changes != 0 {
+		t.Fatalf("expected skipped resource changes not to be streamed, got %d", changes)
+	}
+	if plan.PriorState != nil || plan.Config != nil {
+		t.Fatal("expected skipped sections not to be decoded")
+	}
+	if plan.PlannedValues == nil || plan.PlannedValues.RootModule == nil {
+		t.Fatal("expected planned values to be decoded")
+	}
+}
+
+func TestStreamPlan_skipLarge(t *testing.T) {
+	data := testLargePlan(t, filepath.Join(testFixtureDir, "has_changes", testGoldenPlanFileName), 50)
+
+	var expected Plan
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reading a byte at a time ensures that the skipped values extend
+	// beyond the buffer of the decoder, and so are walked a token at a
+	// time.
+	for name, r := range map[string]func() io.Reader{
+		"buffered": func() io.Reader { return bytes.NewReader(data) },
+		"one byte": func() io.Reader { return iotest.OneByteReader(bytes.NewReader(data)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			var changes []*ResourceChange
+			plan, err := StreamPlan(r(), PlanStreamOptions{
+				ResourceChange: func(rc *ResourceChange) error {
+					changes = append(changes, rc)
+					return nil
+				},
+				Skip: []string{"planned_values", "prior_state", "configuration"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(expected.ResourceChanges, changes, copyCmpOpts); diff != "" {
+				t.Fatalf("unexpected resource changes: %s", diff)
+			}
+			if plan.PlannedValues != nil || plan.PriorState != nil || plan.Config != nil {
+				t.Fatal("expected skipped sections not to be decoded")
+			}
+			if diff := cmp.Diff(expected.OutputChanges, plan.OutputChanges, copyCmpOpts); diff != "" {
+				t.Fatalf("unexpected output changes: %s", diff)
+			}
+		})
+	}
+}
+
+func TestStreamPlan_useJSONNumber(t *testing.T) {
+	var sizes []interface{}
+	plan, err := StreamPlan(strings.NewReader(testNestedNumbersPlan), PlanStreamOptions{
+		DecodeOptions: DecodeOptions{UseJSONNumber: true},
+		PlannedResource: func(r *StateResource) error {
+			sizes = append(sizes, r.AttributeValues["size"])
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expressions := plan.Config.RootModule.Resources[0].Expressions
+	actual := append(sizes,
+		plan.PriorState.Values.Outputs["id"].Value,
+		expressions["size"].ConstantValue,
+		plan.Config.RootModule.Variables["size"].Default,
+	)
+	expected := []interface{}{
+		json.Number("12345678901234567891"),
+		json.Number("12345678901234567892"),
+		json.Number("12345678901234567894"),
+		json.Number("12345678901234567896"),
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Fatalf("unexpected values: %s", diff)
+	}
+}
+
+func TestStreamPlan_errors(t *testing.T) {
+	errCallback := errors.New("callback failed")
+
+	testCases := map[string]struct {
+		raw         string
+		opts        PlanStreamOptions
+		expectedErr string
+	}{
+		"too large": {
+			raw:         `{"format_version":"1.2"}`,
+			opts:        PlanStreamOptions{DecodeOptions: DecodeOptions{MaxSize: 10}},
+			expectedErr: "document too large: exceeds 10 bytes",
+		},
+		"exact size": {
+			raw:  `{"format_version":"1.2"}`,
+			opts: PlanStreamOptions{DecodeOptions: DecodeOptions{MaxSize: 24}},
+		},
+		"unknown fields": {
+			raw:         `{"format_version":"1.2"}`,
+			opts:        PlanStreamOptions{DecodeOptions: DecodeOptions{CollectUnknownFields: true}},
+			expectedErr: "unknown fields options are not supported when streaming a plan",
+		},
+		"unsupported version": {
+			raw: `{"format_version":"2.0","resource_changes":[{"address":"null_resource.foo"}]}`,
+			opts: PlanStreamOptions{
+				ResourceChange: func(*ResourceChange) error {
+					return errCallback
+				},
+			},
+			expectedErr: `unsupported plan format version: "2.0.0" does not satisfy ">= 0.1, < 2.0"`,
+		},
+		"callback": {
+			raw: `{"format_version":"1.2","resource_changes":[{"address":"null_resource.foo"}]}`,
+			opts: PlanStreamOptions{
+				ResourceChange: func(*ResourceChange) error {
+					return errCallback
+				},
+			},
+			expectedErr: errCallback.Error(),
+		},
+		"not an object": {
+			raw:         `[]`,
+			expectedErr: "plan must be a JSON object",
+		},
+		"invalid": {
+			raw:         `{"format_version":"1.2"} {}`,
+			expectedErr: "unexpected data after top-level value",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := StreamPlan(strings.NewReader(tc.raw), tc.opts)
+			switch {
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("expected error %q", tc.expectedErr)
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("unexpected error: %s", err)
+			case err != nil && err.Error() != tc.expectedErr:
+				t.Fatalf("expected error %q, got %q", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func BenchmarkStreamPlan(b *testing.B) {
+	data := testLargePlan(b, filepath.Join(testFixtureDir, "has_changes", testGoldenPlanFileName), 1000)
+
+	b.Run("Unmarshal", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var plan Plan
+			if err := json.Unmarshal(data, &plan); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("StreamPlan", func(b *testing.B) {
+		opts := PlanStreamOptions{
+			ResourceChange:  func(*ResourceChange) error { return nil },
+			PlannedResource: func(*StateResource) error { return nil },
+			OutputChange:    func(string, *Change) error { return nil },
+		}
+
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := StreamPlan(bytes.NewReader(data), opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("StreamPlan/skip", func(b *testing.B) {
+		opts := PlanStreamOptions{
+			ResourceChange: func(*ResourceChange) error { return nil },
+			Skip:           []string{"planned_values", "prior_state", "configuration"},
+		}
+
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := StreamPlan(bytes.NewReader(data), opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkStreamPlan_peakHeap(b *testing.B) {
+	data := testLargePlan(b, filepath.Join(testFixtureDir, "has_changes", testGoldenPlanFileName), 1000)
+
+	b.Run("Unmarshal", func(b *testing.B) {
+		testBenchmarkPeakHeap(b, func() error {
+			var plan Plan
+			return json.Unmarshal(data, &plan)
+		})
+	})
+
+	b.Run("StreamPlan", func(b *testing.B) {
+		opts := PlanStreamOptions{
+			ResourceChange:  func(*ResourceChange) error { return nil },
+			PlannedResource: func(*StateResource) error { return nil },
+			OutputChange:    func(string, *Change) error { return nil },
+		}
+
+		testBenchmarkPeakHeap(b, func() error {
+			_, err := StreamPlan(bytes.NewReader(data), opts)
+			return err
+		})
+	})
+
+	b.Run("StreamPlan/skip", func(b *testing.B) {
+		opts := PlanStreamOptions{
+			ResourceChange: func(*ResourceChange) error { return nil },
+			Skip:           []string{"planned_values", "prior_state", "configuration"},
+		}
+
+		testBenchmarkPeakHeap(b, func() error {
+			_, err := StreamPlan(bytes.NewReader(data), opts)
+			return err
+		})
+	})
+}
+
+// testBenchmarkPeakHeap runs fn b.N times, and reports the greatest growth
+// of runtime.MemStats.HeapInuse, sampled every millisecond while fn runs,
+// as the "peak-heap-B" metric. The heap is collected before each run, so
+// that the growth reflects the memory fn holds rather than garbage left by
+// earlier runs.
+func testBenchmarkPeakHeap(b *testing.B, fn func() error) {
+	var peak uint64
+	for i := 0; i < b.N; i++ {
+		runtime.GC()
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		base := m.HeapInuse
+
+		done := make(chan struct{})
+		sampled := make(chan uint64)
+		go func() {
+			ticker := time.NewTicker(time.Millisecond)
+			defer ticker.Stop()
+
+			var max uint64
+			for {
+				var m runtime.MemStats
+				runtime.ReadMemStats(&m)
+				if m.HeapInuse > max {
+					max = m.HeapInuse
+				}
+
+				select {
+				case <-done:
+					sampled <- max
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+
+		err := fn()
+		close(done)
+		max := <-sampled
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		if max > base && max-base > peak {
+			peak = max - base
+		}
+	}
+
+	b.ReportMetric(float64(peak), "peak-heap-B")
+}
+
+// testModuleResources returns the resources of m and its descendants, in
+// the order StreamPlan passes them to PlanStreamOptions.PlannedResource.
+func testModuleResources(m *StateModule) []*StateResource {
+	if m == nil {
+		return nil
+	}
+
+	result := append([]*StateResource(nil), m.Resources...)
+	for _, child := range m.ChildModules {
+		result = append(result, testModuleResources(child)...)
+	}
+
+	return result
+}
+
+// testLargePlan returns the plan at path with its resource changes and
+// planned resources repeated n times.
+func testLargePlan(t testing.TB, path string, n int) []byte {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	repeat := func(v interface{}) []interface{} {
+		var result []interface{}
+		for i := 0; i < n; i++ {
+			result = append(result, v.([]interface{})...)
+		}
+		return result
+	}
+	raw["resource_changes"] = repeat(raw["resource_changes"])
+	root := raw["planned_values"].(map[string]interface{})["root_module"].(map[string]interface{})
+	root["resources"] = repeat(root["resources"])
+
+	b, err = json.Marshal(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
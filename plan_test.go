@@ -68,7 +68,7 @@ func TestPlan_015(t *testing.T) {
 		BeforeSensitive: false,
 		AfterSensitive:  map[string]interface{}{"ami": true},
 	}
-	if diff := cmp.Diff(expectedChange, plan.ResourceChanges[0].Change); diff != "" {
+	if diff := cmp.Diff(expectedChange, plan.ResourceChanges[0].Change, testCmpUnexported); diff != "" {
 		t.Fatalf("unexpected change: %s", diff)
 	}
 
@@ -78,7 +78,7 @@ func TestPlan_015(t *testing.T) {
 			Sensitive: true,
 		},
 	}
-	if diff := cmp.Diff(expectedVariable, plan.Config.RootModule.Variables); diff != "" {
+	if diff := cmp.Diff(expectedVariable, plan.Config.RootModule.Variables, testCmpUnexported); diff != "" {
 		t.Fatalf("unexpected variables: %s", diff)
 	}
 }
@@ -196,7 +196,7 @@ func TestPlan_actionInvocations(t *testing.T) {
 		},
 	}
 
-	if diff := cmp.Diff(expectedAction, plan.ActionInvocations); diff != "" {
+	if diff := cmp.Diff(expectedAction, plan.ActionInvocations, testCmpUnexported); diff != "" {
 		t.Fatalf("unexpected action invocation: %s", diff)
 	}
 }
@@ -0,0 +1,320 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultProviderRegistryHost is the hostname of the public Terraform
+	// registry, which is assumed for provider addresses that do not
+	// include a hostname.
+	DefaultProviderRegistryHost = "registry.terraform.io"
+
+	// LegacyProviderNamespace is the namespace of legacy provider
+	// addresses, which identify a provider only by its type, as in
+	// Terraform 0.12 and earlier.
+	LegacyProviderNamespace = "-"
+
+	// BuiltInProviderHost and BuiltInProviderNamespace are the hostname
+	// and namespace of providers built into Terraform, such as
+	// "terraform.io/builtin/terraform".
+	BuiltInProviderHost      = "terraform.io"
+	BuiltInProviderNamespace = "builtin"
+)
+
+// ProviderAddress is the source address of a provider, which uniquely
+// identifies it. This is the fully-qualified name found in
+// ResourceChange.ProviderName, StateResource.ProviderName,
+// ProviderConfig.FullName and the keys of ProviderSchemas.Schemas.
+type ProviderAddress struct {
+	// Hostname is the hostname of the registry the provider is
+	// installed from, such as "registry.terraform.io".
+	Hostname string
+
+	// Namespace is the organization the provider belongs to within the
+	// registry, such as "hashicorp". It is LegacyProviderNamespace for
+	// legacy addresses.
+	Namespace string
+
+	// Type is the provider type, such as "aws".
+	Type string
+}
+
+var providerPartRe = regexp.MustCompile(`^[0-9a-z](?:[0-9a-z-]*[0-9a-z])?$`)
+
+// ParseProviderAddress parses a provider source address of the form
+// [HOSTNAME/]NAMESPACE/TYPE, as produced by Terraform 0.13 and later.
+// Addresses are case-insensitive, and are normalized to lower case.
+//
+// Terraform 0.12 and earlier identified providers only by their type,
+// optionally followed by the alias of the provider configuration, as in
+// "aws" or "aws.east". Such an address is parsed as a legacy address,
+// with LegacyProviderNamespace as its namespace, and any alias is
+// discarded as it is not part of the provider address.
+func ParseProviderAddress(s string) (ProviderAddress, error) {
+	parts := strings.Split(strings.ToLower(s), "/")
+
+	addr := ProviderAddress{Hostname: DefaultProviderRegistryHost}
+	switch len(parts) {
+	case 1:
+		typ, _, _ := strings.Cut(parts[0], ".")
+		addr.Namespace, addr.Type = LegacyProviderNamespace, typ
+	case 2:
+		addr.Namespace, addr.Type = parts[0], parts[1]
+	case 3:
+		addr.Hostname, addr.Namespace, addr.Type = parts[0], parts[1], parts[2]
+		if !hostnameRe.MatchString(addr.Hostname) {
+			return ProviderAddress{}, fmt.Errorf("invalid provider address %q: invalid hostname %q", s, addr.Hostname)
+		}
+	default:
+		return ProviderAddress{}, fmt.Errorf("invalid provider address %q: must be of the form [HOSTNAME/]NAMESPACE/TYPE", s)
+	}
+
+	if addr.Namespace != LegacyProviderNamespace && !providerPartRe.MatchString(addr.Namespace) {
+		return ProviderAddress{}, fmt.Errorf("invalid provider address %q: invalid namespace %q", s, addr.Namespace)
+	}
+	if !providerPartRe.MatchString(addr.Type) {
+		return ProviderAddress{}, fmt.Errorf("invalid provider address %q: invalid type %q", s, addr.Type)
+	}
+
+	return addr, nil
+}
+
+// String returns the fully-qualified form of the address, such as
+// "registry.terraform.io/hashicorp/aws". Legacy addresses are returned as
+// the provider type alone, as Terraform 0.12 and earlier recorded them.
+func (a ProviderAddress) String() string {
+	if a.IsLegacy() {
+		return a.Type
+	}
+
+	return a.Hostname + "/" + a.Namespace + "/" + a.Type
+}
+
+// ForDisplay returns the address in the short form used in
+// configuration, omitting the hostname of the public registry.
+func (a ProviderAddress) ForDisplay() string {
+	if a.IsLegacy() || a.Hostname != DefaultProviderRegistryHost {
+		return a.String()
+	}
+
+	return a.Namespace + "/" + a.Type
+}
+
+// IsLegacy returns true if a is a legacy address, identifying a provider
+// only by its type.
+func (a ProviderAddress) IsLegacy() bool {
+	return a.Namespace == LegacyProviderNamespace
+}
+
+// IsBuiltIn returns true if a is a provider built into Terraform.
+func (a ProviderAddress) IsBuiltIn() bool {
+	return a.Hostname == BuiltInProviderHost && a.Namespace == BuiltInProviderNamespace
+}
+
+// Matches returns true if a and other identify the same provider. As when
+// Terraform 0.13 upgrades a configuration, a legacy address matches the
+// provider of the same type in the hashicorp namespace of the public
+// registry.
+func (a ProviderAddress) Matches(other ProviderAddress) bool {
+	if a == other {
+		return true
+	}
+
+	if a.Type != other.Type {
+		return false
+	}
+	if a.IsLegacy() {
+		a, other = other, a
+	}
+
+	return other.IsLegacy() && a.Hostname == DefaultProviderRegistryHost && a.Namespace == "hashicorp"
+}
+
+// ProviderConfigRef is a reference to a provider configuration, as found
+// in ConfigResource.ProviderConfigKey and the keys of
+// Config.ProviderConfigs.
+type ProviderConfigRef struct {
+	// Module is the address of the module the provider configuration is
+	// declared in, such as "module.network.module.subnets", or empty for
+	// the root module.
+	Module string
+
+	// LocalName is the name of the provider within the module, which is
+	// usually, but not necessarily, the provider type.
+	LocalName string
+
+	// Alias is the alias of the provider configuration, if any.
+	Alias string
+}
+
+// ParseProviderConfigKey parses a provider configuration key, such as
+// "aws", "aws.west" or "module.child:aws.west".
+//
+// Terraform 0.12 and earlier write the module address without "module."
+// prefixes, as in "child.grandchild:aws". These are normalized to the
+// current form, so that keys from either version can be compared. A
+// path which is valid in both forms, such as "module.a", is interpreted
+// as the current form.
+func ParseProviderConfigKey(key string) (ProviderConfigRef, error) {
+	var ref ProviderConfigRef
+
+	module, provider, found := strings.Cut(key, ":")
+	if !found {
+		module, provider = "", key
+	}
+
+	if module != "" {
+		var err error
+		ref.Module, err = normalizeModulePath(module)
+		if err != nil {
+			return ProviderConfigRef{}, fmt.Errorf("invalid provider config key %q: %w", key, err)
+		}
+	}
+
+	ref.LocalName, ref.Alias, _ = strings.Cut(provider, ".")
+	if ref.LocalName == "" || strings.HasSuffix(provider, ".") {
+		return ProviderConfigRef{}, fmt.Errorf("invalid provider config key %q", key)
+	}
+
+	return ref, nil
+}
+
+// normalizeModulePath converts a module path, which may be either an
+// address such as "module.a.module.b" or a legacy path such as "a.b",
+// into an address.
+func normalizeModulePath(path string) (string, error) {
+	steps := strings.Split(path, ".")
+	for _, step := range steps {
+		if step == "" {
+			return "", errors.New("empty module name")
+		}
+	}
+
+	isAddress := len(steps)%2 == 0
+	for i := 0; isAddress && i < len(steps); i += 2 {
+		isAddress = steps[i] == "module"
+	}
+	if isAddress {
+		return path, nil
+	}
+
+	return "module." + strings.Join(steps, ".module."), nil
+}
+
+// String returns the provider configuration key of the reference, in the
+// form used by current versions of Terraform.
+func (r ProviderConfigRef) String() string {
+	key := r.LocalName
+	if r.Alias != "" {
+		key += "." + r.Alias
+	}
+	if r.Module != "" {
+		key = r.Module + ":" + key
+	}
+
+	return key
+}
+
+// ProviderConfigRef returns a reference to the provider configuration
+// used by the resource, parsed from ProviderConfigKey.
+func (r *ConfigResource) ProviderConfigRef() (ProviderConfigRef, error) {
+	if r == nil {
+		return ProviderConfigRef{}, errors.New("resource is nil")
+	}
+
+	return ParseProviderConfigKey(r.ProviderConfigKey)
+}
+
+// Ref returns a reference to the provider configuration, which can be
+// compared with that of the resources using it.
+func (pc *ProviderConfig) Ref() ProviderConfigRef {
+	ref := ProviderConfigRef{
+		LocalName: pc.Name,
+		Alias:     pc.Alias,
+	}
+	if pc.ModuleAddress != "" {
+		// An invalid module address is retained as-is, so that it can
+		// still be compared with that of a resource.
+		if module, err := normalizeModulePath(pc.ModuleAddress); err == nil {
+			ref.Module = module
+		} else {
+			ref.Module = pc.ModuleAddress
+		}
+	}
+
+	return ref
+}
+
+// ProviderAddress returns the address of the provider. Terraform 1.1 and
+// earlier do not record FullName, in which case a legacy address is
+// returned based on Name.
+func (pc *ProviderConfig) ProviderAddress() (ProviderAddress, error) {
+	if pc == nil {
+		return ProviderAddress{}, errors.New("provider config is nil")
+	}
+
+	if pc.FullName != "" {
+		return ParseProviderAddress(pc.FullName)
+	}
+
+	return ParseProviderAddress(pc.Name)
+}
+
+// ProviderAddress returns the address of the provider managing the
+// resource, parsed from ProviderName.
+func (rc *ResourceChange) ProviderAddress() (ProviderAddress, error) {
+	if rc == nil {
+		return ProviderAddress{}, errors.New("resource change is nil")
+	}
+
+	return ParseProviderAddress(rc.ProviderName)
+}
+
+// ProviderAddress returns the address of the provider managing the
+// resource, parsed from ProviderName.
+func (r *StateResource) ProviderAddress() (ProviderAddress, error) {
+	if r == nil {
+		return ProviderAddress{}, errors.New("resource is nil")
+	}
+
+	return ParseProviderAddress(r.ProviderName)
+}
+
+// ProviderSchema returns the schema of the provider at addr, or nil if
+// there is none. Keys which are not valid provider addresses are ignored.
+// See ProviderAddress.Matches for how legacy addresses are matched.
+func (p *ProviderSchemas) ProviderSchema(addr ProviderAddress) *ProviderSchema {
+	if p == nil {
+		return nil
+	}
+
+	if schema, ok := p.Schemas[addr.String()]; ok {
+		return schema
+	}
+
+	var match *ProviderSchema
+	var matchKey string
+	for key, schema := range p.Schemas {
+		a, err := ParseProviderAddress(key)
+		if err != nil || !a.Matches(addr) {
+			continue
+		}
+		// Prefer an exact match, and otherwise the first key in order,
+		// so that the result is deterministic.
+		if a == addr {
+			return schema
+		}
+		if match == nil || key < matchKey {
+			match, matchKey = schema, key
+		}
+	}
+
+	return match
+}
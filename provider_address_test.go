@@ -0,0 +1,207 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseProviderAddress(t *testing.T) {
+	cases := []struct {
+		raw      string
+		expected ProviderAddress
+		display  string
+		legacy   bool
+	}{
+		{
+			raw:      "registry.terraform.io/hashicorp/aws",
+			expected: ProviderAddress{Hostname: "registry.terraform.io", Namespace: "hashicorp", Type: "aws"},
+			display:  "hashicorp/aws",
+		},
+		{
+			raw:      "Example.com/Acme/Widgets",
+			expected: ProviderAddress{Hostname: "example.com", Namespace: "acme", Type: "widgets"},
+			display:  "example.com/acme/widgets",
+		},
+		{
+			raw:      "integrations/github",
+			expected: ProviderAddress{Hostname: "registry.terraform.io", Namespace: "integrations", Type: "github"},
+			display:  "integrations/github",
+		},
+		{
+			raw:      "terraform.io/builtin/terraform",
+			expected: ProviderAddress{Hostname: "terraform.io", Namespace: "builtin", Type: "terraform"},
+			display:  "terraform.io/builtin/terraform",
+		},
+		{
+			raw:      "null",
+			expected: ProviderAddress{Hostname: "registry.terraform.io", Namespace: "-", Type: "null"},
+			display:  "null",
+			legacy:   true,
+		},
+		{
+			raw:      "null.aliased",
+			expected: ProviderAddress{Hostname: "registry.terraform.io", Namespace: "-", Type: "null"},
+			display:  "null",
+			legacy:   true,
+		},
+		{
+			raw:      "registry.terraform.io/-/aws",
+			expected: ProviderAddress{Hostname: "registry.terraform.io", Namespace: "-", Type: "aws"},
+			display:  "aws",
+			legacy:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.raw, func(t *testing.T) {
+			got, err := ParseProviderAddress(tc.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+			if got.ForDisplay() != tc.display {
+				t.Fatalf("expected %q, got %q", tc.display, got.ForDisplay())
+			}
+			if got.IsLegacy() != tc.legacy {
+				t.Fatalf("expected IsLegacy to be %t", tc.legacy)
+			}
+		})
+	}
+
+	for _, raw := range []string{"", "a/b/c/d", "host_name/a/b", "hashicorp/-aws", "hashicorp/aws_v2"} {
+		if _, err := ParseProviderAddress(raw); err == nil {
+			t.Errorf("%q: expected error", raw)
+		}
+	}
+}
+
+func TestProviderAddress_Matches(t *testing.T) {
+	aws, _ := ParseProviderAddress("registry.terraform.io/hashicorp/aws")
+	legacyAWS, _ := ParseProviderAddress("aws")
+	otherAWS, _ := ParseProviderAddress("example.com/hashicorp/aws")
+	null, _ := ParseProviderAddress("null")
+
+	if !aws.Matches(aws) || !aws.Matches(legacyAWS) || !legacyAWS.Matches(aws) {
+		t.Error("expected hashicorp/aws to match legacy aws")
+	}
+	if otherAWS.Matches(legacyAWS) || legacyAWS.Matches(otherAWS) {
+		t.Error("expected example.com/hashicorp/aws not to match legacy aws")
+	}
+	if legacyAWS.Matches(null) {
+		t.Error("expected aws not to match null")
+	}
+}
+
+func TestParseProviderConfigKey(t *testing.T) {
+	cases := map[string]ProviderConfigRef{
+		"aws":                       {LocalName: "aws"},
+		"aws.east":                  {LocalName: "aws", Alias: "east"},
+		"module.child:aws.west":     {Module: "module.child", LocalName: "aws", Alias: "west"},
+		"module.a.module.b:null":    {Module: "module.a.module.b", LocalName: "null"},
+		"foo:null.aliased":          {Module: "module.foo", LocalName: "null", Alias: "aliased"},
+		"foo.bar:null":              {Module: "module.foo.module.bar", LocalName: "null"},
+		"module:null":               {Module: "module.module", LocalName: "null"},
+		"module.module.module:null": {Module: "module.module.module.module.module.module", LocalName: "null"},
+	}
+
+	for key, expected := range cases {
+		got, err := ParseProviderConfigKey(key)
+		if err != nil {
+			t.Errorf("%q: %s", key, err)
+			continue
+		}
+		if diff := cmp.Diff(expected, got); diff != "" {
+			t.Errorf("%q: mismatch (-want +got):\n%s", key, diff)
+		}
+	}
+
+	for _, key := range []string{"", "aws.", "module.:aws", ":aws.east.", "module.a:"} {
+		if _, err := ParseProviderConfigKey(key); err == nil {
+			t.Errorf("%q: expected error", key)
+		}
+	}
+}
+
+func TestProviderConfigRef_join(t *testing.T) {
+	for _, dir := range []string{"basic", "110_basic", "has_checks"} {
+		t.Run(dir, func(t *testing.T) {
+			plan := testReadPlan(t, filepath.Join(testFixtureDir, dir, testGoldenPlanFileName))
+
+			configs := map[ProviderConfigRef]*ProviderConfig{}
+			for key, pc := range plan.Config.ProviderConfigs {
+				ref, err := ParseProviderConfigKey(key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(ref, pc.Ref()); diff != "" {
+					t.Fatalf("%q: key does not match config (-key +config):\n%s", key, diff)
+				}
+				configs[ref] = pc
+			}
+
+			var resources []*ConfigResource
+			var collect func(m *ConfigModule)
+			collect = func(m *ConfigModule) {
+				resources = append(resources, m.Resources...)
+				for _, call := range m.ModuleCalls {
+					collect(call.Module)
+				}
+			}
+			collect(plan.Config.RootModule)
+
+			var joined int
+			for _, r := range resources {
+				ref, err := r.ProviderConfigRef()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, ok := configs[ref]; ok {
+					joined++
+				}
+			}
+			if joined == 0 {
+				t.Fatal("expected at least one resource to join to its provider config")
+			}
+		})
+	}
+}
+
+func TestProviderSchemas_ProviderSchema(t *testing.T) {
+	schemas := &ProviderSchemas{
+		Schemas: map[string]*ProviderSchema{
+			"registry.terraform.io/hashicorp/aws": {},
+			"null":                                {},
+			"example.com/acme/null":               {},
+		},
+	}
+
+	cases := map[string]string{
+		"registry.terraform.io/hashicorp/aws":  "registry.terraform.io/hashicorp/aws",
+		"aws":                                  "registry.terraform.io/hashicorp/aws",
+		"registry.terraform.io/hashicorp/null": "null",
+		"example.com/acme/null":                "example.com/acme/null",
+	}
+
+	for raw, expected := range cases {
+		addr, err := ParseProviderAddress(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := schemas.ProviderSchema(addr); got != schemas.Schemas[expected] {
+			t.Errorf("%q: expected schema %q", raw, expected)
+		}
+	}
+
+	missing, _ := ParseProviderAddress("hashicorp/random")
+	if got := schemas.ProviderSchema(missing); got != nil {
+		t.Errorf("expected no schema for %s", missing)
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResolvedProvider describes the provider configuration used by a
+// resource in configuration.
+type ResolvedProvider struct {
+	// Resource is the resource.
+	Resource *ConfigResource
+
+	// Module is the address of the module containing the resource, such
+	// as "module.network.module.subnets", or empty for the root module.
+	Module string
+
+	// Key is the key of Config in Config.ProviderConfigs, or empty if
+	// Implicit is true.
+	Key string
+
+	// Config is the provider configuration used by the resource. If
+	// there is no configuration for the provider, it is an empty
+	// configuration in the root module, as Terraform uses in that case.
+	//
+	// Config is nil if the resource uses an aliased configuration which
+	// is not recorded. Terraform 1.1 and earlier did not record aliased
+	// configurations passed to a module, or the key of the configuration
+	// in the parent module.
+	Config *ProviderConfig
+
+	// Address is the address of the provider.
+	Address ProviderAddress
+
+	// Schema is the schema of the provider, or nil if no schemas were
+	// supplied or they do not include the provider.
+	Schema *ProviderSchema
+
+	// Inherited is true if Config is declared in a different module to
+	// the resource, either because it was passed to the module in the
+	// providers argument of a module call, or because the module
+	// implicitly inherits the default configuration of a provider from
+	// its parent.
+	Inherited bool
+
+	// Implicit is true if the provider has no configuration at all, and
+	// so Config is an empty configuration.
+	Implicit bool
+}
+
+// ResourceSchema returns the schema of the resource type from Schema, or
+// nil if it is not available.
+func (rp *ResolvedProvider) ResourceSchema() *Schema {
+	if rp.Schema == nil || rp.Resource == nil {
+		return nil
+	}
+
+	if rp.Resource.Mode == DataResourceMode {
+		return rp.Schema.DataSourceSchemas[rp.Resource.Type]
+	}
+
+	return rp.Schema.ResourceSchemas[rp.Resource.Type]
+}
+
+// ResolveProviders returns the provider configuration used by every
+// resource in c, including those within child modules. Resources are
+// ordered by module address, and then in the order they appear within
+// their module. schemas may be nil, in which case Schema is not set.
+//
+// Terraform resolves ConfigResource.ProviderConfigKey to the module that
+// declares the configuration, including following configurations passed
+// to modules, in all but the oldest versions. Where a module instead
+// inherits the default configuration of a provider implicitly, or there
+// is no configuration at all, the resolution is completed here.
+func (c *Config) ResolveProviders(schemas *ProviderSchemas) ([]*ResolvedProvider, error) {
+	if c == nil {
+		return nil, errors.New("config is nil")
+	}
+
+	configs := make(map[ProviderConfigRef]string, len(c.ProviderConfigs))
+	for key := range c.ProviderConfigs {
+		ref, err := ParseProviderConfigKey(key)
+		if err != nil {
+			return nil, err
+		}
+		configs[ref] = key
+	}
+
+	r := &providerResolver{
+		config:  c,
+		schemas: schemas,
+		configs: configs,
+	}
+
+	var result []*ResolvedProvider
+	err := r.resolveModule(c.RootModule, "", &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type providerResolver struct {
+	config  *Config
+	schemas *ProviderSchemas
+
+	// configs maps each provider configuration to its key in
+	// Config.ProviderConfigs.
+	configs map[ProviderConfigRef]string
+}
+
+func (r *providerResolver) resolveModule(m *ConfigModule, module string, result *[]*ResolvedProvider) error {
+	if m == nil {
+		return nil
+	}
+
+	for _, res := range m.Resources {
+		if res == nil {
+			continue
+		}
+
+		rp, err := r.resolve(res, module)
+		if err != nil {
+			address := res.Address
+			if module != "" {
+				address = module + "." + address
+			}
+			return fmt.Errorf("%s: %w", address, err)
+		}
+		*result = append(*result, rp)
+	}
+
+	names := make([]string, 0, len(m.ModuleCalls))
+	for name := range m.ModuleCalls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		call := m.ModuleCalls[name]
+		if call == nil {
+			continue
+		}
+
+		child := "module." + name
+		if module != "" {
+			child = module + "." + child
+		}
+		if err := r.resolveModule(call.Module, child, result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *providerResolver) resolve(res *ConfigResource, module string) (*ResolvedProvider, error) {
+	// Without a key, the resource uses the default configuration of the
+	// provider implied by its type, as in Terraform.
+	ref := ProviderConfigRef{Module: module, LocalName: impliedProviderName(res.Type)}
+	var err error
+	if res.ProviderConfigKey != "" {
+		ref, err = res.ProviderConfigRef()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rp := &ResolvedProvider{
+		Resource: res,
+		Module:   module,
+	}
+
+	key, ok := r.configs[ref]
+	if !ok && ref.Alias == "" {
+		// A module which does not configure a default provider inherits
+		// the default configuration from its parent, if any.
+		for m := parentModule(ref.Module); ; m = parentModule(m) {
+			inherited := ref
+			inherited.Module = m
+			if key, ok = r.configs[inherited]; ok || m == "" {
+				break
+			}
+		}
+	}
+
+	if ok {
+		rp.Key = key
+		rp.Config = r.config.ProviderConfigs[key]
+		rp.Inherited = rp.Config.Ref().Module != module
+		rp.Address, err = rp.Config.ProviderAddress()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		fullName := r.required(ref.LocalName, module)
+		if fullName == "" {
+			fullName = DefaultProviderRegistryHost + "/hashicorp/" + ref.LocalName
+		}
+
+		// Terraform uses an empty configuration for default providers
+		// which have none. There is no such fallback for an aliased
+		// configuration, which must have been passed to the module in a
+		// version of Terraform which did not record it.
+		if ref.Alias == "" {
+			rp.Implicit = true
+			rp.Inherited = module != ""
+			rp.Config = &ProviderConfig{
+				Name:     ref.LocalName,
+				FullName: fullName,
+			}
+		}
+
+		rp.Address, err = ParseProviderAddress(fullName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rp.Schema = r.schemas.ProviderSchema(rp.Address)
+
+	return rp, nil
+}
+
+// required returns the full name of the provider with the given local
+// name in module or its closest ancestor declaring it, or an empty string
+// if there is none.
+func (r *providerResolver) required(localName, module string) string {
+	for m := module; ; m = parentModule(m) {
+		for ref, key := range r.configs {
+			if ref.Module == m && ref.LocalName == localName {
+				if fullName := r.config.ProviderConfigs[key].FullName; fullName != "" {
+					return fullName
+				}
+			}
+		}
+		if m == "" {
+			return ""
+		}
+	}
+}
+
+// impliedProviderName returns the local name of the provider implied by a
+// resource type, which is the portion of the type before the first
+// underscore.
+func impliedProviderName(resourceType string) string {
+	name, _, _ := strings.Cut(resourceType, "_")
+	return name
+}
+
+// parentModule returns the address of the module containing the module
+// at address, which must not be the root module.
+func parentModule(address string) string {
+	i := strings.LastIndex(address, "module.")
+	if i <= 0 {
+		return ""
+	}
+
+	return address[:i-1]
+}
@@ -0,0 +1,183 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testReadSchemas(t *testing.T, path string) *ProviderSchemas {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schemas *ProviderSchemas
+	if err := json.Unmarshal(b, &schemas); err != nil {
+		t.Fatal(err)
+	}
+
+	return schemas
+}
+
+// testResolvedProvider is a summary of a ResolvedProvider for comparison.
+type testResolvedProvider struct {
+	Address   string
+	Key       string
+	Provider  string
+	Schema    bool
+	Inherited bool
+	Implicit  bool
+}
+
+func testSummarizeResolved(resolved []*ResolvedProvider) []testResolvedProvider {
+	var result []testResolvedProvider
+	for _, rp := range resolved {
+		address := rp.Resource.Address
+		if rp.Module != "" {
+			address = rp.Module + "." + address
+		}
+		result = append(result, testResolvedProvider{
+			Address:   address,
+			Key:       rp.Key,
+			Provider:  rp.Address.String(),
+			Schema:    rp.ResourceSchema() != nil,
+			Inherited: rp.Inherited,
+			Implicit:  rp.Implicit,
+		})
+	}
+
+	return result
+}
+
+func TestConfig_ResolveProviders(t *testing.T) {
+	cases := map[string][]testResolvedProvider{
+		"basic": {
+			{Address: "null_resource.bar", Key: "null", Provider: "null", Schema: true},
+			{Address: "null_resource.baz", Key: "null", Provider: "null", Schema: true},
+			{Address: "null_resource.foo", Key: "null", Provider: "null", Schema: true},
+			{Address: "data.null_data_source.baz", Key: "null", Provider: "null", Schema: true},
+			{Address: "module.foo.null_resource.aliased", Key: "foo:null.aliased", Provider: "null", Schema: true},
+			{Address: "module.foo.null_resource.foo", Key: "null", Provider: "null", Schema: true, Inherited: true},
+		},
+		"has_checks": {
+			{Address: "module.files.local_file.foo", Key: "module.files:local", Provider: "registry.terraform.io/hashicorp/local", Schema: true},
+		},
+		"deep_module": {
+			{Address: "module.foo.module.bar.null_resource.baz", Provider: "registry.terraform.io/hashicorp/null", Schema: true, Inherited: true, Implicit: true},
+		},
+	}
+
+	for dir, expected := range cases {
+		t.Run(dir, func(t *testing.T) {
+			plan := testReadPlan(t, filepath.Join(testFixtureDir, dir, testGoldenPlanFileName))
+			schemas := testReadSchemas(t, filepath.Join(testFixtureDir, dir, testGoldenSchemasFileName))
+
+			resolved, err := plan.Config.ResolveProviders(schemas)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(expected, testSummarizeResolved(resolved)); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfig_ResolveProviders_inheritance(t *testing.T) {
+	config := &Config{
+		ProviderConfigs: map[string]*ProviderConfig{
+			"aws": {
+				Name:     "aws",
+				FullName: "registry.terraform.io/hashicorp/aws",
+			},
+			"aws.west": {
+				Name:     "aws",
+				FullName: "registry.terraform.io/hashicorp/aws",
+				Alias:    "west",
+			},
+			"module.a:google": {
+				Name:          "google",
+				FullName:      "example.com/acme/google",
+				ModuleAddress: "module.a",
+			},
+		},
+		RootModule: &ConfigModule{
+			Resources: []*ConfigResource{
+				{Address: "aws_instance.east", Mode: ManagedResourceMode, Type: "aws_instance", ProviderConfigKey: "aws"},
+				{Address: "aws_instance.west", Mode: ManagedResourceMode, Type: "aws_instance", ProviderConfigKey: "aws.west"},
+			},
+			ModuleCalls: map[string]*ModuleCall{
+				"a": {
+					Module: &ConfigModule{
+						Resources: []*ConfigResource{
+							// Passed from the root module, and so resolved
+							// to it by Terraform.
+							{Address: "aws_instance.passed", Mode: ManagedResourceMode, Type: "aws_instance", ProviderConfigKey: "aws.west"},
+							// Inherited from the root module.
+							{Address: "aws_instance.inherited", Mode: ManagedResourceMode, Type: "aws_instance", ProviderConfigKey: "module.a:aws"},
+							// Required but not configured.
+							{Address: "google_project.p", Mode: ManagedResourceMode, Type: "google_project", ProviderConfigKey: "module.a:google"},
+							// Neither required nor configured.
+							{Address: "random_id.r", Mode: ManagedResourceMode, Type: "random_id"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resolved, err := config.ResolveProviders(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []testResolvedProvider{
+		{Address: "aws_instance.east", Key: "aws", Provider: "registry.terraform.io/hashicorp/aws"},
+		{Address: "aws_instance.west", Key: "aws.west", Provider: "registry.terraform.io/hashicorp/aws"},
+		{Address: "module.a.aws_instance.passed", Key: "aws.west", Provider: "registry.terraform.io/hashicorp/aws", Inherited: true},
+		{Address: "module.a.aws_instance.inherited", Key: "aws", Provider: "registry.terraform.io/hashicorp/aws", Inherited: true},
+		{Address: "module.a.google_project.p", Key: "module.a:google", Provider: "example.com/acme/google"},
+		{Address: "module.a.random_id.r", Provider: "registry.terraform.io/hashicorp/random", Inherited: true, Implicit: true},
+	}
+	if diff := cmp.Diff(expected, testSummarizeResolved(resolved)); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := resolved[5].Config; got == nil || got.Name != "random" || len(got.Expressions) != 0 {
+		t.Fatalf("expected an empty implicit configuration, got %#v", got)
+	}
+}
+
+func TestConfig_ResolveProviders_unrecordedAlias(t *testing.T) {
+	plan := testReadPlan(t, filepath.Join(testFixtureDir, "110_basic", testGoldenPlanFileName))
+
+	resolved, err := plan.Config.ResolveProviders(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rp := range resolved {
+		if rp.Resource.Address != "null_resource.aliased" {
+			continue
+		}
+		if rp.Config != nil {
+			t.Fatalf("expected no config, got %#v", rp.Config)
+		}
+		if rp.Address.String() != "registry.terraform.io/hashicorp/null" {
+			t.Fatalf("unexpected provider %s", rp.Address)
+		}
+		return
+	}
+
+	t.Fatal("resource not found")
+}
@@ -0,0 +1,503 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReferenceKind describes the kind of object a Reference refers to.
+type ReferenceKind string
+
+const (
+	// ReferenceKindVariable is a reference to an input variable, such as
+	// var.region.
+	ReferenceKindVariable ReferenceKind = "variable"
+
+	// ReferenceKindLocal is a reference to a local value, such as
+	// local.tags.
+	ReferenceKindLocal ReferenceKind = "local"
+
+	// ReferenceKindResource is a reference to a managed resource, such as
+	// aws_instance.web.
+	ReferenceKindResource ReferenceKind = "resource"
+
+	// ReferenceKindDataSource is a reference to a data source, such as
+	// data.aws_ami.ubuntu.
+	ReferenceKindDataSource ReferenceKind = "data"
+
+	// ReferenceKindEphemeralResource is a reference to an ephemeral
+	// resource, such as ephemeral.random_password.db.
+	ReferenceKindEphemeralResource ReferenceKind = "ephemeral"
+
+	// ReferenceKindAction is a reference to an action, such as
+	// action.aws_lambda_invoke.notify.
+	ReferenceKindAction ReferenceKind = "action"
+
+	// ReferenceKindModule is a reference to a whole module call, such as
+	// module.vpc.
+	ReferenceKindModule ReferenceKind = "module"
+
+	// ReferenceKindModuleOutput is a reference to an output of a module
+	// call, such as module.vpc.subnet_ids.
+	ReferenceKindModuleOutput ReferenceKind = "module_output"
+
+	// ReferenceKindPath is a reference to a filesystem path, such as
+	// path.module.
+	ReferenceKindPath ReferenceKind = "path"
+
+	// ReferenceKindTerraform is a reference to information about
+	// Terraform, such as terraform.workspace.
+	ReferenceKindTerraform ReferenceKind = "terraform"
+
+	// ReferenceKindCount is a reference to count.index.
+	ReferenceKindCount ReferenceKind = "count"
+
+	// ReferenceKindEach is a reference to each.key or each.value.
+	ReferenceKindEach ReferenceKind = "each"
+
+	// ReferenceKindSelf is a reference to the containing object, within a
+	// provisioner or connection block.
+	ReferenceKindSelf ReferenceKind = "self"
+)
+
+// Reference is a reference made by an expression, as found in
+// ExpressionData.References, parsed into the object it refers to and the
+// remainder of the traversal.
+type Reference struct {
+	// Raw is the reference as it was parsed.
+	Raw string
+
+	// Kind is the kind of object referred to.
+	Kind ReferenceKind
+
+	// Name is the name of the variable, local value, resource or module
+	// call referred to, or the attribute of path, terraform, count or
+	// each, such as "module" for path.module. It is empty for references
+	// to self.
+	Name string
+
+	// Type is the type of the resource or action referred to, for
+	// references to resources, data sources, ephemeral resources and
+	// actions.
+	Type string
+
+	// Key is the instance key of the resource, action or module call
+	// referred to, such as 0 in aws_instance.web[0], if any. It is either
+	// an int or a string.
+	Key interface{}
+
+	// Output is the name of the output referred to, for references to
+	// module outputs.
+	Output string
+
+	// Remaining is the rest of the traversal following the object
+	// referred to, such as ".private_ip" in aws_instance.web.private_ip
+	// or "[0]" in var.list[0].
+	Remaining string
+}
+
+// Subject returns the address of the object referred to, without any
+// instance key or the remainder of the traversal, such as
+// "aws_instance.web", "module.vpc.subnet_ids", "count.index" or "self".
+func (r *Reference) Subject() string {
+	switch r.Kind {
+	case ReferenceKindVariable:
+		return "var." + r.Name
+	case ReferenceKindLocal:
+		return "local." + r.Name
+	case ReferenceKindResource:
+		return r.Type + "." + r.Name
+	case ReferenceKindDataSource, ReferenceKindEphemeralResource, ReferenceKindAction:
+		return string(r.Kind) + "." + r.Type + "." + r.Name
+	case ReferenceKindModule:
+		return "module." + r.Name
+	case ReferenceKindModuleOutput:
+		return "module." + r.Name + "." + r.Output
+	case ReferenceKindSelf:
+		return "self"
+	}
+
+	return string(r.Kind) + "." + r.Name
+}
+
+// ResourceMode returns the mode of the resource referred to, or an empty
+// string if r is not a reference to a resource.
+func (r *Reference) ResourceMode() ResourceMode {
+	switch r.Kind {
+	case ReferenceKindResource:
+		return ManagedResourceMode
+	case ReferenceKindDataSource:
+		return DataResourceMode
+	case ReferenceKindEphemeralResource:
+		return EphemeralResourceMode
+	}
+
+	return ""
+}
+
+// referenceStep is a step of a traversal: either an attribute name, or an
+// index key, which is either an int or a string.
+type referenceStep struct {
+	name  string
+	key   interface{}
+	isKey bool
+
+	// offset is the offset of the step within the traversal.
+	offset int
+}
+
+// ParseReference parses a reference, such as "var.region",
+// "aws_instance.web[0].id" or `module.vpc["east"].subnet_ids`.
+func ParseReference(s string) (*Reference, error) {
+	steps, err := splitTraversal(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %q: %w", s, err)
+	}
+
+	if steps[0].isKey {
+		return nil, fmt.Errorf("invalid reference %q: must begin with a name", s)
+	}
+
+	ref := &Reference{Raw: s}
+
+	// name returns the attribute name at step i, if there is one.
+	name := func(i int) (string, bool) {
+		if i >= len(steps) || steps[i].isKey {
+			return "", false
+		}
+		return steps[i].name, true
+	}
+	// key consumes an optional instance key at step i, returning the
+	// index of the following step.
+	key := func(i int) int {
+		if i < len(steps) && steps[i].isKey {
+			ref.Key = steps[i].key
+			return i + 1
+		}
+		return i
+	}
+
+	var ok bool
+	next := 2
+	switch steps[0].name {
+	case "self":
+		// The containing object is referred to as a whole, so any
+		// attribute is part of the remainder.
+		ref.Kind = ReferenceKindSelf
+		ok = true
+		next = 1
+	case "var", "local", "path", "terraform", "count", "each":
+		ref.Kind = map[string]ReferenceKind{
+			"var":       ReferenceKindVariable,
+			"local":     ReferenceKindLocal,
+			"path":      ReferenceKindPath,
+			"terraform": ReferenceKindTerraform,
+			"count":     ReferenceKindCount,
+			"each":      ReferenceKindEach,
+		}[steps[0].name]
+		ref.Name, ok = name(1)
+	case "module":
+		ref.Kind = ReferenceKindModule
+		ref.Name, ok = name(1)
+		next = key(2)
+		if output, isOutput := name(next); ok && isOutput {
+			ref.Kind = ReferenceKindModuleOutput
+			ref.Output = output
+			next++
+		}
+	case "data", "ephemeral", "action":
+		ref.Kind = ReferenceKind(steps[0].name)
+		ref.Type, ok = name(1)
+		if ok {
+			ref.Name, ok = name(2)
+		}
+		next = key(3)
+	default:
+		ref.Kind = ReferenceKindResource
+		ref.Type = steps[0].name
+		ref.Name, ok = name(1)
+		next = key(2)
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid reference %q: incomplete %s reference", s, steps[0].name)
+	}
+
+	if next < len(steps) {
+		ref.Remaining = s[steps[next].offset:]
+	}
+
+	return ref, nil
+}
+
+// SplitModuleAddress splits an absolute address, such as
+// `module.network["east"].aws_subnet.private[0]`, into references to the
+// module calls it is nested within, each relative to the module before
+// it, such as `module.network["east"]`, and
+// the remainder of the address relative to the innermost module, such as
+// "aws_subnet.private[0]". The remainder is empty if address is the
+// address of a module instance, and is not parsed, so may be the address
+// of any object within a module, such as a provider configuration.
+func SplitModuleAddress(address string) ([]*Reference, string, error) {
+	var modules []*Reference
+	for address == "module" || strings.HasPrefix(address, "module.") {
+		ref, err := ParseReference(address)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if ref.Kind == ReferenceKindModule {
+			if ref.Remaining != "" {
+				return nil, "", fmt.Errorf("invalid address %q", address)
+			}
+			return append(modules, ref), "", nil
+		}
+
+		// The "output" of the module call is the first step of the rest
+		// of the address.
+		rest := ref.Output + ref.Remaining
+		modules = append(modules, &Reference{
+			Raw:  address[:len(address)-len(rest)-1],
+			Kind: ReferenceKindModule,
+			Name: ref.Name,
+			Key:  ref.Key,
+		})
+		address = rest
+	}
+
+	return modules, address, nil
+}
+
+// splitTraversal splits a traversal into its steps. Each step after the
+// first is either an attribute name following a period, or an index key,
+// which is a non-negative integer or a quoted string, within brackets.
+func splitTraversal(s string) ([]referenceStep, error) {
+	var steps []referenceStep
+
+	for i := 0; i < len(s); {
+		start := i
+		switch {
+		case s[i] == '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, errors.New("unterminated index")
+			}
+
+			raw := s[i+1 : i+end]
+			if strings.HasPrefix(raw, `"`) {
+				// The closing bracket may be within the string, so find
+				// the end of the string first.
+				n, err := quotedStringLength(s[i+1:])
+				if err != nil {
+					return nil, err
+				}
+				raw = s[i+1 : i+1+n]
+				if !strings.HasPrefix(s[i+1+n:], "]") {
+					return nil, errors.New("unterminated index")
+				}
+
+				key, err := strconv.Unquote(raw)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %s", raw)
+				}
+				steps = append(steps, referenceStep{key: key, isKey: true, offset: start})
+				i += n + 2
+				continue
+			}
+
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid index %s", raw)
+			}
+			steps = append(steps, referenceStep{key: n, isKey: true, offset: start})
+			i += end + 1
+		case s[i] == '.' && len(steps) > 0:
+			i++
+			fallthrough
+		case len(steps) == 0:
+			end := i
+			for end < len(s) && s[end] != '.' && s[end] != '[' {
+				end++
+			}
+			if end == i {
+				return nil, errors.New("empty attribute name")
+			}
+			steps = append(steps, referenceStep{name: s[i:end], offset: start})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected %q", s[i])
+		}
+	}
+
+	if len(steps) == 0 {
+		return nil, errors.New("empty reference")
+	}
+
+	return steps, nil
+}
+
+// quotedStringLength returns the length of the quoted string at the start
+// of s, including its quotes.
+func quotedStringLength(s string) (int, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+
+	return 0, errors.New("unterminated string")
+}
+
+// ReduceReferences returns refs without duplicates, and without any
+// reference which is a prefix of another. Terraform reports both a
+// traversal such as aws_instance.web.id and the objects it traverses,
+// aws_instance.web, which are redundant for most purposes. The order of
+// the remaining references is preserved.
+func ReduceReferences(refs []string) []string {
+	var result []string
+	for i, ref := range refs {
+		redundant := false
+		for j, other := range refs {
+			if other == ref && j < i || isTraversalPrefix(ref, other) {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			result = append(result, ref)
+		}
+	}
+
+	return result
+}
+
+// isTraversalPrefix returns true if prefix is a traversal which s
+// traverses through, such as aws_instance.web for aws_instance.web.id,
+// but not aws_instance.webserver.
+func isTraversalPrefix(prefix, s string) bool {
+	if len(s) <= len(prefix) || !strings.HasPrefix(s, prefix) {
+		return false
+	}
+
+	next := s[len(prefix)]
+	return next == '.' || next == '['
+}
+
+// ParsedReferences returns the references made by the expression and any
+// nested blocks within it, parsed, with redundant references removed as
+// by ReduceReferences.
+func (e *Expression) ParsedReferences() ([]*Reference, error) {
+	if e == nil || e.ExpressionData == nil {
+		return nil, nil
+	}
+
+	refs := expressionReferences(e)
+	result := make([]*Reference, 0, len(refs))
+	for _, raw := range ReduceReferences(refs) {
+		ref, err := ParseReference(raw)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ref)
+	}
+
+	return result, nil
+}
+
+func expressionReferences(e *Expression) []string {
+	if e == nil || e.ExpressionData == nil {
+		return nil
+	}
+
+	refs := e.References
+	for _, block := range e.NestedBlocks {
+		for _, name := range sortedKeys(block) {
+			refs = append(refs, expressionReferences(block[name])...)
+		}
+	}
+
+	return refs
+}
+
+// ReferencedResource returns the resource, data source or ephemeral
+// resource declared in m which ref refers to, or nil if ref does not
+// refer to a resource or it is not declared in m.
+func (m *ConfigModule) ReferencedResource(ref *Reference) *ConfigResource {
+	if m == nil || ref == nil {
+		return nil
+	}
+
+	mode := ref.ResourceMode()
+	if mode == "" {
+		return nil
+	}
+
+	for _, r := range m.Resources {
+		if r != nil && r.Mode == mode && r.Type == ref.Type && r.Name == ref.Name {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// ReferencedAction returns the action declared in m which ref refers to,
+// or nil if ref does not refer to an action or it is not declared in m.
+func (m *ConfigModule) ReferencedAction(ref *Reference) *ConfigAction {
+	if m == nil || ref == nil || ref.Kind != ReferenceKindAction {
+		return nil
+	}
+
+	for _, a := range m.Actions {
+		if a != nil && a.Type == ref.Type && a.Name == ref.Name {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// ReferencedVariable returns the variable declared in m which ref refers
+// to, or nil if ref does not refer to a variable or it is not declared in
+// m.
+func (m *ConfigModule) ReferencedVariable(ref *Reference) *ConfigVariable {
+	if m == nil || ref == nil || ref.Kind != ReferenceKindVariable {
+		return nil
+	}
+
+	return m.Variables[ref.Name]
+}
+
+// ReferencedModuleCall returns the module call in m which ref refers to,
+// either as a whole or through one of its outputs, or nil if ref does not
+// refer to a module or it is not called from m.
+func (m *ConfigModule) ReferencedModuleCall(ref *Reference) *ModuleCall {
+	if m == nil || ref == nil || (ref.Kind != ReferenceKindModule && ref.Kind != ReferenceKindModuleOutput) {
+		return nil
+	}
+
+	return m.ModuleCalls[ref.Name]
+}
+
+// ReferencedOutput returns the output of the module called from m which
+// ref refers to, or nil if ref does not refer to a module output, or the
+// module or output is not declared.
+func (m *ConfigModule) ReferencedOutput(ref *Reference) *ConfigOutput {
+	if ref == nil || ref.Kind != ReferenceKindModuleOutput {
+		return nil
+	}
+
+	call := m.ReferencedModuleCall(ref)
+	if call == nil || call.Module == nil {
+		return nil
+	}
+
+	return call.Module.Outputs[ref.Output]
+}
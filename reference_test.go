@@ -0,0 +1,326 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseReference(t *testing.T) {
+	cases := []struct {
+		raw      string
+		expected Reference
+		subject  string
+	}{
+		{
+			raw:      "var.region",
+			expected: Reference{Kind: ReferenceKindVariable, Name: "region"},
+			subject:  "var.region",
+		},
+		{
+			raw:      "var.list[0].name",
+			expected: Reference{Kind: ReferenceKindVariable, Name: "list", Remaining: "[0].name"},
+			subject:  "var.list",
+		},
+		{
+			raw:      "local.tags",
+			expected: Reference{Kind: ReferenceKindLocal, Name: "tags"},
+			subject:  "local.tags",
+		},
+		{
+			raw:      "aws_instance.web[0].id",
+			expected: Reference{Kind: ReferenceKindResource, Type: "aws_instance", Name: "web", Key: 0, Remaining: ".id"},
+			subject:  "aws_instance.web",
+		},
+		{
+			raw:      `aws_instance.web["a.b]"].tags["Name"]`,
+			expected: Reference{Kind: ReferenceKindResource, Type: "aws_instance", Name: "web", Key: "a.b]", Remaining: `.tags["Name"]`},
+			subject:  "aws_instance.web",
+		},
+		{
+			raw:      "data.aws_ami.ubuntu.id",
+			expected: Reference{Kind: ReferenceKindDataSource, Type: "aws_ami", Name: "ubuntu", Remaining: ".id"},
+			subject:  "data.aws_ami.ubuntu",
+		},
+		{
+			raw:      "ephemeral.random_password.db",
+			expected: Reference{Kind: ReferenceKindEphemeralResource, Type: "random_password", Name: "db"},
+			subject:  "ephemeral.random_password.db",
+		},
+		{
+			raw:      `action.aws_lambda_invoke.notify["a"]`,
+			expected: Reference{Kind: ReferenceKindAction, Type: "aws_lambda_invoke", Name: "notify", Key: "a"},
+			subject:  "action.aws_lambda_invoke.notify",
+		},
+		{
+			raw:      "module.vpc",
+			expected: Reference{Kind: ReferenceKindModule, Name: "vpc"},
+			subject:  "module.vpc",
+		},
+		{
+			raw:      `module.vpc["east"]`,
+			expected: Reference{Kind: ReferenceKindModule, Name: "vpc", Key: "east"},
+			subject:  "module.vpc",
+		},
+		{
+			raw:      `module.vpc["east"].subnet_ids[1]`,
+			expected: Reference{Kind: ReferenceKindModuleOutput, Name: "vpc", Key: "east", Output: "subnet_ids", Remaining: "[1]"},
+			subject:  "module.vpc.subnet_ids",
+		},
+		{
+			raw:      "path.module",
+			expected: Reference{Kind: ReferenceKindPath, Name: "module"},
+			subject:  "path.module",
+		},
+		{
+			raw:      "terraform.workspace",
+			expected: Reference{Kind: ReferenceKindTerraform, Name: "workspace"},
+			subject:  "terraform.workspace",
+		},
+		{
+			raw:      "count.index",
+			expected: Reference{Kind: ReferenceKindCount, Name: "index"},
+			subject:  "count.index",
+		},
+		{
+			raw:      "each.value.name",
+			expected: Reference{Kind: ReferenceKindEach, Name: "value", Remaining: ".name"},
+			subject:  "each.value",
+		},
+		{
+			raw:      "self.private_ip",
+			expected: Reference{Kind: ReferenceKindSelf, Remaining: ".private_ip"},
+			subject:  "self",
+		},
+		{
+			raw:      "self",
+			expected: Reference{Kind: ReferenceKindSelf},
+			subject:  "self",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.raw, func(t *testing.T) {
+			ref, err := ParseReference(tc.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expected.Raw = tc.raw
+			if diff := cmp.Diff(&tc.expected, ref); diff != "" {
+				t.Fatalf("unexpected reference (-want +got):\n%s", diff)
+			}
+			if got := ref.Subject(); got != tc.subject {
+				t.Fatalf("expected subject %q, got %q", tc.subject, got)
+			}
+		})
+	}
+}
+
+func TestParseReference_invalid(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"var",
+		"module",
+		"module[0]",
+		"data.aws_ami",
+		"aws_instance",
+		"aws_instance[0]",
+		"[0]",
+		"var..x",
+		"var.x.",
+		"var.x[",
+		"var.x[-1]",
+		"var.x[a]",
+		`var.x["a]`,
+		`var.x["a"b]`,
+	} {
+		if _, err := ParseReference(raw); err == nil {
+			t.Errorf("%q: expected error", raw)
+		}
+	}
+}
+
+func TestSplitModuleAddress(t *testing.T) {
+	cases := map[string]struct {
+		modules []string
+		rest    string
+	}{
+		"aws_instance.web[0]":                             {nil, "aws_instance.web[0]"},
+		`module.a["x.y"].aws_instance.web["k]"]`:          {[]string{`module.a["x.y"]`}, `aws_instance.web["k]"]`},
+		`module.a[0].module.b["c"].data.d.e`:              {[]string{"module.a[0]", `module.b["c"]`}, "data.d.e"},
+		`module.a["quote \" ] ."].module.b`:               {[]string{`module.a["quote \" ] ."]`, "module.b"}, ""},
+		`provider["registry.terraform.io/hashicorp/aws"]`: {nil, `provider["registry.terraform.io/hashicorp/aws"]`},
+		"": {nil, ""},
+	}
+
+	for address, tc := range cases {
+		modules, rest, err := SplitModuleAddress(address)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", address, err)
+			continue
+		}
+
+		var raw []string
+		for _, m := range modules {
+			if m.Kind != ReferenceKindModule {
+				t.Errorf("%s: unexpected kind %q for %s", address, m.Kind, m.Raw)
+			}
+			raw = append(raw, m.Raw)
+		}
+		if diff := cmp.Diff(tc.modules, raw); diff != "" {
+			t.Errorf("%s: unexpected modules: %s", address, diff)
+		}
+		if rest != tc.rest {
+			t.Errorf("%s: expected rest %q, got %q", address, tc.rest, rest)
+		}
+	}
+
+	for _, address := range []string{"module", "module.a[0][1]", `module.a["x`} {
+		if _, _, err := SplitModuleAddress(address); err == nil {
+			t.Errorf("%s: expected error", address)
+		}
+	}
+}
+
+func TestReduceReferences(t *testing.T) {
+	cases := []struct {
+		refs     []string
+		expected []string
+	}{
+		{nil, nil},
+		{
+			[]string{"null_resource.foo.id", "null_resource.foo"},
+			[]string{"null_resource.foo.id"},
+		},
+		{
+			[]string{`module.vpc["a"]`, "module.vpc", `module.vpc["a"].id`},
+			[]string{`module.vpc["a"].id`},
+		},
+		{
+			[]string{"aws_instance.web", "aws_instance.webserver", "var.a", "var.a"},
+			[]string{"aws_instance.web", "aws_instance.webserver", "var.a"},
+		},
+	}
+
+	for _, tc := range cases {
+		if diff := cmp.Diff(tc.expected, ReduceReferences(tc.refs)); diff != "" {
+			t.Errorf("%q: unexpected result (-want +got):\n%s", tc.refs, diff)
+		}
+	}
+}
+
+func TestExpressionParsedReferences(t *testing.T) {
+	e := &Expression{ExpressionData: &ExpressionData{
+		NestedBlocks: []map[string]*Expression{
+			{
+				"b": {ExpressionData: &ExpressionData{References: []string{"var.b"}}},
+				"a": {ExpressionData: &ExpressionData{References: []string{"aws_instance.web.id", "aws_instance.web"}}},
+			},
+			{
+				"a": {ExpressionData: &ExpressionData{References: []string{"var.b"}}},
+			},
+		},
+	}}
+
+	refs, err := e.ParsedReferences()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.Raw)
+	}
+	if diff := cmp.Diff([]string{"aws_instance.web.id", "var.b"}, got); diff != "" {
+		t.Fatalf("unexpected references (-want +got):\n%s", diff)
+	}
+
+	var nilExpr *Expression
+	if refs, err := nilExpr.ParsedReferences(); err != nil || refs != nil {
+		t.Fatalf("expected no references, got %v, %v", refs, err)
+	}
+}
+
+func TestConfigModuleReferenced(t *testing.T) {
+	plan := testReadPlan(t, filepath.Join(testFixtureDir, "013_module_depends_on", testGoldenPlanFileName))
+	m := plan.Config.RootModule.ModuleCalls["foo"].Module
+
+	ref, err := ParseReference("null_resource.resource.id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := m.ReferencedResource(ref); r == nil || r.Address != "null_resource.resource" {
+		t.Fatalf("expected null_resource.resource, got %#v", r)
+	}
+
+	ref, err = ParseReference("data.null_data_source.data.outputs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := m.ReferencedResource(ref); r == nil || r.Address != "data.null_data_source.data" {
+		t.Fatalf("expected data.null_data_source.data, got %#v", r)
+	}
+
+	// A managed resource of the same type and name does not match a data
+	// source reference.
+	ref.Kind = ReferenceKindResource
+	if r := m.ReferencedResource(ref); r != nil {
+		t.Fatalf("expected no resource, got %#v", r)
+	}
+
+	child := &ConfigModule{
+		Outputs:   map[string]*ConfigOutput{"ids": {Description: "ids"}},
+		Variables: map[string]*ConfigVariable{"region": {Description: "region"}},
+	}
+	m = &ConfigModule{
+		ModuleCalls: map[string]*ModuleCall{"vpc": {Source: "./vpc", Module: child}},
+		Variables:   map[string]*ConfigVariable{"region": {Description: "root"}},
+	}
+
+	ref, err = ParseReference(`module.vpc["east"].ids[0]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o := m.ReferencedOutput(ref); o != child.Outputs["ids"] {
+		t.Fatalf("expected output ids, got %#v", o)
+	}
+	if call := m.ReferencedModuleCall(ref); call != m.ModuleCalls["vpc"] {
+		t.Fatalf("expected module call vpc, got %#v", call)
+	}
+
+	ref, err = ParseReference("module.vpc.missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o := m.ReferencedOutput(ref); o != nil {
+		t.Fatalf("expected no output, got %#v", o)
+	}
+
+	ref, err = ParseReference("var.region")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := m.ReferencedVariable(ref); v != m.Variables["region"] {
+		t.Fatalf("expected root variable, got %#v", v)
+	}
+	if o := m.ReferencedOutput(ref); o != nil {
+		t.Fatalf("expected no output for variable reference, got %#v", o)
+	}
+
+	plan = testReadPlan(t, filepath.Join(testFixtureDir, "config_constructs", testGoldenPlanFileName))
+	ref, err = ParseReference("action.demo_notify.deployed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := plan.Config.RootModule.ReferencedAction(ref); a != plan.Config.RootModule.Actions[1] {
+		t.Fatalf("expected action, got %#v", a)
+	}
+	if r := plan.Config.RootModule.ReferencedResource(ref); r != nil {
+		t.Fatalf("expected no resource for action reference, got %#v", r)
+	}
+}
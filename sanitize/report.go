@@ -0,0 +1,146 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package sanitize
+
+import (
+	"fmt"
+	"sort"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// RedactionReason describes why a value was redacted.
+type RedactionReason string
+
+const (
+	// RedactionReasonSensitiveMask indicates a resource value was marked
+	// as sensitive in the BeforeSensitive or AfterSensitive data of its
+	// change.
+	RedactionReasonSensitiveMask RedactionReason = "sensitive_mask"
+
+	// RedactionReasonSensitiveVariable indicates the value of a root
+	// module variable declared as sensitive.
+	RedactionReasonSensitiveVariable RedactionReason = "sensitive_variable"
+
+	// RedactionReasonSensitiveOutput indicates the value of an output
+	// declared as sensitive.
+	RedactionReasonSensitiveOutput RedactionReason = "sensitive_output"
+
+	// RedactionReasonCustomRule indicates a value matched by a Rule.
+	RedactionReasonCustomRule RedactionReason = "custom_rule"
+)
+
+// RedactionLocation describes which part of a Plan a redacted value was
+// found in.
+type RedactionLocation string
+
+const (
+	// RedactionLocationBefore is the Before value of a resource or output
+	// change.
+	RedactionLocationBefore RedactionLocation = "before"
+
+	// RedactionLocationAfter is the After value of a resource or output
+	// change.
+	RedactionLocationAfter RedactionLocation = "after"
+
+	// RedactionLocationPlanned is PlannedValues.
+	RedactionLocationPlanned RedactionLocation = "planned"
+
+	// RedactionLocationPrior is PriorState.
+	RedactionLocationPrior RedactionLocation = "prior"
+
+	// RedactionLocationVariable is Variables.
+	RedactionLocationVariable RedactionLocation = "variable"
+)
+
+// Redaction describes a single value that was replaced during
+// sanitization. It never contains the value itself.
+type Redaction struct {
+	// Address is the absolute address of the resource instance, or
+	// "var.NAME" and "output.NAME" for variables and outputs.
+	Address string `json:"address"`
+
+	// Location is the part of the plan the value was found in.
+	Location RedactionLocation `json:"location"`
+
+	// Path is the path to the redacted attribute within the value at
+	// Address, using the same representation as Change.ReplacePaths:
+	// each step is either a string key or an integer index. An empty
+	// path means the whole value was redacted.
+	Path []interface{} `json:"path,omitempty"`
+
+	// Reason is why the value was redacted.
+	Reason RedactionReason `json:"reason"`
+
+	// Rule is the name of the Rule that matched, if Reason is
+	// RedactionReasonCustomRule.
+	Rule string `json:"rule,omitempty"`
+}
+
+// Report lists every redaction made while sanitizing a Plan. It is
+// designed to be stored alongside the sanitized plan, and so can be
+// marshaled to JSON.
+type Report struct {
+	Redactions []Redaction `json:"redactions"`
+}
+
+// Rule is a custom redaction rule, applied to resource attribute values
+// in addition to the sensitivity data supplied by Terraform.
+type Rule struct {
+	// Name identifies the rule in a Report.
+	Name string
+
+	// Match is called for every attribute value, at every level of
+	// nesting, within the resource instance at address, and returns true
+	// if the value at path should be redacted. Null values and values
+	// already redacted due to sensitivity data are not passed to Match,
+	// and the children of a matched value are not visited.
+	Match func(address string, path []interface{}, value interface{}) bool
+}
+
+// SanitizePlanWithReport sanitizes a Plan in the same way as
+// SanitizePlanWithValue, additionally redacting any resource attribute
+// values matched by the supplied rules. Along with the sanitized copy of
+// the Plan, it returns a Report of every value that was redacted.
+func SanitizePlanWithReport(old *tfjson.Plan, replaceWith interface{}, rules ...Rule) (*tfjson.Plan, *Report, error) {
+	if old == nil {
+		return nil, nil, NilPlanError
+	}
+
+	report := &Report{Redactions: []Redaction{}}
+	s := &sanitizer{
+		replaceWith: replaceWith,
+		rules:       rules,
+		report:      report,
+	}
+
+	result := s.plan(old)
+	report.sort()
+
+	return result, report, nil
+}
+
+var redactionLocationOrder = map[RedactionLocation]int{
+	RedactionLocationVariable: 0,
+	RedactionLocationBefore:   1,
+	RedactionLocationAfter:    2,
+	RedactionLocationPrior:    3,
+	RedactionLocationPlanned:  4,
+}
+
+// sort orders the redactions in the report, as maps are visited in an
+// unspecified order during sanitization.
+func (r *Report) sort() {
+	sort.SliceStable(r.Redactions, func(i, j int) bool {
+		a, b := r.Redactions[i], r.Redactions[j]
+		if a.Location != b.Location {
+			return redactionLocationOrder[a.Location] < redactionLocationOrder[b.Location]
+		}
+		if a.Address != b.Address {
+			return a.Address < b.Address
+		}
+
+		return fmt.Sprintf("%v", a.Path) < fmt.Sprintf("%v", b.Path)
+	})
+}
@@ -0,0 +1,248 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package sanitize
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func TestSanitizePlanWithReport(t *testing.T) {
+	plan := &tfjson.Plan{
+		Variables: map[string]*tfjson.PlanVariable{
+			"token":  {Value: "hunter2"},
+			"region": {Value: "us-east-1"},
+		},
+		ResourceChanges: []*tfjson.ResourceChange{
+			{
+				Address: "aws_db_instance.main",
+				Change: &tfjson.Change{
+					Before: map[string]interface{}{
+						"password": "old",
+						"tags":     map[string]interface{}{"owner": "me"},
+					},
+					After: map[string]interface{}{
+						"password": "new",
+						"tags":     map[string]interface{}{"owner": "me"},
+						"users":    []interface{}{"a", "b"},
+					},
+					BeforeSensitive: map[string]interface{}{"password": true},
+					AfterSensitive: map[string]interface{}{
+						"password": true,
+						"users":    []interface{}{false, true},
+					},
+				},
+			},
+		},
+		OutputChanges: map[string]*tfjson.Change{
+			"secret": {
+				Before:          "foo",
+				After:           "bar",
+				BeforeSensitive: true,
+				AfterSensitive:  true,
+			},
+		},
+		PlannedValues: &tfjson.StateValues{
+			Outputs: map[string]*tfjson.StateOutput{
+				"secret": {Sensitive: true, Value: "bar"},
+			},
+			RootModule: &tfjson.StateModule{
+				Resources: []*tfjson.StateResource{
+					{
+						Address: "aws_db_instance.main",
+						AttributeValues: map[string]interface{}{
+							"password": "new",
+							"tags":     map[string]interface{}{"owner": "me"},
+							"users":    []interface{}{"a", "b"},
+						},
+					},
+				},
+			},
+		},
+		Config: &tfjson.Config{
+			RootModule: &tfjson.ConfigModule{
+				Variables: map[string]*tfjson.ConfigVariable{
+					"token":  {Sensitive: true},
+					"region": {},
+				},
+			},
+		},
+	}
+
+	ownerRule := Rule{
+		Name: "owner-tags",
+		Match: func(address string, path []interface{}, value interface{}) bool {
+			return len(path) == 2 && path[0] == "tags" && path[1] == "owner"
+		},
+	}
+
+	result, report, err := SanitizePlanWithReport(plan, DefaultSensitiveValue, ownerRule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Redaction{
+		{Address: "var.token", Location: RedactionLocationVariable, Reason: RedactionReasonSensitiveVariable},
+		{Address: "aws_db_instance.main", Location: RedactionLocationBefore, Path: []interface{}{"password"}, Reason: RedactionReasonSensitiveMask},
+		{Address: "aws_db_instance.main", Location: RedactionLocationBefore, Path: []interface{}{"tags", "owner"}, Reason: RedactionReasonCustomRule, Rule: "owner-tags"},
+		{Address: "output.secret", Location: RedactionLocationBefore, Reason: RedactionReasonSensitiveOutput},
+		{Address: "aws_db_instance.main", Location: RedactionLocationAfter, Path: []interface{}{"password"}, Reason: RedactionReasonSensitiveMask},
+		{Address: "aws_db_instance.main", Location: RedactionLocationAfter, Path: []interface{}{"tags", "owner"}, Reason: RedactionReasonCustomRule, Rule: "owner-tags"},
+		{Address: "aws_db_instance.main", Location: RedactionLocationAfter, Path: []interface{}{"users", 1}, Reason: RedactionReasonSensitiveMask},
+		{Address: "output.secret", Location: RedactionLocationAfter, Reason: RedactionReasonSensitiveOutput},
+		{Address: "aws_db_instance.main", Location: RedactionLocationPlanned, Path: []interface{}{"password"}, Reason: RedactionReasonSensitiveMask},
+		{Address: "aws_db_instance.main", Location: RedactionLocationPlanned, Path: []interface{}{"tags", "owner"}, Reason: RedactionReasonCustomRule, Rule: "owner-tags"},
+		{Address: "aws_db_instance.main", Location: RedactionLocationPlanned, Path: []interface{}{"users", 1}, Reason: RedactionReasonSensitiveMask},
+		{Address: "output.secret", Location: RedactionLocationPlanned, Reason: RedactionReasonSensitiveOutput},
+	}
+	if diff := cmp.Diff(expected, report.Redactions); diff != "" {
+		t.Errorf("SanitizePlanWithReport() report mismatch (-expected +actual):\n%s", diff)
+	}
+
+	tags := result.ResourceChanges[0].Change.After.(map[string]interface{})["tags"]
+	if diff := cmp.Diff(map[string]interface{}{"owner": DefaultSensitiveValue}, tags); diff != "" {
+		t.Errorf("custom rule was not applied (-expected +actual):\n%s", diff)
+	}
+
+	// The report must never contain the values it describes.
+	b, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"hunter2", "old", "new", "\"bar\"", "\"me\""} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("report contains value %s: %s", secret, b)
+		}
+	}
+}
+
+func TestSanitizePlanWithReport_ruleMatchesObject(t *testing.T) {
+	values := func() map[string]interface{} {
+		return map[string]interface{}{"password": "hunter2", "name": "db"}
+	}
+	plan := &tfjson.Plan{
+		ResourceChanges: []*tfjson.ResourceChange{
+			{
+				Address: "aws_db_instance.main",
+				Change: &tfjson.Change{
+					Actions: tfjson.Actions{tfjson.ActionUpdate},
+					Before:  values(),
+					After:   values(),
+				},
+			},
+		},
+		PlannedValues: &tfjson.StateValues{
+			RootModule: &tfjson.StateModule{
+				Resources: []*tfjson.StateResource{
+					{Address: "aws_db_instance.main", AttributeValues: values()},
+				},
+			},
+		},
+		PriorState: &tfjson.State{
+			Values: &tfjson.StateValues{
+				RootModule: &tfjson.StateModule{
+					Resources: []*tfjson.StateResource{
+						{Address: "aws_db_instance.main", AttributeValues: values()},
+					},
+				},
+			},
+		},
+	}
+
+	dbRule := Rule{
+		Name: "db",
+		Match: func(address string, path []interface{}, value interface{}) bool {
+			return address == "aws_db_instance.main" && len(path) == 0
+		},
+	}
+
+	result, report, err := SanitizePlanWithReport(plan, DefaultSensitiveValue, dbRule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Redactions) != 4 {
+		t.Errorf("expected 4 redactions, got %#v", report.Redactions)
+	}
+
+	redacted := map[string]interface{}{
+		"password": DefaultSensitiveValue,
+		"name":     DefaultSensitiveValue,
+	}
+	for name, actual := range map[string]interface{}{
+		"before":  result.ResourceChanges[0].Change.Before,
+		"after":   result.ResourceChanges[0].Change.After,
+		"planned": result.PlannedValues.RootModule.Resources[0].AttributeValues,
+		"prior":   result.PriorState.Values.RootModule.Resources[0].AttributeValues,
+	} {
+		expected := interface{}(redacted)
+		if name == "before" || name == "after" {
+			expected = DefaultSensitiveValue
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Errorf("%s values were not redacted (-expected +actual):\n%s", name, diff)
+		}
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "hunter2") {
+		t.Errorf("sanitized plan contains redacted value: %s", b)
+	}
+}
+
+func TestSanitizePlanWithReport_ruleSkipsNull(t *testing.T) {
+	plan := &tfjson.Plan{
+		ResourceChanges: []*tfjson.ResourceChange{
+			{
+				Address: "aws_db_instance.main",
+				Change: &tfjson.Change{
+					Actions: tfjson.Actions{tfjson.ActionCreate},
+					After:   map[string]interface{}{"password": "hunter2", "name": nil},
+				},
+			},
+		},
+	}
+
+	allRule := Rule{
+		Name: "all",
+		Match: func(address string, path []interface{}, value interface{}) bool {
+			return len(path) > 0 || value == nil
+		},
+	}
+
+	result, report, err := SanitizePlanWithReport(plan, DefaultSensitiveValue, allRule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Redaction{
+		{Address: "aws_db_instance.main", Location: RedactionLocationAfter, Path: []interface{}{"password"}, Reason: RedactionReasonCustomRule, Rule: "all"},
+	}
+	if diff := cmp.Diff(expected, report.Redactions); diff != "" {
+		t.Errorf("SanitizePlanWithReport() report mismatch (-expected +actual):\n%s", diff)
+	}
+
+	change := result.ResourceChanges[0].Change
+	if change.Before != nil {
+		t.Errorf("expected Before to remain null, got %#v", change.Before)
+	}
+	expectedAfter := map[string]interface{}{"password": DefaultSensitiveValue, "name": nil}
+	if diff := cmp.Diff(expectedAfter, change.After); diff != "" {
+		t.Errorf("unexpected After (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestSanitizePlanWithReport_nil(t *testing.T) {
+	_, _, err := SanitizePlanWithReport(nil, DefaultSensitiveValue)
+	if err != NilPlanError {
+		t.Fatalf("expected NilPlanError, got %v", err)
+	}
+}
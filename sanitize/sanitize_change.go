@@ -11,46 +11,38 @@ import (
 // the particular locations marked by BeforeSensitive AfterSensitive
 // with the value supplied as replaceWith.
 //
-// A new change is issued.
+// A new change is issued. A nil change results in a nil change being
+// returned.
 func SanitizeChange(old *tfjson.Change, replaceWith interface{}) (*tfjson.Change, error) {
-	result, err := copyChange(old)
-	if err != nil {
-		return nil, err
-	}
+	result := old.DeepCopy()
 
-	result.Before = sanitizeChangeValue(result.Before, result.BeforeSensitive, replaceWith)
-	result.After = sanitizeChangeValue(result.After, result.AfterSensitive, replaceWith)
+	s := &sanitizer{replaceWith: replaceWith}
+	s.change(result, "", RedactionReasonSensitiveMask)
 
 	return result, nil
 }
 
-func sanitizeChangeValue(old, sensitive, replaceWith interface{}) interface{} {
-	// Only expect deep types that we would normally see in JSON, so
-	// arrays and objects.
-	switch x := old.(type) {
-	case []interface{}:
-		if filterSlice, ok := sensitive.([]interface{}); ok {
-			for i := range filterSlice {
-				if i >= len(x) {
-					break
-				}
-
-				x[i] = sanitizeChangeValue(x[i], filterSlice[i], replaceWith)
-			}
-		}
-	case map[string]interface{}:
-		if filterMap, ok := sensitive.(map[string]interface{}); ok {
-			for filterKey := range filterMap {
-				if value, ok := x[filterKey]; ok {
-					x[filterKey] = sanitizeChangeValue(value, filterMap[filterKey], replaceWith)
-				}
-			}
-		}
-	}
-
-	if shouldFilter, ok := sensitive.(bool); ok && shouldFilter {
-		return replaceWith
+// change sanitizes a Change in place. address and reason are used when
+// reporting redactions.
+func (s *sanitizer) change(c *tfjson.Change, address string, reason RedactionReason) {
+	if c == nil {
+		return
 	}
 
-	return old
+	// Custom rules only apply to resource values, which are the only
+	// ones reported as masked by sensitivity data.
+	applyRules := reason == RedactionReasonSensitiveMask
+
+	c.Before = s.value(c.Before, c.BeforeSensitive, redactionSite{
+		address:    address,
+		location:   RedactionLocationBefore,
+		reason:     reason,
+		applyRules: applyRules,
+	}, nil)
+	c.After = s.value(c.After, c.AfterSensitive, redactionSite{
+		address:    address,
+		location:   RedactionLocationAfter,
+		reason:     reason,
+		applyRules: applyRules,
+	}, nil)
 }
@@ -133,11 +133,11 @@ func TestSanitizeChange(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+			if diff := cmp.Diff(tc.expected, actual, testCmpUnexported); diff != "" {
 				t.Errorf("SanitizeChange() mismatch (-expected +actual):\n%s", diff)
 			}
 
-			if diff := cmp.Diff(changeCases()[i].old, tc.old); diff != "" {
+			if diff := cmp.Diff(changeCases()[i].old, tc.old, testCmpUnexported); diff != "" {
 				t.Errorf("SanitizeChange() altered original (-expected +actual):\n%s", diff)
 			}
 		})
@@ -0,0 +1,264 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package sanitize
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+var NilLogMessageError = errors.New("nil log message supplied")
+
+// SanitizeLogMessage sanitizes a message from Terraform's machine-readable
+// UI output, as returned by tfjson.UnmarshalLogMessage. The following
+// values are replaced with replaceWith:
+//
+// * The statements about expression values in diagnostic snippets, which
+// can contain values rendered by Terraform.
+//
+// * The values of outputs marked as sensitive in "outputs" messages.
+//
+// * Any text within the message matching one of the supplied patterns,
+// including the human-readable message, diagnostic summaries and detail,
+// and values within output and query messages.
+//
+// The message passed in is not modified.
+func SanitizeLogMessage(msg tfjson.LogMsg, replaceWith string, patterns ...*regexp.Regexp) (tfjson.LogMsg, error) {
+	if msg == nil {
+		return nil, NilLogMessageError
+	}
+
+	s := &logSanitizer{replaceWith: replaceWith, patterns: patterns}
+
+	switch m := msg.(type) {
+	case tfjson.VersionLogMessage:
+		m.Msg = s.text(m.Msg)
+		return m, nil
+	case tfjson.LogMessage:
+		m.Msg = s.text(m.Msg)
+		return m, nil
+	case tfjson.DiagnosticLogMessage:
+		m.Msg = s.text(m.Msg)
+		m.Diagnostic = *s.diagnostic(&m.Diagnostic)
+		return m, nil
+	case tfjson.InitOutputMessage:
+		m.Msg = s.text(m.Msg)
+		return m, nil
+	case tfjson.OutputsLogMessage:
+		m.Msg = s.text(m.Msg)
+		if m.Outputs != nil {
+			outputs := make(map[string]tfjson.OutputLogValue, len(m.Outputs))
+			for k, v := range m.Outputs {
+				if v.Sensitive && v.Value != nil {
+					v.Value = s.replaceWith
+				} else {
+					v.Value = s.value(v.Value)
+				}
+				outputs[k] = v
+			}
+			m.Outputs = outputs
+		}
+		return m, nil
+	case tfjson.ListStartMessage:
+		m.Msg = s.text(m.Msg)
+		m.ListStart.InputConfig = s.object(m.ListStart.InputConfig)
+		return m, nil
+	case tfjson.ListResourceFoundMessage:
+		m.Msg = s.text(m.Msg)
+		m.ListResourceFound.DisplayName = s.text(m.ListResourceFound.DisplayName)
+		m.ListResourceFound.Identity = s.object(m.ListResourceFound.Identity)
+		m.ListResourceFound.ResourceObject = s.object(m.ListResourceFound.ResourceObject)
+		m.ListResourceFound.Config = s.text(m.ListResourceFound.Config)
+		m.ListResourceFound.ImportConfig = s.text(m.ListResourceFound.ImportConfig)
+		return m, nil
+	case tfjson.ListCompleteMessage:
+		m.Msg = s.text(m.Msg)
+		return m, nil
+	case tfjson.UnknownLogMessage:
+		m.Msg = s.text(m.Msg)
+		return m, nil
+	}
+
+	return nil, fmt.Errorf("unsupported log message type %T", msg)
+}
+
+// SanitizeLogStream reads a stream of newline-delimited messages in
+// Terraform's machine-readable UI format from r, such as the output of
+// "terraform apply -json", and writes each message to w sanitized in the
+// same way as SanitizeLogMessage.
+//
+// Messages are sanitized as raw JSON, so fields not modelled by this
+// library, and messages of unknown types, are preserved. Lines which are
+// not valid JSON are written with only the supplied patterns applied.
+func SanitizeLogStream(w io.Writer, r io.Reader, replaceWith string, patterns ...*regexp.Regexp) error {
+	s := &logSanitizer{replaceWith: replaceWith, patterns: patterns}
+
+	br := bufio.NewReader(r)
+	for {
+		line, readErr := br.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+
+		if len(line) > 0 {
+			if _, err := w.Write(s.rawLine(line)); err != nil {
+				return err
+			}
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+// logSanitizer holds the settings for sanitizing log messages. Unlike
+// sanitizer, its methods return modified copies of the values they are
+// given, as log messages are not deep copied up front.
+type logSanitizer struct {
+	replaceWith string
+	patterns    []*regexp.Regexp
+}
+
+// text replaces any part of str matching one of the patterns.
+func (s *logSanitizer) text(str string) string {
+	for _, p := range s.patterns {
+		str = p.ReplaceAllLiteralString(str, s.replaceWith)
+	}
+
+	return str
+}
+
+// value returns a copy of a decoded JSON value with patterns applied to
+// every string within it.
+func (s *logSanitizer) value(v interface{}) interface{} {
+	if len(s.patterns) == 0 {
+		return v
+	}
+
+	switch x := v.(type) {
+	case string:
+		return s.text(x)
+	case []interface{}:
+		result := make([]interface{}, len(x))
+		for i, elem := range x {
+			result[i] = s.value(elem)
+		}
+		return result
+	case map[string]interface{}:
+		return s.object(x)
+	}
+
+	return v
+}
+
+// object is value for JSON objects.
+func (s *logSanitizer) object(obj map[string]interface{}) map[string]interface{} {
+	if obj == nil || len(s.patterns) == 0 {
+		return obj
+	}
+
+	result := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		result[k] = s.value(v)
+	}
+
+	return result
+}
+
+// diagnostic returns a sanitized copy of d.
+func (s *logSanitizer) diagnostic(d *tfjson.Diagnostic) *tfjson.Diagnostic {
+	result := d.DeepCopy()
+	result.Summary = s.text(result.Summary)
+	result.Detail = s.text(result.Detail)
+
+	if result.Snippet != nil {
+		result.Snippet.Code = s.text(result.Snippet.Code)
+		if result.Snippet.Context != nil {
+			context := s.text(*result.Snippet.Context)
+			result.Snippet.Context = &context
+		}
+		for i := range result.Snippet.Values {
+			result.Snippet.Values[i].Statement = s.replaceWith
+		}
+	}
+
+	return result
+}
+
+// rawLine sanitizes a single line of a machine-readable UI stream,
+// retaining its line ending.
+func (s *logSanitizer) rawLine(line []byte) []byte {
+	content := bytes.TrimRight(line, "\r\n")
+	ending := line[len(content):]
+	if len(bytes.TrimSpace(content)) == 0 {
+		return line
+	}
+
+	var msg map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(content))
+	d.UseNumber()
+	if err := d.Decode(&msg); err != nil || d.More() || msg == nil {
+		return append([]byte(s.text(string(content))), ending...)
+	}
+
+	s.rawMessage(msg)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(msg); err != nil {
+		// Every value was produced by the decoder above, so this
+		// is not expected; fall back to treating the line as text.
+		return append([]byte(s.text(string(content))), ending...)
+	}
+
+	return append(bytes.TrimRight(buf.Bytes(), "\n"), ending...)
+}
+
+// rawMessage sanitizes a decoded message in place. The type and
+// metadata fields other than "@message" are left intact so the result
+// can still be decoded.
+func (s *logSanitizer) rawMessage(msg map[string]interface{}) {
+	for k, v := range msg {
+		switch k {
+		case "type", "@level", "@module", "@timestamp":
+		default:
+			msg[k] = s.value(v)
+		}
+	}
+
+	switch t, _ := msg["type"].(string); tfjson.LogMessageType(t) {
+	case tfjson.MessageTypeDiagnostic:
+		diagnostic, _ := msg["diagnostic"].(map[string]interface{})
+		snippet, _ := diagnostic["snippet"].(map[string]interface{})
+		values, _ := snippet["values"].([]interface{})
+		for _, v := range values {
+			if value, ok := v.(map[string]interface{}); ok {
+				value["statement"] = s.replaceWith
+			}
+		}
+	case tfjson.MessageOutputs:
+		outputs, _ := msg["outputs"].(map[string]interface{})
+		for _, v := range outputs {
+			output, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if sensitive, _ := output["sensitive"].(bool); !sensitive {
+				continue
+			}
+			if _, ok := output["value"]; ok {
+				output["value"] = s.replaceWith
+			}
+		}
+	}
+}
@@ -47,6 +47,10 @@ func SanitizePlan(old *tfjson.Plan) (*tfjson.Plan, error) {
 // the BeforeSensitive and AfterSensitive in outputs are opaquely the
 // same.
 //
+// Any of these sections may be absent, as is the case for refresh-only
+// and destroy plans, in which case they are skipped. Variables can only
+// be sanitized when the root module configuration is present.
+//
 // Sensitive values are replaced with the value supplied with
 // replaceWith. A copy of the Plan is returned.
 func SanitizePlanWithValue(old *tfjson.Plan, replaceWith interface{}) (*tfjson.Plan, error) {
@@ -54,64 +58,46 @@ func SanitizePlanWithValue(old *tfjson.Plan, replaceWith interface{}) (*tfjson.P
 		return nil, NilPlanError
 	}
 
-	result, err := copyPlan(old)
-	if err != nil {
-		return nil, err
-	}
+	s := &sanitizer{replaceWith: replaceWith}
+	return s.plan(old), nil
+}
+
+// plan returns a sanitized copy of a Plan. old must not be nil.
+func (s *sanitizer) plan(old *tfjson.Plan) *tfjson.Plan {
+	result := old.DeepCopy()
 
 	// Sanitize ResourceChanges
-	for i := range result.ResourceChanges {
-		result.ResourceChanges[i].Change, err = SanitizeChange(result.ResourceChanges[i].Change, replaceWith)
-		if err != nil {
-			return nil, err
+	for _, rc := range result.ResourceChanges {
+		if rc != nil {
+			s.change(rc.Change, rc.Address, RedactionReasonSensitiveMask)
 		}
 	}
 
 	// Sanitize Variables
-	result.Variables, err = SanitizePlanVariables(result.Variables, result.Config.RootModule.Variables, replaceWith)
-	if err != nil {
-		return nil, err
+	var configVariables map[string]*tfjson.ConfigVariable
+	if result.Config != nil && result.Config.RootModule != nil {
+		configVariables = result.Config.RootModule.Variables
 	}
+	s.planVariables(result.Variables, configVariables)
 
-	// Sanitize PlannedValues
-	result.PlannedValues.RootModule, err = SanitizeStateModule(
-		result.PlannedValues.RootModule,
-		result.ResourceChanges,
-		SanitizeStateModuleChangeModeAfter,
-		replaceWith)
-	if err != nil {
-		return nil, err
-	}
+	resourceChanges := indexResourceChanges(result.ResourceChanges)
 
-	result.PlannedValues.Outputs, err = SanitizeStateOutputs(result.PlannedValues.Outputs, replaceWith)
-	if err != nil {
-		return nil, err
+	// Sanitize PlannedValues
+	if result.PlannedValues != nil {
+		s.stateModule(result.PlannedValues.RootModule, resourceChanges, SanitizeStateModuleChangeModeAfter)
+		s.stateOutputs(result.PlannedValues.Outputs, RedactionLocationPlanned)
 	}
 
 	// Sanitize PriorState
-	if result.PriorState != nil {
-		result.PriorState.Values.RootModule, err = SanitizeStateModule(
-			result.PriorState.Values.RootModule,
-			result.ResourceChanges,
-			SanitizeStateModuleChangeModeBefore,
-			replaceWith)
-		if err != nil {
-			return nil, err
-		}
-
-		result.PriorState.Values.Outputs, err = SanitizeStateOutputs(result.PriorState.Values.Outputs, replaceWith)
-		if err != nil {
-			return nil, err
-		}
+	if result.PriorState != nil && result.PriorState.Values != nil {
+		s.stateModule(result.PriorState.Values.RootModule, resourceChanges, SanitizeStateModuleChangeModeBefore)
+		s.stateOutputs(result.PriorState.Values.Outputs, RedactionLocationPrior)
 	}
 
 	// Sanitize OutputChanges
-	for k := range result.OutputChanges {
-		result.OutputChanges[k], err = SanitizeChange(result.OutputChanges[k], replaceWith)
-		if err != nil {
-			return nil, err
-		}
+	for k, c := range result.OutputChanges {
+		s.change(c, "output."+k, RedactionReasonSensitiveOutput)
 	}
 
-	return result, nil
+	return result
 }
@@ -4,18 +4,28 @@
 package sanitize
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/sebdah/goldie"
 )
 
 const testDataDir = "testdata"
 
+// testCmpUnexported allows cmp to compare the unexported fields of the types
+// of the tfjson package, such as those holding preserved unknown members.
+var testCmpUnexported = cmp.Exporter(func(t reflect.Type) bool {
+	return t.PkgPath() == reflect.TypeOf(tfjson.Plan{}).PkgPath()
+})
+
 func TestSanitizePlanGolden(t *testing.T) {
 	cases, err := goldenCases()
 	if err != nil {
@@ -44,6 +54,258 @@ func testSanitizePlanGoldenEntry(c testGoldenCase) func(t *testing.T) {
 	}
 }
 
+func TestSanitizePlan_nil(t *testing.T) {
+	_, err := SanitizePlan(nil)
+	if !errors.Is(err, NilPlanError) {
+		t.Fatalf("expected NilPlanError, got %v", err)
+	}
+}
+
+func TestSanitizePlan_preserveUnknownFields(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(testDataDir, "basic.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatal(err)
+	}
+	raw["new_section"] = map[string]interface{}{"enabled": true}
+	raw["action_invocations"] = []interface{}{
+		map[string]interface{}{
+			"address": "action.foo.bar",
+			"type":    "foo",
+			"name":    "bar",
+			"invoke_action_trigger": map[string]interface{}{
+				"new_field": "baz",
+			},
+		},
+	}
+	b, err = json.Marshal(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := new(tfjson.Plan)
+	p.PreserveUnknownFields(true)
+	if err := json.Unmarshal(b, p); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err = SanitizePlan(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw = nil
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]interface{}{"enabled": true}, raw["new_section"]); diff != "" {
+		t.Fatalf("unexpected new_section: %s", diff)
+	}
+	trigger := raw["action_invocations"].([]interface{})[0].(map[string]interface{})["invoke_action_trigger"]
+	if diff := cmp.Diff(map[string]interface{}{"new_field": "baz"}, trigger); diff != "" {
+		t.Fatalf("unexpected invoke_action_trigger: %s", diff)
+	}
+}
+
+func TestSanitizePlan_partial(t *testing.T) {
+	cases := map[string]struct {
+		plan     *tfjson.Plan
+		expected *tfjson.Plan
+	}{
+		"empty": {
+			plan:     &tfjson.Plan{},
+			expected: &tfjson.Plan{},
+		},
+		"refresh-only": {
+			plan: &tfjson.Plan{
+				Variables: map[string]*tfjson.PlanVariable{
+					"foo": {Value: "bar"},
+				},
+				ResourceDrift: []*tfjson.ResourceChange{
+					{Address: "null_resource.foo"},
+				},
+				PriorState: &tfjson.State{},
+			},
+			expected: &tfjson.Plan{
+				Variables: map[string]*tfjson.PlanVariable{
+					"foo": {Value: "bar"},
+				},
+				ResourceDrift: []*tfjson.ResourceChange{
+					{Address: "null_resource.foo"},
+				},
+				PriorState: &tfjson.State{},
+			},
+		},
+		"destroy": {
+			plan: &tfjson.Plan{
+				PlannedValues: &tfjson.StateValues{},
+				ResourceChanges: []*tfjson.ResourceChange{
+					{
+						Address: "null_resource.foo",
+						Change: &tfjson.Change{
+							Actions:         tfjson.Actions{tfjson.ActionDelete},
+							Before:          map[string]interface{}{"secret": "foo"},
+							BeforeSensitive: map[string]interface{}{"secret": true},
+						},
+					},
+				},
+				PriorState: &tfjson.State{
+					Values: &tfjson.StateValues{
+						RootModule: &tfjson.StateModule{
+							Resources: []*tfjson.StateResource{
+								{
+									Address:         "null_resource.foo",
+									AttributeValues: map[string]interface{}{"secret": "foo"},
+								},
+							},
+						},
+					},
+				},
+				Config: &tfjson.Config{},
+			},
+			expected: &tfjson.Plan{
+				PlannedValues: &tfjson.StateValues{},
+				ResourceChanges: []*tfjson.ResourceChange{
+					{
+						Address: "null_resource.foo",
+						Change: &tfjson.Change{
+							Actions:         tfjson.Actions{tfjson.ActionDelete},
+							Before:          map[string]interface{}{"secret": DefaultSensitiveValue},
+							BeforeSensitive: map[string]interface{}{"secret": true},
+						},
+					},
+				},
+				PriorState: &tfjson.State{
+					Values: &tfjson.StateValues{
+						RootModule: &tfjson.StateModule{
+							Resources: []*tfjson.StateResource{
+								{
+									Address:         "null_resource.foo",
+									AttributeValues: map[string]interface{}{"secret": DefaultSensitiveValue},
+								},
+							},
+						},
+					},
+				},
+				Config: &tfjson.Config{},
+			},
+		},
+		"nil entries": {
+			plan: &tfjson.Plan{
+				ResourceChanges: []*tfjson.ResourceChange{nil, {Address: "null_resource.foo"}},
+				OutputChanges:   map[string]*tfjson.Change{"foo": nil},
+				PlannedValues: &tfjson.StateValues{
+					Outputs: map[string]*tfjson.StateOutput{"foo": nil},
+					RootModule: &tfjson.StateModule{
+						Resources:    []*tfjson.StateResource{nil, {Address: "null_resource.foo"}},
+						ChildModules: []*tfjson.StateModule{nil},
+					},
+				},
+			},
+			expected: &tfjson.Plan{
+				ResourceChanges: []*tfjson.ResourceChange{nil, {Address: "null_resource.foo"}},
+				OutputChanges:   map[string]*tfjson.Change{"foo": nil},
+				PlannedValues: &tfjson.StateValues{
+					Outputs: map[string]*tfjson.StateOutput{"foo": nil},
+					RootModule: &tfjson.StateModule{
+						Resources:    []*tfjson.StateResource{nil, {Address: "null_resource.foo"}},
+						ChildModules: []*tfjson.StateModule{nil},
+					},
+				},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		tc := tc
+		t.Run(tn, func(t *testing.T) {
+			actual, err := SanitizePlan(tc.plan)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, actual, testCmpUnexported); diff != "" {
+				t.Errorf("SanitizePlan() mismatch (-expected +actual):\n%s", diff)
+			}
+		})
+	}
+}
+
+// rawPlan allows a Plan to be decoded without its format version being
+// validated, so that the fuzzer can explore arbitrary plan shapes.
+type rawPlan tfjson.Plan
+
+func FuzzSanitizePlan(f *testing.F) {
+	cases, err := goldenCases()
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, c := range cases {
+		f.Add(c.InputData)
+	}
+
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`{"resource_changes":[null,{"change":null}],"output_changes":{"a":null}}`))
+	f.Add([]byte(`{"planned_values":{"outputs":{"a":null}},"prior_state":{"format_version":"1.0"}}`))
+	f.Add([]byte(`{"planned_values":{"root_module":{"resources":[null,{"address":"a.b"}],"child_modules":[null]}}}`))
+	f.Add([]byte(`{"resource_changes":[{"address":"a.b","change":{"before_sensitive":true,"after_sensitive":[true]}}],"prior_state":{"format_version":"1.0","values":{"root_module":{"resources":[{"address":"a.b","values":{"x":1}}]}}}}`))
+	f.Add([]byte(`{"variables":{"a":null,"b":{"value":1}},"configuration":{"root_module":{"variables":{"b":{"sensitive":true}}}}}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p := new(tfjson.Plan)
+		if err := json.Unmarshal(data, (*rawPlan)(p)); err != nil {
+			t.Skip()
+		}
+
+		before, err := json.Marshal(p)
+		if err != nil {
+			t.Skip()
+		}
+
+		if _, err := SanitizePlan(p); err != nil {
+			t.Fatal(err)
+		}
+
+		after, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(before, after) {
+			t.Fatalf("SanitizePlan() altered original:\nbefore: %s\nafter: %s", before, after)
+		}
+	})
+}
+
+func BenchmarkSanitizePlan(b *testing.B) {
+	data, err := os.ReadFile(filepath.Join(testDataDir, "basic.json"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	p := new(tfjson.Plan)
+	if err := json.Unmarshal(data, p); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := SanitizePlan(p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 type testGoldenCase struct {
 	FileName  string
 	InputData []byte
@@ -20,30 +20,27 @@ func SanitizePlanVariables(
 ) (map[string]*tfjson.PlanVariable, error) {
 	result := make(map[string]*tfjson.PlanVariable, len(old))
 	for k := range old {
-		v, err := sanitizeVariable(old[k], configs[k], replaceWith)
-		if err != nil {
-			return nil, err
-		}
-
-		result[k] = v
+		result[k] = old[k].DeepCopy()
 	}
 
+	s := &sanitizer{replaceWith: replaceWith}
+	s.planVariables(result, configs)
+
 	return result, nil
 }
 
-func sanitizeVariable(
-	old *tfjson.PlanVariable,
-	config *tfjson.ConfigVariable,
-	replaceWith interface{},
-) (*tfjson.PlanVariable, error) {
-	result, err := copyPlanVariable(old)
-	if err != nil {
-		return nil, err
-	}
-
-	if config != nil && config.Sensitive {
-		result.Value = replaceWith
+// planVariables sanitizes a map of PlanVariable in place.
+func (s *sanitizer) planVariables(
+	vars map[string]*tfjson.PlanVariable,
+	configs map[string]*tfjson.ConfigVariable,
+) {
+	for k, v := range vars {
+		if config := configs[k]; v != nil && config != nil && config.Sensitive {
+			v.Value = s.replaceWith
+			s.record(redactionSite{
+				address:  "var." + k,
+				location: RedactionLocationVariable,
+			}, nil, RedactionReasonSensitiveVariable, "")
+		}
 	}
-
-	return result, nil
 }
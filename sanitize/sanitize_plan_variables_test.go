@@ -58,11 +58,11 @@ func TestSanitizePlanVariables(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+			if diff := cmp.Diff(tc.expected, actual, testCmpUnexported); diff != "" {
 				t.Errorf("SanitizePlanVariables() mismatch (-expected +actual):\n%s", diff)
 			}
 
-			if diff := cmp.Diff(variablesCases()[i].old, tc.old); diff != "" {
+			if diff := cmp.Diff(variablesCases()[i].old, tc.old, testCmpUnexported); diff != "" {
 				t.Errorf("SanitizePlanVariables() altered original (-expected +actual):\n%s", diff)
 			}
 		})
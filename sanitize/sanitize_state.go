@@ -16,6 +16,17 @@ const (
 	SanitizeStateModuleChangeModeAfter  SanitizeStateModuleChangeMode = "after_sensitive"
 )
 
+// InvalidChangeModeError is returned when a SanitizeStateModuleChangeMode
+// other than SanitizeStateModuleChangeModeBefore or
+// SanitizeStateModuleChangeModeAfter is supplied.
+type InvalidChangeModeError struct {
+	Mode SanitizeStateModuleChangeMode
+}
+
+func (e *InvalidChangeModeError) Error() string {
+	return fmt.Sprintf("invalid change mode %q", e.Mode)
+}
+
 // SanitizeStateModule traverses a StateModule, consulting the
 // supplied ResourceChange set for resources to determine whether or
 // not particular values should be obfuscated.
@@ -28,89 +39,129 @@ const (
 // * SanitizeStateModuleChangeModeAfter for after_sensitive
 //
 // Sensitive values are replaced with the supplied replaceWith value.
-// A new state module tree is issued.
+// A new state module tree is issued. A nil module results in a nil
+// module being returned, and an unknown mode results in an
+// *InvalidChangeModeError.
 func SanitizeStateModule(
 	old *tfjson.StateModule,
 	resourceChanges []*tfjson.ResourceChange,
 	mode SanitizeStateModuleChangeMode,
 	replaceWith interface{},
 ) (*tfjson.StateModule, error) {
-	result := &tfjson.StateModule{
-		Resources:    make([]*tfjson.StateResource, len(old.Resources)),
-		Address:      old.Address,
-		ChildModules: make([]*tfjson.StateModule, len(old.ChildModules)),
+	if err := validateChangeMode(mode); err != nil {
+		return nil, err
 	}
 
-	for i := range old.Resources {
-		var err error
-		result.Resources[i], err = sanitizeStateResource(
-			old.Resources[i],
-			findResourceChange(resourceChanges, old.Resources[i].Address),
-			mode,
-			replaceWith,
-		)
-		if err != nil {
-			return nil, err
-		}
+	result := old.DeepCopy()
+
+	s := &sanitizer{replaceWith: replaceWith}
+	s.stateModule(result, indexResourceChanges(resourceChanges), mode)
+
+	return result, nil
+}
+
+func validateChangeMode(mode SanitizeStateModuleChangeMode) error {
+	switch mode {
+	case SanitizeStateModuleChangeModeBefore, SanitizeStateModuleChangeModeAfter:
+		return nil
 	}
 
-	for i := range old.ChildModules {
-		var err error
-		result.ChildModules[i], err = SanitizeStateModule(
-			old.ChildModules[i],
-			resourceChanges,
-			mode,
-			replaceWith,
-		)
-		if err != nil {
-			return nil, err
+	return &InvalidChangeModeError{Mode: mode}
+}
+
+// stateModule sanitizes a StateModule tree in place. The mode must
+// already have been validated.
+func (s *sanitizer) stateModule(
+	m *tfjson.StateModule,
+	resourceChanges map[string]*tfjson.ResourceChange,
+	mode SanitizeStateModuleChangeMode,
+) {
+	if m == nil {
+		return
+	}
+
+	for _, r := range m.Resources {
+		if r == nil {
+			continue
 		}
+
+		s.stateResource(r, resourceChanges[r.Address], mode)
 	}
 
-	return result, nil
+	for _, c := range m.ChildModules {
+		s.stateModule(c, resourceChanges, mode)
+	}
 }
 
-func sanitizeStateResource(
-	old *tfjson.StateResource,
+// stateResource sanitizes a StateResource in place.
+func (s *sanitizer) stateResource(
+	r *tfjson.StateResource,
 	rc *tfjson.ResourceChange,
 	mode SanitizeStateModuleChangeMode,
-	replaceWith interface{},
-) (*tfjson.StateResource, error) {
-	result, err := copyStateResource(old)
-	if err != nil {
-		return nil, err
-	}
-
-	if rc == nil {
-		return result, nil
+) {
+	site := redactionSite{
+		address:    r.Address,
+		reason:     RedactionReasonSensitiveMask,
+		applyRules: true,
 	}
 
 	var sensitive interface{}
 	switch mode {
 	case SanitizeStateModuleChangeModeBefore:
-		sensitive = rc.Change.BeforeSensitive
+		site.location = RedactionLocationPrior
+		if rc != nil && rc.Change != nil {
+			sensitive = rc.Change.BeforeSensitive
+		}
 
 	case SanitizeStateModuleChangeModeAfter:
-		sensitive = rc.Change.AfterSensitive
+		site.location = RedactionLocationPlanned
+		if rc != nil && rc.Change != nil {
+			sensitive = rc.Change.AfterSensitive
+		}
+	}
 
-	default:
-		panic(fmt.Sprintf("invalid change mode %q", mode))
+	if r.AttributeValues == nil || (sensitive == nil && len(s.rules) == 0) {
+		return
 	}
 
-	// We can re-use sanitizeChangeValue here to do the sanitization.
-	result.AttributeValues = sanitizeChangeValue(result.AttributeValues, sensitive, replaceWith).(map[string]interface{})
-	return result, nil
+	// If the entire object is sensitive, every attribute is.
+	if all, ok := sensitive.(bool); ok && all {
+		for k := range r.AttributeValues {
+			r.AttributeValues[k] = s.replaceWith
+		}
+		s.record(site, nil, site.reason, "")
+
+		return
+	}
+
+	// A rule matching the entire object redacts every attribute, as the
+	// attribute values must remain an object.
+	switch values := s.value(r.AttributeValues, sensitive, site, nil).(type) {
+	case map[string]interface{}:
+		r.AttributeValues = values
+	default:
+		for k := range r.AttributeValues {
+			r.AttributeValues[k] = s.replaceWith
+		}
+	}
 }
 
-func findResourceChange(resourceChanges []*tfjson.ResourceChange, addr string) *tfjson.ResourceChange {
-	// Linear search here, unfortunately :P
+// indexResourceChanges indexes the supplied ResourceChange set by address.
+// When more than one change shares an address, such as for deposed
+// objects, the first one wins.
+func indexResourceChanges(resourceChanges []*tfjson.ResourceChange) map[string]*tfjson.ResourceChange {
+	result := make(map[string]*tfjson.ResourceChange, len(resourceChanges))
 	for _, rc := range resourceChanges {
-		if rc.Address == addr {
-			return rc
+		if rc == nil {
+			continue
+		}
+
+		if _, ok := result[rc.Address]; !ok {
+			result[rc.Address] = rc
 		}
 	}
 
-	return nil
+	return result
 }
 
 // SanitizeStateOutputs scans the supplied map of StateOutputs and
@@ -119,16 +170,31 @@ func findResourceChange(resourceChanges []*tfjson.ResourceChange, addr string) *
 //
 // A new copy of StateOutputs is returned.
 func SanitizeStateOutputs(old map[string]*tfjson.StateOutput, replaceWith interface{}) (map[string]*tfjson.StateOutput, error) {
-	result, err := copyStateOutputs(old)
-	if err != nil {
-		return nil, err
+	if old == nil {
+		return nil, nil
 	}
 
-	for k := range result {
-		if result[k].Sensitive {
-			result[k].Value = replaceWith
-		}
+	result := make(map[string]*tfjson.StateOutput, len(old))
+	for k := range old {
+		result[k] = old[k].DeepCopy()
 	}
 
+	s := &sanitizer{replaceWith: replaceWith}
+	s.stateOutputs(result, "")
+
 	return result, nil
 }
+
+// stateOutputs sanitizes a map of StateOutputs in place. location is
+// used when reporting redactions.
+func (s *sanitizer) stateOutputs(outputs map[string]*tfjson.StateOutput, location RedactionLocation) {
+	for k, o := range outputs {
+		if o != nil && o.Sensitive {
+			o.Value = s.replaceWith
+			s.record(redactionSite{
+				address:  "output." + k,
+				location: location,
+			}, nil, RedactionReasonSensitiveOutput, "")
+		}
+	}
+}
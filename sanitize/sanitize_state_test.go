@@ -4,6 +4,7 @@
 package sanitize
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -197,17 +198,86 @@ func TestSanitizeStateModule(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+			if diff := cmp.Diff(tc.expected, actual, testCmpUnexported); diff != "" {
 				t.Errorf("SanitizeStateModule() mismatch (-expected +actual):\n%s", diff)
 			}
 
-			if diff := cmp.Diff(stateCases()[i].old, tc.old); diff != "" {
+			if diff := cmp.Diff(stateCases()[i].old, tc.old, testCmpUnexported); diff != "" {
 				t.Errorf("SanitizeStateModule() altered original (-expected +actual):\n%s", diff)
 			}
 		})
 	}
 }
 
+func TestSanitizeStateModule_sensitiveObject(t *testing.T) {
+	old := &tfjson.StateModule{
+		Resources: []*tfjson.StateResource{
+			{
+				Address: "null_resource.foo",
+				AttributeValues: map[string]interface{}{
+					"foo": "bar",
+					"baz": "qux",
+				},
+			},
+		},
+	}
+	resourceChanges := []*tfjson.ResourceChange{
+		{
+			Address: "null_resource.foo",
+			Change: &tfjson.Change{
+				AfterSensitive: true,
+			},
+		},
+		{
+			Address: "null_resource.bar",
+		},
+	}
+	expected := &tfjson.StateModule{
+		Resources: []*tfjson.StateResource{
+			{
+				Address: "null_resource.foo",
+				AttributeValues: map[string]interface{}{
+					"foo": DefaultSensitiveValue,
+					"baz": DefaultSensitiveValue,
+				},
+			},
+		},
+	}
+
+	actual, err := SanitizeStateModule(old, resourceChanges, SanitizeStateModuleChangeModeAfter, DefaultSensitiveValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(expected, actual, testCmpUnexported); diff != "" {
+		t.Errorf("SanitizeStateModule() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestSanitizeStateModule_invalidMode(t *testing.T) {
+	_, err := SanitizeStateModule(&tfjson.StateModule{}, nil, "bogus", DefaultSensitiveValue)
+
+	var modeErr *InvalidChangeModeError
+	if !errors.As(err, &modeErr) {
+		t.Fatalf("expected InvalidChangeModeError, got %v", err)
+	}
+
+	if modeErr.Mode != "bogus" {
+		t.Fatalf("unexpected mode %q", modeErr.Mode)
+	}
+}
+
+func TestSanitizeStateModule_nil(t *testing.T) {
+	actual, err := SanitizeStateModule(nil, nil, SanitizeStateModuleChangeModeBefore, DefaultSensitiveValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual != nil {
+		t.Fatalf("expected nil module, got %#v", actual)
+	}
+}
+
 type testOutputCase struct {
 	name     string
 	old      map[string]*tfjson.StateOutput
@@ -249,11 +319,11 @@ func TestSanitizeStateOutputs(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			if diff := cmp.Diff(tc.expected, actual, ctydebug.CmpOptions); diff != "" {
+			if diff := cmp.Diff(tc.expected, actual, ctydebug.CmpOptions, testCmpUnexported); diff != "" {
 				t.Errorf("SanitizeStateOutputs() mismatch (-expected +actual):\n%s", diff)
 			}
 
-			if diff := cmp.Diff(outputCases()[i].old, tc.old, ctydebug.CmpOptions); diff != "" {
+			if diff := cmp.Diff(outputCases()[i].old, tc.old, ctydebug.CmpOptions, testCmpUnexported); diff != "" {
 				t.Errorf("SanitizeStateOutputs() altered original (-expected +actual):\n%s", diff)
 			}
 		})
@@ -0,0 +1,127 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package sanitize
+
+// sanitizer holds the settings for a single sanitization pass. All of
+// its methods modify the values they are given in place, so callers are
+// responsible for copying anything that must not change.
+type sanitizer struct {
+	// replaceWith is the value substituted for every redacted value.
+	replaceWith interface{}
+
+	// rules are custom rules applied to resource attribute values in
+	// addition to the sensitivity data supplied by Terraform.
+	rules []Rule
+
+	// report, when non-nil, has every redaction appended to it.
+	report *Report
+}
+
+// redactionSite describes where a value being sanitized lives, for the
+// purposes of reporting.
+type redactionSite struct {
+	address  string
+	location RedactionLocation
+
+	// reason is the reason recorded when a sensitivity marker causes a
+	// value to be redacted.
+	reason RedactionReason
+
+	// applyRules is true if custom rules apply at this site.
+	applyRules bool
+}
+
+// tracksPaths is true if attribute paths need to be tracked while
+// walking values, either to report them or to pass them to rules.
+func (s *sanitizer) tracksPaths() bool {
+	return s.report != nil || len(s.rules) > 0
+}
+
+// value traverses old and replaces the values at the particular
+// locations marked by sensitive with the replacement value, as well as
+// any values matched by a custom rule when the site allows it.
+//
+// Objects and arrays are sanitized in place; the returned value must be
+// used in place of old in case old itself was redacted.
+func (s *sanitizer) value(old, sensitive interface{}, site redactionSite, path []interface{}) interface{} {
+	if shouldFilter, ok := sensitive.(bool); ok && shouldFilter {
+		s.record(site, path, site.reason, "")
+		return s.replaceWith
+	}
+
+	// Null values, such as the Before value of a create, have nothing to
+	// redact, so are not passed to the rules.
+	rules := site.applyRules && len(s.rules) > 0 && old != nil
+	if rules {
+		for _, rule := range s.rules {
+			if rule.Match != nil && rule.Match(site.address, path, old) {
+				s.record(site, path, RedactionReasonCustomRule, rule.Name)
+				return s.replaceWith
+			}
+		}
+	}
+
+	// Only expect deep types that we would normally see in JSON, so
+	// arrays and objects. Without custom rules, only the elements
+	// marked in sensitive need to be visited.
+	switch x := old.(type) {
+	case []interface{}:
+		filterSlice, _ := sensitive.([]interface{})
+		n := len(filterSlice)
+		if rules || n > len(x) {
+			n = len(x)
+		}
+
+		for i := 0; i < n; i++ {
+			var filter interface{}
+			if i < len(filterSlice) {
+				filter = filterSlice[i]
+			}
+
+			x[i] = s.value(x[i], filter, site, s.childPath(path, i))
+		}
+	case map[string]interface{}:
+		filterMap, _ := sensitive.(map[string]interface{})
+		if rules {
+			for k, value := range x {
+				x[k] = s.value(value, filterMap[k], site, s.childPath(path, k))
+			}
+		} else {
+			for filterKey, filter := range filterMap {
+				if value, ok := x[filterKey]; ok {
+					x[filterKey] = s.value(value, filter, site, s.childPath(path, filterKey))
+				}
+			}
+		}
+	}
+
+	return old
+}
+
+// childPath returns path extended by step, or nil when paths are not
+// being tracked. The result never shares its backing array with path.
+func (s *sanitizer) childPath(path []interface{}, step interface{}) []interface{} {
+	if !s.tracksPaths() {
+		return nil
+	}
+
+	result := make([]interface{}, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, step)
+}
+
+// record appends a redaction to the report, if there is one.
+func (s *sanitizer) record(site redactionSite, path []interface{}, reason RedactionReason, rule string) {
+	if s.report == nil {
+		return
+	}
+
+	s.report.Redactions = append(s.report.Redactions, Redaction{
+		Address:  site.address,
+		Location: site.location,
+		Path:     path,
+		Reason:   reason,
+		Rule:     rule,
+	})
+}
@@ -19,6 +19,22 @@ var ProviderSchemasFormatVersionConstraints = ">= 0.1, < 2.0"
 // ProviderSchemas represents the schemas of all providers and
 // resources in use by the configuration.
 type ProviderSchemas struct {
+	// decodeOptions are the options used when decoding the provider
+	// schemas. Set them using ProviderSchemas.SetDecodeOptions, or
+	// individually using ProviderSchemas.UseJSONNumber,
+	// ProviderSchemas.CollectUnknownFields and
+	// ProviderSchemas.PreserveUnknownFields.
+	decodeOptions DecodeOptions
+
+	// unknownFields are the paths recorded when decoding with
+	// DecodeOptions.CollectUnknownFields set, returned by
+	// ProviderSchemas.UnknownFields.
+	unknownFields []string
+
+	// unknownMembers are the members retained when decoding with
+	// DecodeOptions.PreserveUnknownFields set.
+	unknownMembers
+
 	// The version of the plan format. This should always match one of
 	// ProviderSchemasFormatVersions in this package, or else
 	// an unmarshal will be unstable.
@@ -31,6 +47,38 @@ type ProviderSchemas struct {
 	Schemas map[string]*ProviderSchema `json:"provider_schemas,omitempty"`
 }
 
+// SetDecodeOptions sets the options used when decoding the
+// ProviderSchemas, replacing any set previously.
+func (p *ProviderSchemas) SetDecodeOptions(opts DecodeOptions) {
+	p.decodeOptions = opts
+}
+
+// UseJSONNumber controls whether the ProviderSchemas will be decoded using
+// the json.Number behavior or the float64 behavior for values of arbitrary
+// type.
+func (p *ProviderSchemas) UseJSONNumber(b bool) {
+	p.decodeOptions.UseJSONNumber = b
+}
+
+// CollectUnknownFields sets DecodeOptions.CollectUnknownFields for
+// decoding the ProviderSchemas. The paths found are returned by UnknownFields.
+func (p *ProviderSchemas) CollectUnknownFields(b bool) {
+	p.decodeOptions.CollectUnknownFields = b
+}
+
+// UnknownFields returns the paths of the unknown fields collected when
+// decoding the ProviderSchemas, such as "provider_schemas["aws"].new_field".
+func (p *ProviderSchemas) UnknownFields() []string {
+	return p.unknownFields
+}
+
+// PreserveUnknownFields sets DecodeOptions.PreserveUnknownFields for
+// decoding the ProviderSchemas, so that unknown fields are emitted again when
+// it is encoded.
+func (p *ProviderSchemas) PreserveUnknownFields(b bool) {
+	p.decodeOptions.PreserveUnknownFields = b
+}
+
 // Validate checks to ensure that ProviderSchemas is present, and the
 // version matches the version supported by this library.
 func (p *ProviderSchemas) Validate() error {
@@ -68,20 +116,31 @@ func (p *ProviderSchemas) UnmarshalJSON(b []byte) error {
 	type rawSchemas ProviderSchemas
 	var schemas rawSchemas
 
-	err := json.Unmarshal(b, &schemas)
+	opts := p.decodeOptions
+	unknownFields, err := opts.Unmarshal(b, &schemas)
 	if err != nil {
 		return err
 	}
 
 	*p = *(*ProviderSchemas)(&schemas)
+	p.decodeOptions = opts
+	p.unknownFields = unknownFields
+
+	return opts.validate(p)
+}
 
-	return p.Validate()
+// MarshalJSON implements json.Marshaler for ProviderSchemas.
+func (p *ProviderSchemas) MarshalJSON() ([]byte, error) {
+	type rawProviderSchemas ProviderSchemas
+	return marshalUnknownMembers((*rawProviderSchemas)(p))
 }
 
 // ProviderSchema is the JSON representation of the schema of an
 // entire provider, including the provider configuration and any
 // resources and data sources included with the provider.
 type ProviderSchema struct {
+	unknownMembers
+
 	// The schema for the provider's configuration.
 	ConfigSchema *Schema `json:"provider,omitempty"`
 
@@ -110,9 +169,17 @@ type ProviderSchema struct {
 	StateStoreSchemas map[string]*Schema `json:"state_store_schemas,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for ProviderSchema.
+func (p *ProviderSchema) MarshalJSON() ([]byte, error) {
+	type rawProviderSchema ProviderSchema
+	return marshalUnknownMembers((*rawProviderSchema)(p))
+}
+
 // Schema is the JSON representation of a particular schema
 // (provider configuration, resources, data sources).
 type Schema struct {
+	unknownMembers
+
 	// The version of the particular resource schema.
 	Version uint64 `json:"version"`
 
@@ -120,6 +187,12 @@ type Schema struct {
 	Block *SchemaBlock `json:"block,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for Schema.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type rawSchema Schema
+	return marshalUnknownMembers((*rawSchema)(s))
+}
+
 // SchemaDescriptionKind describes the format type for a particular description's field.
 type SchemaDescriptionKind string
 
@@ -134,6 +207,8 @@ const (
 
 // SchemaBlock represents a nested block within a particular schema.
 type SchemaBlock struct {
+	unknownMembers
+
 	// The attributes defined at the particular level of this block.
 	Attributes map[string]*SchemaAttribute `json:"attributes,omitempty"`
 
@@ -149,6 +224,12 @@ type SchemaBlock struct {
 	Deprecated bool `json:"deprecated,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for SchemaBlock.
+func (b *SchemaBlock) MarshalJSON() ([]byte, error) {
+	type rawSchemaBlock SchemaBlock
+	return marshalUnknownMembers((*rawSchemaBlock)(b))
+}
+
 // SchemaNestingMode is the nesting mode for a particular nested
 // schema block.
 type SchemaNestingMode string
@@ -195,6 +276,8 @@ const (
 
 // SchemaBlockType describes a nested block within a schema.
 type SchemaBlockType struct {
+	unknownMembers
+
 	// The nesting mode for this block.
 	NestingMode SchemaNestingMode `json:"nesting_mode,omitempty"`
 
@@ -211,8 +294,16 @@ type SchemaBlockType struct {
 	MaxItems uint64 `json:"max_items,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for SchemaBlockType.
+func (b *SchemaBlockType) MarshalJSON() ([]byte, error) {
+	type rawSchemaBlockType SchemaBlockType
+	return marshalUnknownMembers((*rawSchemaBlockType)(b))
+}
+
 // SchemaAttribute describes an attribute within a schema block.
 type SchemaAttribute struct {
+	unknownMembers
+
 	// The attribute type
 	// Either AttributeType or AttributeNestedType is set, never both.
 	AttributeType cty.Type `json:"type,omitempty"`
@@ -260,6 +351,8 @@ type SchemaAttribute struct {
 // which the default Go marshaller cannot ignore because it's a
 // not nil-able struct.
 type jsonSchemaAttribute struct {
+	unknownMembers
+
 	AttributeType       json.RawMessage            `json:"type,omitempty"`
 	AttributeNestedType *SchemaNestedAttributeType `json:"nested_type,omitempty"`
 	Description         string                     `json:"description,omitempty"`
@@ -274,6 +367,7 @@ type jsonSchemaAttribute struct {
 
 func (as *SchemaAttribute) MarshalJSON() ([]byte, error) {
 	jsonSa := &jsonSchemaAttribute{
+		unknownMembers:      as.unknownMembers,
 		AttributeNestedType: as.AttributeNestedType,
 		Description:         as.Description,
 		DescriptionKind:     as.DescriptionKind,
@@ -288,7 +382,7 @@ func (as *SchemaAttribute) MarshalJSON() ([]byte, error) {
 		attrTy, _ := as.AttributeType.MarshalJSON()
 		jsonSa.AttributeType = attrTy
 	}
-	return json.Marshal(jsonSa)
+	return marshalUnknownMembers(jsonSa)
 }
 
 // SchemaNestedAttributeType describes a nested attribute
@@ -296,6 +390,8 @@ func (as *SchemaAttribute) MarshalJSON() ([]byte, error) {
 // cty.List(cty.Object(...)) etc. but this allows tracking additional
 // metadata which can help interpreting or validating the data.
 type SchemaNestedAttributeType struct {
+	unknownMembers
+
 	// A map of nested attributes
 	Attributes map[string]*SchemaAttribute `json:"attributes,omitempty"`
 
@@ -311,9 +407,17 @@ type SchemaNestedAttributeType struct {
 	MaxItems uint64 `json:"max_items,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for SchemaNestedAttributeType.
+func (t *SchemaNestedAttributeType) MarshalJSON() ([]byte, error) {
+	type rawSchemaNestedAttributeType SchemaNestedAttributeType
+	return marshalUnknownMembers((*rawSchemaNestedAttributeType)(t))
+}
+
 // IdentitySchema is the JSON representation of a particular
 // resource identity schema
 type IdentitySchema struct {
+	unknownMembers
+
 	// The version of the particular resource identity schema.
 	Version uint64 `json:"version"`
 
@@ -321,8 +425,16 @@ type IdentitySchema struct {
 	Attributes map[string]*IdentityAttribute `json:"attributes,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for IdentitySchema.
+func (s *IdentitySchema) MarshalJSON() ([]byte, error) {
+	type rawIdentitySchema IdentitySchema
+	return marshalUnknownMembers((*rawIdentitySchema)(s))
+}
+
 // IdentityAttribute describes an identity attribute
 type IdentityAttribute struct {
+	unknownMembers
+
 	// The identity attribute type
 	IdentityType cty.Type `json:"type,omitempty"`
 
@@ -339,8 +451,22 @@ type IdentityAttribute struct {
 	OptionalForImport bool `json:"optional_for_import,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for IdentityAttribute.
+func (a *IdentityAttribute) MarshalJSON() ([]byte, error) {
+	type rawIdentityAttribute IdentityAttribute
+	return marshalUnknownMembers((*rawIdentityAttribute)(a))
+}
+
 // ActionSchema is the JSON representation of an action schema
 type ActionSchema struct {
+	unknownMembers
+
 	// The root-level block of configuration values.
 	Block *SchemaBlock `json:"block,omitempty"`
 }
+
+// MarshalJSON implements json.Marshaler for ActionSchema.
+func (s *ActionSchema) MarshalJSON() ([]byte, error) {
+	type rawActionSchema ActionSchema
+	return marshalUnknownMembers((*rawActionSchema)(s))
+}
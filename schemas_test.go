@@ -119,7 +119,7 @@ func TestProviderSchemas_action(t *testing.T) {
 	}
 
 	gotAction := schemas.Schemas["registry.terraform.io/hashicorp/external"].ActionSchemas["external"]
-	if diff := cmp.Diff(gotAction, expectedAction, cmpopts.EquateComparable(cty.Type{})); diff != "" {
+	if diff := cmp.Diff(gotAction, expectedAction, cmpopts.EquateComparable(cty.Type{}), testCmpUnexported); diff != "" {
 		t.Errorf("Unexpected diff (+wanted, -got): %s", diff)
 		return
 	}
@@ -164,7 +164,7 @@ func TestProviderSchemas_stateStores(t *testing.T) {
 		)
 	}
 
-	if diff := cmp.Diff(gotStoreSchema, expectedStoreSchema, cmpopts.EquateComparable(cty.Type{})); diff != "" {
+	if diff := cmp.Diff(gotStoreSchema, expectedStoreSchema, cmpopts.EquateComparable(cty.Type{}), testCmpUnexported); diff != "" {
 		t.Errorf("Unexpected diff (+wanted, -got): %s", diff)
 		return
 	}
@@ -19,11 +19,20 @@ var StateFormatVersionConstraints = ">= 0.1, < 2.0"
 
 // State is the top-level representation of a Terraform state.
 type State struct {
-	// useJSONNumber opts into the behavior of calling
-	// json.Decoder.UseNumber prior to decoding the state, which turns
-	// numbers into json.Numbers instead of float64s. Set it using
-	// State.UseJSONNumber.
-	useJSONNumber bool
+	// decodeOptions are the options used when decoding the state. Set them
+	// using State.SetDecodeOptions, or individually using
+	// State.UseJSONNumber, State.CollectUnknownFields and
+	// State.PreserveUnknownFields.
+	decodeOptions DecodeOptions
+
+	// unknownFields are the paths recorded when decoding with
+	// DecodeOptions.CollectUnknownFields set, returned by
+	// State.UnknownFields.
+	unknownFields []string
+
+	// unknownMembers are the members retained when decoding with
+	// DecodeOptions.PreserveUnknownFields set.
+	unknownMembers
 
 	// The version of the state format. This should always match the
 	// StateFormatVersion constant in this package, or else am
@@ -41,12 +50,38 @@ type State struct {
 	Checks []CheckResultStatic `json:"checks,omitempty"`
 }
 
+// SetDecodeOptions sets the options used when decoding the State,
+// replacing any set previously.
+func (s *State) SetDecodeOptions(opts DecodeOptions) {
+	s.decodeOptions = opts
+}
+
 // UseJSONNumber controls whether the State will be decoded using the
 // json.Number behavior or the float64 behavior. When b is true, the State will
-// represent numbers in StateOutputs as json.Numbers. When b is false, the
-// State will represent numbers in StateOutputs as float64s.
+// represent numbers in values of arbitrary type, such as StateOutputs and
+// resource attributes, as json.Numbers. When b is false, the State will
+// represent them as float64s.
 func (s *State) UseJSONNumber(b bool) {
-	s.useJSONNumber = b
+	s.decodeOptions.UseJSONNumber = b
+}
+
+// CollectUnknownFields sets DecodeOptions.CollectUnknownFields for
+// decoding the State. The paths found are returned by UnknownFields.
+func (s *State) CollectUnknownFields(b bool) {
+	s.decodeOptions.CollectUnknownFields = b
+}
+
+// UnknownFields returns the paths of the unknown fields collected when
+// decoding the State, such as "values.root_module.resources[0].new_field".
+func (s *State) UnknownFields() []string {
+	return s.unknownFields
+}
+
+// PreserveUnknownFields sets DecodeOptions.PreserveUnknownFields for
+// decoding the State, so that unknown fields are emitted again when
+// it is encoded.
+func (s *State) PreserveUnknownFields(b bool) {
+	s.decodeOptions.PreserveUnknownFields = b
 }
 
 // Validate checks to ensure that the state is present, and the
@@ -86,23 +121,46 @@ func (s *State) UnmarshalJSON(b []byte) error {
 	type rawState State
 	var state rawState
 
-	dec := json.NewDecoder(bytes.NewReader(b))
-	if s.useJSONNumber {
-		dec.UseNumber()
-	}
-	err := dec.Decode(&state)
+	opts := s.decodeOptions
+	unknownFields, err := opts.Unmarshal(b, &state)
 	if err != nil {
 		return err
 	}
 
 	*s = *(*State)(&state)
+	s.decodeOptions = opts
+	s.unknownFields = unknownFields
 
-	return s.Validate()
+	return opts.validate(s)
+}
+
+// unmarshalJSONOptions implements optionsUnmarshaler for State, which is
+// nested within a Plan as its prior state.
+func (s *State) unmarshalJSONOptions(d *jsonDecoder, b []byte) error {
+	type rawState State
+	var state rawState
+
+	if err := d.decode(b, &state); err != nil {
+		return err
+	}
+
+	*s = *(*State)(&state)
+	s.decodeOptions = d.opts
+
+	return d.opts.validate(s)
+}
+
+// MarshalJSON implements json.Marshaler for State.
+func (s *State) MarshalJSON() ([]byte, error) {
+	type rawState State
+	return marshalUnknownMembers((*rawState)(s))
 }
 
 // StateValues is the common representation of resolved values for both the
 // prior state (which is always complete) and the planned new state.
 type StateValues struct {
+	unknownMembers
+
 	// The Outputs for this common state representation.
 	Outputs map[string]*StateOutput `json:"outputs,omitempty"`
 
@@ -110,9 +168,17 @@ type StateValues struct {
 	RootModule *StateModule `json:"root_module,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for StateValues.
+func (v *StateValues) MarshalJSON() ([]byte, error) {
+	type rawStateValues StateValues
+	return marshalUnknownMembers((*rawStateValues)(v))
+}
+
 // StateModule is the representation of a module in the common state
 // representation. This can be the root module or a child module.
 type StateModule struct {
+	unknownMembers
+
 	// All resources or data sources within this module.
 	Resources []*StateResource `json:"resources,omitempty"`
 
@@ -123,9 +189,17 @@ type StateModule struct {
 	ChildModules []*StateModule `json:"child_modules,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for StateModule.
+func (m *StateModule) MarshalJSON() ([]byte, error) {
+	type rawStateModule StateModule
+	return marshalUnknownMembers((*rawStateModule)(m))
+}
+
 // StateResource is the representation of a resource in the common
 // state representation.
 type StateResource struct {
+	unknownMembers
+
 	// The absolute resource address.
 	Address string `json:"address,omitempty"`
 
@@ -187,9 +261,17 @@ type StateResource struct {
 	IdentityValues map[string]interface{} `json:"identity,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for StateResource.
+func (r *StateResource) MarshalJSON() ([]byte, error) {
+	type rawStateResource StateResource
+	return marshalUnknownMembers((*rawStateResource)(r))
+}
+
 // StateOutput represents an output value in a common state
 // representation.
 type StateOutput struct {
+	unknownMembers
+
 	// Whether or not the output was marked as sensitive.
 	Sensitive bool `json:"sensitive"`
 
@@ -207,19 +289,55 @@ type StateOutput struct {
 // which the default Go marshaller cannot ignore because it's a
 // not nil-able struct.
 type jsonStateOutput struct {
+	unknownMembers
+
 	Sensitive bool            `json:"sensitive"`
 	Value     interface{}     `json:"value,omitempty"`
 	Type      json.RawMessage `json:"type,omitempty"`
 }
 
+// UnmarshalJSON implements json.Unmarshaler for StateOutput.
+//
+// As per established convention this method should only ever
+// be invoked *indirectly* via [encoding/json] library.
+func (so *StateOutput) UnmarshalJSON(b []byte) error {
+	_, err := DecodeOptions{}.Unmarshal(b, so)
+	return err
+}
+
+// unmarshalJSONOptions implements optionsUnmarshaler for StateOutput, so
+// that its value is decoded according to the options of the document
+// containing it.
+func (so *StateOutput) unmarshalJSONOptions(d *jsonDecoder, b []byte) error {
+	var raw jsonStateOutput
+	if err := d.decode(b, &raw); err != nil {
+		return err
+	}
+
+	output := StateOutput{
+		unknownMembers: raw.unknownMembers,
+		Sensitive:      raw.Sensitive,
+		Value:          raw.Value,
+	}
+	if len(raw.Type) > 0 && !bytes.Equal(raw.Type, []byte("null")) {
+		if err := output.Type.UnmarshalJSON(raw.Type); err != nil {
+			return fmt.Errorf("invalid output type: %w", err)
+		}
+	}
+
+	*so = output
+	return nil
+}
+
 func (so *StateOutput) MarshalJSON() ([]byte, error) {
 	jsonSa := &jsonStateOutput{
-		Sensitive: so.Sensitive,
-		Value:     so.Value,
+		unknownMembers: so.unknownMembers,
+		Sensitive:      so.Sensitive,
+		Value:          so.Value,
 	}
 	if so.Type != cty.NilType {
 		outputType, _ := so.Type.MarshalJSON()
 		jsonSa.Type = outputType
 	}
-	return json.Marshal(jsonSa)
+	return marshalUnknownMembers(jsonSa)
 }
@@ -0,0 +1,230 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+// StateResourceQuery selects resources from a state representation, such
+// as State.Values, Plan.PlannedValues or Plan.PriorState. A resource must
+// match every field that is set; the zero value matches every resource.
+//
+// Fields documented as patterns are matched against the whole value,
+// where "*" matches any sequence of characters (including "." and "[")
+// and "?" matches any single character. All other characters match
+// themselves, so addresses such as `module.a["x"].aws_instance.b[0]` can
+// be used without escaping.
+type StateResourceQuery struct {
+	// Address is a pattern matched against the absolute resource
+	// address, for example "module.*.aws_iam_role.*".
+	Address string
+
+	// Mode is the resource mode, if set.
+	Mode ResourceMode
+
+	// Type is a pattern matched against the resource type, for example
+	// "aws_iam_*".
+	Type string
+
+	// ProviderName is a pattern matched against the provider name, for
+	// example "registry.terraform.io/hashicorp/aws".
+	ProviderName string
+
+	// ModuleAddress is a pattern matched against the address of the
+	// module containing the resource, if set. The root module has an
+	// empty address, so a pointer to "" selects only root module
+	// resources.
+	ModuleAddress *string
+
+	// Tainted, if set, selects only tainted or untainted resources.
+	Tainted *bool
+
+	// Deposed, if set, selects only deposed or current resource
+	// instance objects.
+	Deposed *bool
+
+	// DeposedKey is a pattern matched against the deposed key. Setting
+	// it selects only deposed objects.
+	DeposedKey string
+}
+
+// Matches returns true if r, which is contained in module, matches the
+// query.
+func (q StateResourceQuery) Matches(module *StateModule, r *StateResource) bool {
+	if r == nil {
+		return false
+	}
+
+	if q.Address != "" && !matchPattern(q.Address, r.Address) {
+		return false
+	}
+	if q.Mode != "" && q.Mode != r.Mode {
+		return false
+	}
+	if q.Type != "" && !matchPattern(q.Type, r.Type) {
+		return false
+	}
+	if q.ProviderName != "" && !matchPattern(q.ProviderName, r.ProviderName) {
+		return false
+	}
+	if q.ModuleAddress != nil {
+		var address string
+		if module != nil {
+			address = module.Address
+		}
+		if !matchPattern(*q.ModuleAddress, address) {
+			return false
+		}
+	}
+	if q.Tainted != nil && *q.Tainted != r.Tainted {
+		return false
+	}
+	if q.Deposed != nil && *q.Deposed != (r.DeposedKey != "") {
+		return false
+	}
+	if q.DeposedKey != "" && (r.DeposedKey == "" || !matchPattern(q.DeposedKey, r.DeposedKey)) {
+		return false
+	}
+
+	return true
+}
+
+// WalkModules calls fn for m and each of its descendant modules, depth
+// first, with parents before their children. If fn returns false the walk
+// stops and WalkModules returns false.
+func (m *StateModule) WalkModules(fn func(module *StateModule) bool) bool {
+	if m == nil {
+		return true
+	}
+
+	if !fn(m) {
+		return false
+	}
+
+	for _, child := range m.ChildModules {
+		if !child.WalkModules(fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Walk calls fn for every resource in m and its descendant modules, along
+// with the module that contains it. Modules are visited in the same order
+// as WalkModules, and resources in the order they appear in each module.
+// If fn returns false the walk stops and Walk returns false.
+func (m *StateModule) Walk(fn func(module *StateModule, r *StateResource) bool) bool {
+	return m.WalkModules(func(module *StateModule) bool {
+		for _, r := range module.Resources {
+			if r == nil {
+				continue
+			}
+			if !fn(module, r) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// Find returns every resource in m and its descendant modules that
+// matches q, in the order visited by Walk.
+func (m *StateModule) Find(q StateResourceQuery) []*StateResource {
+	var result []*StateResource
+	m.Walk(func(module *StateModule, r *StateResource) bool {
+		if q.Matches(module, r) {
+			result = append(result, r)
+		}
+		return true
+	})
+
+	return result
+}
+
+// WalkModules is StateModule.WalkModules for the root module.
+func (v *StateValues) WalkModules(fn func(module *StateModule) bool) bool {
+	if v == nil {
+		return true
+	}
+
+	return v.RootModule.WalkModules(fn)
+}
+
+// Walk is StateModule.Walk for the root module.
+func (v *StateValues) Walk(fn func(module *StateModule, r *StateResource) bool) bool {
+	if v == nil {
+		return true
+	}
+
+	return v.RootModule.Walk(fn)
+}
+
+// Find is StateModule.Find for the root module.
+func (v *StateValues) Find(q StateResourceQuery) []*StateResource {
+	if v == nil {
+		return nil
+	}
+
+	return v.RootModule.Find(q)
+}
+
+// WalkModules is StateModule.WalkModules for the root module of the
+// state values.
+func (s *State) WalkModules(fn func(module *StateModule) bool) bool {
+	if s == nil {
+		return true
+	}
+
+	return s.Values.WalkModules(fn)
+}
+
+// Walk is StateModule.Walk for the root module of the state values.
+func (s *State) Walk(fn func(module *StateModule, r *StateResource) bool) bool {
+	if s == nil {
+		return true
+	}
+
+	return s.Values.Walk(fn)
+}
+
+// Find is StateModule.Find for the root module of the state values.
+func (s *State) Find(q StateResourceQuery) []*StateResource {
+	if s == nil {
+		return nil
+	}
+
+	return s.Values.Find(q)
+}
+
+// matchPattern reports whether s matches pattern in its entirety, where
+// "*" matches any sequence of characters and "?" any single character.
+func matchPattern(pattern, s string) bool {
+	p, str := []rune(pattern), []rune(s)
+
+	// Position to resume from when a mismatch follows a "*": the index
+	// just past the star in p, and the next position to try in str.
+	star, next := -1, 0
+
+	i, j := 0, 0
+	for j < len(str) {
+		switch {
+		case i < len(p) && p[i] == '*':
+			star, next = i+1, j
+			i++
+		case i < len(p) && (p[i] == '?' || p[i] == str[j]):
+			i++
+			j++
+		case star != -1:
+			next++
+			i, j = star, next
+		default:
+			return false
+		}
+	}
+
+	for i < len(p) && p[i] == '*' {
+		i++
+	}
+
+	return i == len(p)
+}
@@ -0,0 +1,86 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.23
+
+package tfjson
+
+import "iter"
+
+// AllModules returns an iterator over m and its descendant modules, in
+// the order visited by WalkModules.
+func (m *StateModule) AllModules() iter.Seq[*StateModule] {
+	return func(yield func(*StateModule) bool) {
+		m.WalkModules(yield)
+	}
+}
+
+// AllResources returns an iterator over every resource in m and its
+// descendant modules, paired with the module that contains it, in the
+// order visited by Walk.
+func (m *StateModule) AllResources() iter.Seq2[*StateModule, *StateResource] {
+	return func(yield func(*StateModule, *StateResource) bool) {
+		m.Walk(yield)
+	}
+}
+
+// Query returns an iterator over the resources in m and its descendant
+// modules that match q, in the order visited by Walk.
+func (m *StateModule) Query(q StateResourceQuery) iter.Seq[*StateResource] {
+	return func(yield func(*StateResource) bool) {
+		m.Walk(func(module *StateModule, r *StateResource) bool {
+			if !q.Matches(module, r) {
+				return true
+			}
+			return yield(r)
+		})
+	}
+}
+
+// AllModules is StateModule.AllModules for the root module.
+func (v *StateValues) AllModules() iter.Seq[*StateModule] {
+	return func(yield func(*StateModule) bool) {
+		v.WalkModules(yield)
+	}
+}
+
+// AllResources is StateModule.AllResources for the root module.
+func (v *StateValues) AllResources() iter.Seq2[*StateModule, *StateResource] {
+	return func(yield func(*StateModule, *StateResource) bool) {
+		v.Walk(yield)
+	}
+}
+
+// Query is StateModule.Query for the root module.
+func (v *StateValues) Query(q StateResourceQuery) iter.Seq[*StateResource] {
+	if v == nil {
+		return func(func(*StateResource) bool) {}
+	}
+
+	return v.RootModule.Query(q)
+}
+
+// AllModules is StateModule.AllModules for the root module of the state
+// values.
+func (s *State) AllModules() iter.Seq[*StateModule] {
+	return func(yield func(*StateModule) bool) {
+		s.WalkModules(yield)
+	}
+}
+
+// AllResources is StateModule.AllResources for the root module of the
+// state values.
+func (s *State) AllResources() iter.Seq2[*StateModule, *StateResource] {
+	return func(yield func(*StateModule, *StateResource) bool) {
+		s.Walk(yield)
+	}
+}
+
+// Query is StateModule.Query for the root module of the state values.
+func (s *State) Query(q StateResourceQuery) iter.Seq[*StateResource] {
+	if s == nil {
+		return func(func(*StateResource) bool) {}
+	}
+
+	return s.Values.Query(q)
+}
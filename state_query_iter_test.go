@@ -0,0 +1,65 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.23
+
+package tfjson
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStateQuery_iterator(t *testing.T) {
+	state := testQueryState()
+
+	var actual []string
+	for r := range state.Query(StateResourceQuery{Type: "aws_iam_role"}) {
+		actual = append(actual, r.Address)
+		if r.Tainted {
+			break
+		}
+	}
+
+	expected := []string{
+		"aws_iam_role.root",
+		`module.app["web"].aws_iam_role.this`,
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("Query() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestStateAllResources_iterator(t *testing.T) {
+	state := testQueryState()
+
+	counts := map[string]int{}
+	for module, r := range state.Values.AllResources() {
+		if r.Address == "" {
+			t.Fatal("unexpected empty address")
+		}
+		counts[module.Address]++
+	}
+
+	expected := map[string]int{
+		"":                             2,
+		`module.app["web"]`:            2,
+		`module.app["web"].module.dns`: 1,
+	}
+	if diff := cmp.Diff(expected, counts); diff != "" {
+		t.Errorf("AllResources() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	var modules []string
+	for module := range state.AllModules() {
+		modules = append(modules, module.Address)
+	}
+	if len(modules) != 3 {
+		t.Errorf("expected 3 modules, got %q", modules)
+	}
+
+	for range (*State)(nil).Query(StateResourceQuery{}) {
+		t.Fatal("unexpected resource in nil state")
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testQueryState() *State {
+	return &State{
+		FormatVersion: "1.0",
+		Values: &StateValues{
+			RootModule: &StateModule{
+				Resources: []*StateResource{
+					{
+						Address:      "aws_iam_role.root",
+						Mode:         ManagedResourceMode,
+						Type:         "aws_iam_role",
+						Name:         "root",
+						ProviderName: "registry.terraform.io/hashicorp/aws",
+					},
+					{
+						Address:      "data.aws_caller_identity.current",
+						Mode:         DataResourceMode,
+						Type:         "aws_caller_identity",
+						Name:         "current",
+						ProviderName: "registry.terraform.io/hashicorp/aws",
+					},
+				},
+				ChildModules: []*StateModule{
+					{
+						Address: `module.app["web"]`,
+						Resources: []*StateResource{
+							{
+								Address:      `module.app["web"].aws_iam_role.this`,
+								Mode:         ManagedResourceMode,
+								Type:         "aws_iam_role",
+								Name:         "this",
+								ProviderName: "registry.terraform.io/hashicorp/aws",
+								Tainted:      true,
+							},
+							{
+								Address:      `module.app["web"].aws_iam_role.this`,
+								Mode:         ManagedResourceMode,
+								Type:         "aws_iam_role",
+								Name:         "this",
+								ProviderName: "registry.terraform.io/hashicorp/aws",
+								DeposedKey:   "00000001",
+							},
+						},
+						ChildModules: []*StateModule{
+							{
+								Address: `module.app["web"].module.dns`,
+								Resources: []*StateResource{
+									{
+										Address:      `module.app["web"].module.dns.google_dns_record_set.this[0]`,
+										Mode:         ManagedResourceMode,
+										Type:         "google_dns_record_set",
+										Name:         "this",
+										Index:        0,
+										ProviderName: "registry.terraform.io/hashicorp/google",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestStateFind(t *testing.T) {
+	state := testQueryState()
+	root := ""
+	app := `module.app["web"]`
+	tainted := true
+	deposed := false
+
+	cases := []struct {
+		name     string
+		query    StateResourceQuery
+		expected []string
+	}{
+		{
+			name:  "all",
+			query: StateResourceQuery{},
+			expected: []string{
+				"aws_iam_role.root",
+				"data.aws_caller_identity.current",
+				`module.app["web"].aws_iam_role.this`,
+				`module.app["web"].aws_iam_role.this`,
+				`module.app["web"].module.dns.google_dns_record_set.this[0]`,
+			},
+		},
+		{
+			name:  "type in any module",
+			query: StateResourceQuery{Type: "aws_iam_role"},
+			expected: []string{
+				"aws_iam_role.root",
+				`module.app["web"].aws_iam_role.this`,
+				`module.app["web"].aws_iam_role.this`,
+			},
+		},
+		{
+			name:     "address glob",
+			query:    StateResourceQuery{Address: `module.app["*"].module.*`},
+			expected: []string{`module.app["web"].module.dns.google_dns_record_set.this[0]`},
+		},
+		{
+			name:     "address literal brackets",
+			query:    StateResourceQuery{Address: `*.this[0]`},
+			expected: []string{`module.app["web"].module.dns.google_dns_record_set.this[0]`},
+		},
+		{
+			name:     "mode",
+			query:    StateResourceQuery{Mode: DataResourceMode},
+			expected: []string{"data.aws_caller_identity.current"},
+		},
+		{
+			name:     "provider",
+			query:    StateResourceQuery{ProviderName: "*/google"},
+			expected: []string{`module.app["web"].module.dns.google_dns_record_set.this[0]`},
+		},
+		{
+			name:  "root module",
+			query: StateResourceQuery{ModuleAddress: &root},
+			expected: []string{
+				"aws_iam_role.root",
+				"data.aws_caller_identity.current",
+			},
+		},
+		{
+			name:  "module",
+			query: StateResourceQuery{ModuleAddress: &app, Deposed: &deposed},
+			expected: []string{
+				`module.app["web"].aws_iam_role.this`,
+			},
+		},
+		{
+			name:     "tainted",
+			query:    StateResourceQuery{Tainted: &tainted},
+			expected: []string{`module.app["web"].aws_iam_role.this`},
+		},
+		{
+			name:     "deposed key",
+			query:    StateResourceQuery{DeposedKey: "*1"},
+			expected: []string{`module.app["web"].aws_iam_role.this`},
+		},
+		{
+			name:  "no match",
+			query: StateResourceQuery{Type: "aws_s3_bucket"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual []string
+			for _, r := range state.Find(tc.query) {
+				actual = append(actual, r.Address)
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("Find() mismatch (-expected +actual):\n%s", diff)
+			}
+		})
+	}
+
+	if r := state.Find(StateResourceQuery{Tainted: &tainted}); r[0].DeposedKey != "" {
+		t.Errorf("expected current object, got deposed %q", r[0].DeposedKey)
+	}
+}
+
+func TestStateWalk_earlyExit(t *testing.T) {
+	state := testQueryState()
+
+	var visited []string
+	completed := state.Walk(func(module *StateModule, r *StateResource) bool {
+		visited = append(visited, r.Address)
+		return module.Address == ""
+	})
+
+	if completed {
+		t.Fatal("expected Walk to report an early exit")
+	}
+
+	expected := []string{
+		"aws_iam_role.root",
+		"data.aws_caller_identity.current",
+		`module.app["web"].aws_iam_role.this`,
+	}
+	if diff := cmp.Diff(expected, visited); diff != "" {
+		t.Errorf("Walk() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestStateWalk_nil(t *testing.T) {
+	fn := func(*StateModule, *StateResource) bool {
+		t.Fatal("unexpected call")
+		return false
+	}
+
+	if !(*State)(nil).Walk(fn) || !(&State{}).Walk(fn) || !(&StateValues{}).Walk(fn) {
+		t.Fatal("expected Walk over an empty state to complete")
+	}
+	if (*StateValues)(nil).Find(StateResourceQuery{}) != nil {
+		t.Fatal("expected no resources")
+	}
+}
+
+func TestPlanPlannedValuesFind(t *testing.T) {
+	plan := testReadPlan(t, filepath.Join(testFixtureDir, "deep_module", testGoldenPlanFileName))
+
+	module := "module.foo.*"
+	actual := plan.PlannedValues.Find(StateResourceQuery{
+		ModuleAddress: &module,
+		Type:          "null_*",
+	})
+	if len(actual) != 1 || actual[0].Address != "module.foo.module.bar.null_resource.baz" {
+		t.Fatalf("unexpected resources: %#v", actual)
+	}
+
+	if actual := plan.PriorState.Find(StateResourceQuery{}); actual != nil {
+		t.Fatalf("expected no prior state resources, got %#v", actual)
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		expected   bool
+	}{
+		{"", "", true},
+		{"", "a", false},
+		{"*", "", true},
+		{"*", `module.a["b"].c`, true},
+		{"a?c", "abc", true},
+		{"a?c", "ac", false},
+		{"aws_*_role", "aws_iam_role", true},
+		{"aws_*_role", "aws_iam_role_policy", false},
+		{"*b", "*ab", true},
+		{"a*b*c", "aXbYbZc", true},
+		{"a*b*c", "aXbYbZ", false},
+		{`x["*"]`, `x["k"]`, true},
+		{`x[*]`, `x[0]`, true},
+	}
+
+	for _, tc := range cases {
+		if actual := matchPattern(tc.pattern, tc.s); actual != tc.expected {
+			t.Errorf("matchPattern(%q, %q) = %t, expected %t", tc.pattern, tc.s, actual, tc.expected)
+		}
+	}
+}
@@ -8,6 +8,11 @@ import (
 	"io"
 	"os"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestStateValidate_raw(t *testing.T) {
@@ -140,3 +145,53 @@ func TestStateValidate_fromPlan110(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestStateOutput_UnmarshalJSON(t *testing.T) {
+	const output = `{"sensitive":true,"value":[12345678901234567890],"type":["list","number"],"new_field":1}`
+
+	var outputs Outputs
+	outputs.SetDecodeOptions(DecodeOptions{UseJSONNumber: true, PreserveUnknownFields: true})
+	if err := json.Unmarshal([]byte(`{"foo":`+output+`}`), &outputs); err != nil {
+		t.Fatal(err)
+	}
+
+	var state State
+	state.SetDecodeOptions(DecodeOptions{UseJSONNumber: true, PreserveUnknownFields: true})
+	if err := json.Unmarshal([]byte(`{"format_version":"1.0","values":{"outputs":{"foo":`+output+`}}}`), &state); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &StateOutput{
+		Sensitive: true,
+		Type:      cty.List(cty.Number),
+		Value:     []interface{}{json.Number("12345678901234567890")},
+	}
+	for name, actual := range map[string]*StateOutput{
+		"outputs": outputs.Outputs["foo"],
+		"state":   state.Values.Outputs["foo"],
+	} {
+		if diff := cmp.Diff(expected, actual, ctydebug.CmpOptions, cmpopts.IgnoreUnexported(StateOutput{})); diff != "" {
+			t.Errorf("%s: unexpected output (-expected +actual):\n%s", name, diff)
+		}
+
+		b, err := json.Marshal(actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != output {
+			t.Errorf("%s: expected %s, got %s", name, output, b)
+		}
+	}
+
+	var direct StateOutput
+	if err := json.Unmarshal([]byte(`{"sensitive":false,"type":null,"value":"bar"}`), &direct); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&StateOutput{Value: "bar"}, &direct, ctydebug.CmpOptions, testCmpUnexported); diff != "" {
+		t.Errorf("unexpected output (-expected +actual):\n%s", diff)
+	}
+
+	if err := json.Unmarshal([]byte(`{"type":"strin"}`), &direct); err == nil {
+		t.Fatal("expected invalid type error")
+	}
+}
@@ -0,0 +1,535 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package statediff compares two Terraform states, such as snapshots of
+// "terraform show -json" taken at different times, and reports what
+// changed between them.
+//
+// Sensitive values, as marked in the SensitiveValues of each resource and
+// the Sensitive flag of each output, are compared but never included in
+// the result.
+package statediff
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// ChangeKind describes how a resource instance object or output differs
+// between two states.
+type ChangeKind string
+
+const (
+	// Added indicates the object or output is only present in the new
+	// state.
+	Added ChangeKind = "added"
+
+	// Removed indicates the object or output is only present in the old
+	// state.
+	Removed ChangeKind = "removed"
+
+	// Changed indicates the object or output is present in both states,
+	// with differences.
+	Changed ChangeKind = "changed"
+)
+
+// Result is the set of differences between two states. Objects and
+// outputs which are identical in both states are omitted.
+type Result struct {
+	// Resources lists the resource instance objects which differ, ordered
+	// by address and then deposed key.
+	Resources []ResourceDiff `json:"resources"`
+
+	// Outputs lists the root module outputs which differ, ordered by
+	// name.
+	Outputs []OutputDiff `json:"outputs"`
+}
+
+// Empty returns true if the two states had no differences.
+func (r *Result) Empty() bool {
+	return r == nil || (len(r.Resources) == 0 && len(r.Outputs) == 0)
+}
+
+// ResourceDiff describes the differences for a single resource instance
+// object, which is either the current object for an address or a deposed
+// object.
+type ResourceDiff struct {
+	// Address is the absolute resource instance address.
+	Address string `json:"address"`
+
+	// DeposedKey is set if the object is deposed. A deposed object that
+	// appears between the two states is reported as Added.
+	DeposedKey string `json:"deposed_key,omitempty"`
+
+	Mode         tfjson.ResourceMode `json:"mode"`
+	Type         string              `json:"type"`
+	ProviderName string              `json:"provider_name"`
+
+	Kind ChangeKind `json:"kind"`
+
+	// Attributes lists the changed attribute values, for Changed objects
+	// only.
+	Attributes []AttributeDiff `json:"attributes,omitempty"`
+
+	// Identity lists the changed identity values, for Changed objects
+	// only.
+	Identity []AttributeDiff `json:"identity,omitempty"`
+
+	// TaintedBefore and TaintedAfter are the tainted status of the object
+	// in the old and new states respectively. Only the side on which the
+	// object is present is set for Added and Removed objects.
+	TaintedBefore bool `json:"tainted_before"`
+	TaintedAfter  bool `json:"tainted_after"`
+
+	// SchemaVersionBefore and SchemaVersionAfter are the resource type
+	// schema versions in the old and new states respectively.
+	SchemaVersionBefore uint64 `json:"schema_version_before"`
+	SchemaVersionAfter  uint64 `json:"schema_version_after"`
+
+	// IdentitySchemaVersionBefore and IdentitySchemaVersionAfter are the
+	// resource identity schema versions in the old and new states
+	// respectively, if the resource has an identity.
+	IdentitySchemaVersionBefore *uint64 `json:"identity_schema_version_before,omitempty"`
+	IdentitySchemaVersionAfter  *uint64 `json:"identity_schema_version_after,omitempty"`
+}
+
+// TaintedChanged returns true if the object was tainted or untainted.
+func (d ResourceDiff) TaintedChanged() bool {
+	return d.Kind == Changed && d.TaintedBefore != d.TaintedAfter
+}
+
+// SchemaVersionChanged returns true if the resource type schema version
+// changed, indicating the provider upgraded the object.
+func (d ResourceDiff) SchemaVersionChanged() bool {
+	return d.Kind == Changed && d.SchemaVersionBefore != d.SchemaVersionAfter
+}
+
+// IdentityChanged returns true if the identity or the identity schema
+// version changed.
+func (d ResourceDiff) IdentityChanged() bool {
+	return d.Kind == Changed && (len(d.Identity) > 0 ||
+		!reflect.DeepEqual(d.IdentitySchemaVersionBefore, d.IdentitySchemaVersionAfter))
+}
+
+// AttributeDiff describes a single changed value within a resource.
+type AttributeDiff struct {
+	// Path is the path to the value, using the same representation as
+	// Change.ReplacePaths: each step is either a string key or an integer
+	// index.
+	Path []interface{} `json:"path"`
+
+	// Before and After are the values in the old and new states. A value
+	// absent on one side is nil. Both are nil if Sensitive is set.
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+
+	// Sensitive is true if the value is sensitive in either state.
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// OutputDiff describes a changed root module output.
+type OutputDiff struct {
+	Name string     `json:"name"`
+	Kind ChangeKind `json:"kind"`
+
+	// Before and After are the values in the old and new states. A value
+	// absent on one side is nil. Both are nil if Sensitive is set.
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+
+	// Sensitive is true if the output is sensitive in either state.
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// Diff compares the states from and to, where from is the older of the
+// two. Either state may be nil or lack values, in which case it is
+// treated as empty.
+//
+// An error is only returned if the SensitiveValues of a resource cannot
+// be decoded, as a difference could not then be reported safely.
+func Diff(from, to *tfjson.State) (*Result, error) {
+	result := &Result{
+		Resources: []ResourceDiff{},
+		Outputs:   []OutputDiff{},
+	}
+
+	before, beforeKeys := indexResources(from)
+	after, afterKeys := indexResources(to)
+
+	keys := beforeKeys
+	for _, k := range afterKeys {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].address != keys[j].address {
+			return keys[i].address < keys[j].address
+		}
+		return keys[i].deposedKey < keys[j].deposedKey
+	})
+
+	for _, k := range keys {
+		d, err := diffResource(before[k], after[k])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		if d != nil {
+			result.Resources = append(result.Resources, *d)
+		}
+	}
+
+	result.Outputs = diffOutputs(outputs(from), outputs(to))
+
+	return result, nil
+}
+
+// resourceKey identifies a resource instance object within a state.
+type resourceKey struct {
+	address    string
+	deposedKey string
+}
+
+func (k resourceKey) String() string {
+	if k.deposedKey != "" {
+		return fmt.Sprintf("%s (deposed object %s)", k.address, k.deposedKey)
+	}
+
+	return k.address
+}
+
+// indexResources returns the resources in s by key, along with the keys
+// in the order they were found.
+func indexResources(s *tfjson.State) (map[resourceKey]*tfjson.StateResource, []resourceKey) {
+	index := map[resourceKey]*tfjson.StateResource{}
+	var keys []resourceKey
+
+	s.Walk(func(_ *tfjson.StateModule, r *tfjson.StateResource) bool {
+		k := resourceKey{address: r.Address, deposedKey: r.DeposedKey}
+		if _, ok := index[k]; !ok {
+			index[k] = r
+			keys = append(keys, k)
+		}
+		return true
+	})
+
+	return index, keys
+}
+
+func diffResource(before, after *tfjson.StateResource) (*ResourceDiff, error) {
+	r := after
+	if r == nil {
+		r = before
+	}
+
+	d := &ResourceDiff{
+		Address:      r.Address,
+		DeposedKey:   r.DeposedKey,
+		Mode:         r.Mode,
+		Type:         r.Type,
+		ProviderName: r.ProviderName,
+	}
+
+	if before != nil {
+		d.TaintedBefore = before.Tainted
+		d.SchemaVersionBefore = before.SchemaVersion
+		d.IdentitySchemaVersionBefore = before.IdentitySchemaVersion
+	}
+	if after != nil {
+		d.TaintedAfter = after.Tainted
+		d.SchemaVersionAfter = after.SchemaVersion
+		d.IdentitySchemaVersionAfter = after.IdentitySchemaVersion
+	}
+
+	switch {
+	case before == nil:
+		d.Kind = Added
+		return d, nil
+	case after == nil:
+		d.Kind = Removed
+		return d, nil
+	}
+
+	beforeSensitive, err := decodeSensitive(before.SensitiveValues)
+	if err != nil {
+		return nil, err
+	}
+	afterSensitive, err := decodeSensitive(after.SensitiveValues)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &comparer{}
+	c.compare(attributeValues(before.AttributeValues), attributeValues(after.AttributeValues), beforeSensitive, afterSensitive, nil)
+	d.Attributes = c.diffs
+
+	c = &comparer{}
+	c.compare(attributeValues(before.IdentityValues), attributeValues(after.IdentityValues), nil, nil, nil)
+	d.Identity = c.diffs
+
+	d.Kind = Changed
+	if len(d.Attributes) == 0 && !d.TaintedChanged() && !d.SchemaVersionChanged() && !d.IdentityChanged() {
+		return nil, nil
+	}
+
+	return d, nil
+}
+
+// attributeValues converts a possibly nil object to a value for
+// comparison, so that a nil object is equal to an empty one.
+func attributeValues(v map[string]interface{}) interface{} {
+	if v == nil {
+		return map[string]interface{}{}
+	}
+
+	return v
+}
+
+func decodeSensitive(raw json.RawMessage) (interface{}, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid sensitive values: %w", err)
+	}
+
+	return result, nil
+}
+
+func outputs(s *tfjson.State) map[string]*tfjson.StateOutput {
+	if s == nil || s.Values == nil {
+		return nil
+	}
+
+	return s.Values.Outputs
+}
+
+func diffOutputs(before, after map[string]*tfjson.StateOutput) []OutputDiff {
+	result := []OutputDiff{}
+
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b, a := before[name], after[name]
+		if b == nil && a == nil {
+			continue
+		}
+
+		d := OutputDiff{
+			Name:      name,
+			Sensitive: (b != nil && b.Sensitive) || (a != nil && a.Sensitive),
+		}
+
+		switch {
+		case b == nil:
+			d.Kind = Added
+		case a == nil:
+			d.Kind = Removed
+		default:
+			if b.Sensitive == a.Sensitive && b.Type.Equals(a.Type) && equalValues(b.Value, a.Value) {
+				continue
+			}
+			d.Kind = Changed
+		}
+
+		if !d.Sensitive {
+			if b != nil {
+				d.Before = b.Value
+			}
+			if a != nil {
+				d.After = a.Value
+			}
+		}
+
+		result = append(result, d)
+	}
+
+	return result
+}
+
+// comparer collects the differences between two decoded JSON values.
+type comparer struct {
+	diffs []AttributeDiff
+}
+
+// compare records the differences between before and after at path,
+// descending into objects and arrays present on both sides. The
+// sensitivity markers follow the same structure as the values.
+func (c *comparer) compare(before, after, beforeSensitive, afterSensitive interface{}, path []interface{}) {
+	sensitive := isSensitive(beforeSensitive) || isSensitive(afterSensitive)
+
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok && !sensitive {
+			keys := make([]string, 0, len(b)+len(a))
+			for k := range b {
+				keys = append(keys, k)
+			}
+			for k := range a {
+				if _, ok := b[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				c.compare(b[k], a[k], objectStep(beforeSensitive, k), objectStep(afterSensitive, k), childPath(path, k))
+			}
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok && !sensitive {
+			n := len(b)
+			if len(a) > n {
+				n = len(a)
+			}
+
+			for i := 0; i < n; i++ {
+				var bv, av interface{}
+				if i < len(b) {
+					bv = b[i]
+				}
+				if i < len(a) {
+					av = a[i]
+				}
+				c.compare(bv, av, arrayStep(beforeSensitive, i), arrayStep(afterSensitive, i), childPath(path, i))
+			}
+			return
+		}
+	}
+
+	if equalValues(before, after) {
+		return
+	}
+
+	d := AttributeDiff{
+		Path:      path,
+		Sensitive: sensitive || containsSensitive(beforeSensitive) || containsSensitive(afterSensitive),
+	}
+	if !d.Sensitive {
+		d.Before = before
+		d.After = after
+	}
+	if d.Path == nil {
+		d.Path = []interface{}{}
+	}
+
+	c.diffs = append(c.diffs, d)
+}
+
+func isSensitive(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// containsSensitive returns true if any value within v is marked as
+// sensitive, so that a value which can't be compared step by step is not
+// revealed.
+func containsSensitive(v interface{}) bool {
+	switch x := v.(type) {
+	case bool:
+		return x
+	case map[string]interface{}:
+		for _, child := range x {
+			if containsSensitive(child) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range x {
+			if containsSensitive(child) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func objectStep(sensitive interface{}, key string) interface{} {
+	if m, ok := sensitive.(map[string]interface{}); ok {
+		return m[key]
+	}
+
+	return nil
+}
+
+func arrayStep(sensitive interface{}, i int) interface{} {
+	if s, ok := sensitive.([]interface{}); ok && i < len(s) {
+		return s[i]
+	}
+
+	return nil
+}
+
+func childPath(path []interface{}, step interface{}) []interface{} {
+	result := make([]interface{}, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, step)
+}
+
+// equalValues compares two decoded JSON values, treating numbers decoded
+// as float64 and as json.Number as equal if they represent the same
+// number.
+func equalValues(a, b interface{}) bool {
+	if an, ok := number(a); ok {
+		bn, ok := number(b)
+		return ok && an.Cmp(bn) == 0
+	}
+
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !equalValues(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equalValues(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func number(v interface{}) (*big.Float, bool) {
+	switch x := v.(type) {
+	case float64:
+		return big.NewFloat(x), true
+	case json.Number:
+		f, _, err := big.ParseFloat(string(x), 10, 512, big.ToNearestEven)
+		return f, err == nil
+	}
+
+	return nil, false
+}
@@ -0,0 +1,292 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package statediff
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func testState(outputs map[string]*tfjson.StateOutput, resources ...*tfjson.StateResource) *tfjson.State {
+	root := &tfjson.StateModule{}
+	child := &tfjson.StateModule{Address: "module.child"}
+	for _, r := range resources {
+		if strings.HasPrefix(r.Address, "module.child.") {
+			child.Resources = append(child.Resources, r)
+		} else {
+			root.Resources = append(root.Resources, r)
+		}
+	}
+	root.ChildModules = []*tfjson.StateModule{child}
+
+	return &tfjson.State{
+		FormatVersion: "1.0",
+		Values: &tfjson.StateValues{
+			Outputs:    outputs,
+			RootModule: root,
+		},
+	}
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name     string
+		from, to *tfjson.State
+		expected *Result
+	}{
+		{
+			name:     "nil",
+			expected: &Result{Resources: []ResourceDiff{}, Outputs: []OutputDiff{}},
+		},
+		{
+			name: "identical",
+			from: testState(nil, &tfjson.StateResource{
+				Address:         "null_resource.foo",
+				AttributeValues: map[string]interface{}{"id": "1", "n": json.Number("1.0")},
+			}),
+			to: testState(nil, &tfjson.StateResource{
+				Address:         "null_resource.foo",
+				AttributeValues: map[string]interface{}{"id": "1", "n": float64(1)},
+			}),
+			expected: &Result{Resources: []ResourceDiff{}, Outputs: []OutputDiff{}},
+		},
+		{
+			name: "added and removed",
+			from: testState(nil, &tfjson.StateResource{
+				Address: "null_resource.gone",
+				Type:    "null_resource",
+				Tainted: true,
+			}),
+			to: testState(nil,
+				&tfjson.StateResource{
+					Address:       "module.child.null_resource.new",
+					Type:          "null_resource",
+					SchemaVersion: 2,
+				},
+				&tfjson.StateResource{
+					Address:    "null_resource.gone",
+					Type:       "null_resource",
+					DeposedKey: "abcd1234",
+				},
+			),
+			expected: &Result{
+				Resources: []ResourceDiff{
+					{
+						Address:            "module.child.null_resource.new",
+						Type:               "null_resource",
+						Kind:               Added,
+						SchemaVersionAfter: 2,
+					},
+					{
+						Address:       "null_resource.gone",
+						Type:          "null_resource",
+						Kind:          Removed,
+						TaintedBefore: true,
+					},
+					{
+						Address:    "null_resource.gone",
+						DeposedKey: "abcd1234",
+						Type:       "null_resource",
+						Kind:       Added,
+					},
+				},
+				Outputs: []OutputDiff{},
+			},
+		},
+		{
+			name: "attributes",
+			from: testState(nil, &tfjson.StateResource{
+				Address: "aws_instance.web",
+				AttributeValues: map[string]interface{}{
+					"ami":  "ami-1",
+					"tags": map[string]interface{}{"Name": "web", "Env": "dev"},
+					"ebs":  []interface{}{map[string]interface{}{"size": float64(8)}},
+					"gone": "x",
+				},
+			}),
+			to: testState(nil, &tfjson.StateResource{
+				Address: "aws_instance.web",
+				AttributeValues: map[string]interface{}{
+					"ami":  "ami-2",
+					"tags": map[string]interface{}{"Name": "web", "Team": "ops"},
+					"ebs": []interface{}{
+						map[string]interface{}{"size": float64(16)},
+						map[string]interface{}{"size": float64(8)},
+					},
+				},
+			}),
+			expected: &Result{
+				Resources: []ResourceDiff{
+					{
+						Address: "aws_instance.web",
+						Kind:    Changed,
+						Attributes: []AttributeDiff{
+							{Path: []interface{}{"ami"}, Before: "ami-1", After: "ami-2"},
+							{Path: []interface{}{"ebs", 0, "size"}, Before: float64(8), After: float64(16)},
+							{Path: []interface{}{"ebs", 1}, After: map[string]interface{}{"size": float64(8)}},
+							{Path: []interface{}{"gone"}, Before: "x"},
+							{Path: []interface{}{"tags", "Env"}, Before: "dev"},
+							{Path: []interface{}{"tags", "Team"}, After: "ops"},
+						},
+					},
+				},
+				Outputs: []OutputDiff{},
+			},
+		},
+		{
+			name: "sensitive",
+			from: testState(nil, &tfjson.StateResource{
+				Address: "aws_db_instance.main",
+				AttributeValues: map[string]interface{}{
+					"password": "old",
+					"config":   map[string]interface{}{"user": "admin", "key": "k1"},
+					"secrets":  []interface{}{"a"},
+					"port":     float64(5432),
+				},
+				SensitiveValues: json.RawMessage(`{"password":true,"config":{"key":true},"secrets":[true]}`),
+			}),
+			to: testState(nil, &tfjson.StateResource{
+				Address: "aws_db_instance.main",
+				AttributeValues: map[string]interface{}{
+					"password": "new",
+					"config":   map[string]interface{}{"user": "root", "key": "k2"},
+					"secrets":  []interface{}{"a", "b"},
+					"port":     float64(5432),
+				},
+				SensitiveValues: json.RawMessage(`{"password":true,"config":{"key":true},"secrets":true}`),
+			}),
+			expected: &Result{
+				Resources: []ResourceDiff{
+					{
+						Address: "aws_db_instance.main",
+						Kind:    Changed,
+						Attributes: []AttributeDiff{
+							{Path: []interface{}{"config", "key"}, Sensitive: true},
+							{Path: []interface{}{"config", "user"}, Before: "admin", After: "root"},
+							{Path: []interface{}{"password"}, Sensitive: true},
+							{Path: []interface{}{"secrets"}, Sensitive: true},
+						},
+					},
+				},
+				Outputs: []OutputDiff{},
+			},
+		},
+		{
+			name: "tainted, schema version and identity",
+			from: testState(nil, &tfjson.StateResource{
+				Address:               "aws_instance.web",
+				SchemaVersion:         1,
+				IdentitySchemaVersion: uint64Ptr(0),
+				IdentityValues:        map[string]interface{}{"id": "i-1"},
+			}),
+			to: testState(nil, &tfjson.StateResource{
+				Address:               "aws_instance.web",
+				SchemaVersion:         2,
+				Tainted:               true,
+				IdentitySchemaVersion: uint64Ptr(0),
+				IdentityValues:        map[string]interface{}{"id": "i-2"},
+			}),
+			expected: &Result{
+				Resources: []ResourceDiff{
+					{
+						Address:                     "aws_instance.web",
+						Kind:                        Changed,
+						Identity:                    []AttributeDiff{{Path: []interface{}{"id"}, Before: "i-1", After: "i-2"}},
+						TaintedAfter:                true,
+						SchemaVersionBefore:         1,
+						SchemaVersionAfter:          2,
+						IdentitySchemaVersionBefore: uint64Ptr(0),
+						IdentitySchemaVersionAfter:  uint64Ptr(0),
+					},
+				},
+				Outputs: []OutputDiff{},
+			},
+		},
+		{
+			name: "outputs",
+			from: testState(map[string]*tfjson.StateOutput{
+				"same":    {Value: "a", Type: cty.String},
+				"changed": {Value: "a", Type: cty.String},
+				"secret":  {Value: "a", Type: cty.String, Sensitive: true},
+				"gone":    {Value: "a", Type: cty.String},
+			}),
+			to: testState(map[string]*tfjson.StateOutput{
+				"same":    {Value: "a", Type: cty.String},
+				"changed": {Value: "b", Type: cty.String},
+				"secret":  {Value: "b", Type: cty.String, Sensitive: true},
+				"new":     {Value: "b", Type: cty.String, Sensitive: true},
+			}),
+			expected: &Result{
+				Resources: []ResourceDiff{},
+				Outputs: []OutputDiff{
+					{Name: "changed", Kind: Changed, Before: "a", After: "b"},
+					{Name: "gone", Kind: Removed, Before: "a"},
+					{Name: "new", Kind: Added, Sensitive: true},
+					{Name: "secret", Kind: Changed, Sensitive: true},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := Diff(tc.from, tc.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("Diff() mismatch (-expected +actual):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiff_sensitiveNotRevealed(t *testing.T) {
+	from := testState(nil, &tfjson.StateResource{
+		Address:         "aws_db_instance.main",
+		AttributeValues: map[string]interface{}{"password": "hunter2"},
+		SensitiveValues: json.RawMessage(`{}`),
+	})
+	to := testState(nil, &tfjson.StateResource{
+		Address:         "aws_db_instance.main",
+		AttributeValues: map[string]interface{}{"password": "correcthorse"},
+		SensitiveValues: json.RawMessage(`{"password":true}`),
+	})
+
+	result, err := Diff(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"hunter2", "correcthorse"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("result contains %q: %s", secret, b)
+		}
+	}
+}
+
+func TestDiff_invalidSensitiveValues(t *testing.T) {
+	from := testState(nil, &tfjson.StateResource{
+		Address:         "aws_db_instance.main",
+		SensitiveValues: json.RawMessage(`{`),
+	})
+
+	if _, err := Diff(from, from); err == nil {
+		t.Fatal("expected error")
+	}
+}
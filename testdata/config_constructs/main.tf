@@ -0,0 +1,117 @@
+# The demo provider is a local build of a provider with a single action
+# type, demo_notify, which has a required "message" attribute.
+terraform {
+  required_providers {
+    demo = {
+      source = "example.com/test/demo"
+    }
+  }
+}
+
+variable "environment" {
+  type        = string
+  default     = "staging"
+  description = "The environment to deploy into."
+  nullable    = false
+
+  validation {
+    condition     = contains(["staging", "production"], var.environment)
+    error_message = "The environment must be staging or production."
+  }
+}
+
+variable "token" {
+  type      = string
+  default   = "secret"
+  sensitive = true
+  ephemeral = true
+}
+
+variable "replicas" {
+  type    = number
+  default = 2
+}
+
+variable "labels" {
+  type = map(string)
+  default = {
+    team = "platform"
+  }
+}
+
+resource "terraform_data" "revision" {
+  input = "1"
+}
+
+resource "terraform_data" "app" {
+  count = var.replicas
+  input = "${var.environment}-${count.index}"
+
+  lifecycle {
+    create_before_destroy = true
+    ignore_changes        = [input]
+    replace_triggered_by  = [terraform_data.revision]
+
+    precondition {
+      condition     = var.replicas > 0
+      error_message = "At least one replica is required."
+    }
+
+    action_trigger {
+      events    = [after_create, after_update]
+      condition = var.environment == "production"
+      actions   = [action.demo_notify.deployed]
+    }
+  }
+}
+
+resource "terraform_data" "labelled" {
+  for_each   = var.labels
+  input      = each.value
+  depends_on = [terraform_data.revision]
+
+  provisioner "local-exec" {
+    command = "echo ${self.input}"
+  }
+}
+
+action "demo_notify" "deployed" {
+  config {
+    message = "Deployed to ${var.environment}."
+  }
+}
+
+action "demo_notify" "batch" {
+  count = 2
+
+  config {
+    message = "Batch ${count.index}."
+  }
+}
+
+module "notifier" {
+  source     = "./notifier"
+  count      = 1
+  token      = var.token
+  depends_on = [terraform_data.app]
+}
+
+output "app_ids" {
+  value       = terraform_data.app[*].id
+  description = "The IDs of the app instances."
+
+  precondition {
+    condition     = length(terraform_data.app) > 0
+    error_message = "No app instances."
+  }
+}
+
+output "environment" {
+  value     = var.environment
+  sensitive = true
+}
+
+output "revision" {
+  value      = terraform_data.revision.output
+  depends_on = [terraform_data.labelled]
+}
@@ -0,0 +1,24 @@
+variable "token" {
+  type      = string
+  sensitive = true
+  ephemeral = true
+}
+
+resource "terraform_data" "prefix" {
+  input = var.prefix
+}
+
+output "token" {
+  value     = var.token
+  sensitive = true
+  ephemeral = true
+}
+
+output "length" {
+  value = terraform_data.prefix.output
+}
+
+variable "prefix" {
+  type    = string
+  default = "notify"
+}
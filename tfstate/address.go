@@ -0,0 +1,106 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// formatKey returns the address representation of an instance key, which
+// is either a number or a string as decoded from JSON.
+func formatKey(key interface{}) string {
+	switch k := key.(type) {
+	case nil:
+		return ""
+	case string:
+		return "[" + strconv.Quote(k) + "]"
+	case json.Number:
+		return "[" + k.String() + "]"
+	case float64:
+		return "[" + strconv.FormatFloat(k, 'f', -1, 64) + "]"
+	}
+
+	return fmt.Sprintf("[%v]", key)
+}
+
+// splitModule separates the module instance address from the remainder
+// of an address, which is relative to that module.
+func splitModule(address string) (string, string, error) {
+	modules, rest, err := tfjson.SplitModuleAddress(address)
+	if err != nil {
+		return "", "", err
+	}
+
+	steps := make([]string, len(modules))
+	for i, m := range modules {
+		steps[i] = m.Raw
+	}
+
+	return strings.Join(steps, "."), rest, nil
+}
+
+// moduleAncestors returns the address of module and of every module
+// instance containing it, outermost first. The root module is not
+// included.
+func moduleAncestors(module string) ([]string, error) {
+	modules, rest, err := tfjson.SplitModuleAddress(module)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("invalid module address %q", module)
+	}
+
+	var result []string
+	var prefix string
+	for _, m := range modules {
+		if prefix != "" {
+			prefix += "."
+		}
+		prefix += m.Raw
+		result = append(result, prefix)
+	}
+
+	return result, nil
+}
+
+// providerSource extracts the provider source address from the absolute
+// address of a provider configuration, such as
+// `module.child.provider["registry.terraform.io/hashicorp/aws"].west`.
+// States written by Terraform 0.12 use the legacy form
+// `provider.aws.west`, in which case only the provider type is returned.
+func providerSource(provider string) (string, error) {
+	_, rest, err := splitModule(provider)
+	if err != nil {
+		return "", fmt.Errorf("invalid provider address %q: %w", provider, err)
+	}
+
+	// The source address is a quoted string, so the address is not a
+	// valid reference.
+	if quoted, ok := strings.CutPrefix(rest, "provider["); ok {
+		source, err := strconv.QuotedPrefix(quoted)
+		if err != nil {
+			return "", fmt.Errorf("invalid provider address %q: %w", provider, err)
+		}
+
+		alias := strings.TrimPrefix(quoted[len(source):], "]")
+		if len(alias) == len(quoted[len(source):]) || (alias != "" && !strings.HasPrefix(alias, ".")) {
+			return "", fmt.Errorf("invalid provider address %q", provider)
+		}
+
+		return strconv.Unquote(source)
+	}
+
+	ref, err := tfjson.ParseReference(rest)
+	if err != nil || ref.Kind != tfjson.ReferenceKindResource || ref.Type != "provider" || ref.Key != nil {
+		return "", fmt.Errorf("invalid provider address %q", provider)
+	}
+
+	return ref.Name, nil
+}
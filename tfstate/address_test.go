@@ -0,0 +1,60 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfstate
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestModuleAncestors(t *testing.T) {
+	cases := map[string][]string{
+		"":                     nil,
+		"module.a":             {"module.a"},
+		"module.a[0].module.b": {"module.a[0]", "module.a[0].module.b"},
+		`module.a["x.y"].module.b["]"]`: {
+			`module.a["x.y"]`,
+			`module.a["x.y"].module.b["]"]`,
+		},
+	}
+
+	for input, expected := range cases {
+		got, err := moduleAncestors(input)
+		if err != nil {
+			t.Errorf("%q: %s", input, err)
+			continue
+		}
+		if diff := cmp.Diff(expected, got); diff != "" {
+			t.Errorf("%q: mismatch (-want +got):\n%s", input, diff)
+		}
+	}
+}
+
+func TestProviderSource(t *testing.T) {
+	cases := map[string]string{
+		`provider["registry.terraform.io/hashicorp/aws"]`:                    "registry.terraform.io/hashicorp/aws",
+		`provider["registry.terraform.io/hashicorp/aws"].west`:               "registry.terraform.io/hashicorp/aws",
+		`module.a["k"].provider["registry.terraform.io/hashicorp/aws"].west`: "registry.terraform.io/hashicorp/aws",
+		"provider.aws":            "aws",
+		"module.a.provider.aws.b": "aws",
+	}
+
+	for input, expected := range cases {
+		got, err := providerSource(input)
+		if err != nil {
+			t.Errorf("%q: %s", input, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("%q: expected %q, got %q", input, expected, got)
+		}
+	}
+
+	for _, input := range []string{"aws", `provider["aws"`, "module.a", "provider[0]"} {
+		if _, err := providerSource(input); err == nil {
+			t.Errorf("%q: expected error", input)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfstate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// ToJSONState converts the raw state into the representation produced by
+// "terraform show -json", so that it can be used with tooling built on
+// the tfjson package.
+//
+// Data which has no equivalent in that representation, such as the
+// serial, lineage and the private data of each object, is discarded.
+// Objects stored in the legacy flatmap format by Terraform 0.11 and
+// earlier cannot be converted without the provider schema, and so are
+// reported as an error.
+func (s *State) ToJSONState() (*tfjson.State, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &tfjson.State{
+		FormatVersion:    "1.0",
+		TerraformVersion: s.TerraformVersion,
+		Values: &tfjson.StateValues{
+			RootModule: &tfjson.StateModule{},
+		},
+	}
+
+	if len(s.Outputs) > 0 {
+		result.Values.Outputs = make(map[string]*tfjson.StateOutput, len(s.Outputs))
+	}
+	for name, o := range s.Outputs {
+		if o == nil {
+			continue
+		}
+		output := &tfjson.StateOutput{
+			Sensitive: o.Sensitive,
+			Value:     o.Value,
+		}
+		if len(o.Type) > 0 && !bytes.Equal(o.Type, []byte("null")) {
+			if err := output.Type.UnmarshalJSON(o.Type); err != nil {
+				return nil, fmt.Errorf("output %q: invalid type: %w", name, err)
+			}
+		}
+		result.Values.Outputs[name] = output
+	}
+
+	modules := map[string]*tfjson.StateModule{"": result.Values.RootModule}
+	for _, r := range s.Resources {
+		if r == nil {
+			continue
+		}
+
+		module, err := stateModule(modules, r.Module)
+		if err != nil {
+			return nil, fmt.Errorf("resource %s.%s: %w", r.Type, r.Name, err)
+		}
+
+		resources, err := r.toJSON()
+		if err != nil {
+			return nil, err
+		}
+		module.Resources = append(module.Resources, resources...)
+	}
+	for _, module := range modules {
+		sort.Slice(module.ChildModules, func(i, j int) bool {
+			return module.ChildModules[i].Address < module.ChildModules[j].Address
+		})
+	}
+
+	for _, cr := range s.CheckResults {
+		if cr == nil {
+			continue
+		}
+		check, err := cr.toJSON(s.decodeOptions.UseJSONNumber)
+		if err != nil {
+			return nil, err
+		}
+		result.Checks = append(result.Checks, check)
+	}
+
+	return result, nil
+}
+
+// stateModule returns the module at address, creating it and any missing
+// ancestors.
+func stateModule(modules map[string]*tfjson.StateModule, address string) (*tfjson.StateModule, error) {
+	if module, ok := modules[address]; ok {
+		return module, nil
+	}
+
+	ancestors, err := moduleAncestors(address)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := modules[""]
+	for _, a := range ancestors {
+		module, ok := modules[a]
+		if !ok {
+			module = &tfjson.StateModule{Address: a}
+			modules[a] = module
+			parent.ChildModules = append(parent.ChildModules, module)
+		}
+		parent = module
+	}
+
+	return parent, nil
+}
+
+// toJSON returns a StateResource for each current and deposed object of
+// each instance of the resource.
+func (r *Resource) toJSON() ([]*tfjson.StateResource, error) {
+	providerName, err := providerSource(r.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	address := r.Type + "." + r.Name
+	if r.Mode == string(tfjson.DataResourceMode) {
+		address = "data." + address
+	}
+	if r.Module != "" {
+		address = r.Module + "." + address
+	}
+
+	result := make([]*tfjson.StateResource, 0, len(r.Instances))
+	for _, inst := range r.Instances {
+		if inst == nil {
+			continue
+		}
+
+		instAddress := address + formatKey(inst.IndexKey)
+		if inst.AttributesFlat != nil && inst.Attributes == nil {
+			return nil, fmt.Errorf("%s: legacy flatmap attributes are not supported", instAddress)
+		}
+
+		sensitive, err := sensitiveValues(inst.Attributes, inst.SensitiveAttributes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", instAddress, err)
+		}
+
+		result = append(result, &tfjson.StateResource{
+			Address:               instAddress,
+			Mode:                  tfjson.ResourceMode(r.Mode),
+			Type:                  r.Type,
+			Name:                  r.Name,
+			Index:                 inst.IndexKey,
+			ProviderName:          providerName,
+			SchemaVersion:         inst.SchemaVersion,
+			AttributeValues:       inst.Attributes,
+			SensitiveValues:       sensitive,
+			DependsOn:             inst.Dependencies,
+			Tainted:               inst.Status == InstanceStatusTainted,
+			DeposedKey:            inst.Deposed,
+			IdentitySchemaVersion: inst.IdentitySchemaVersion,
+			IdentityValues:        inst.Identity,
+		})
+	}
+
+	return result, nil
+}
+
+// sensitiveValues builds the sensitive_values structure of a resource
+// instance: the same shape as its attribute values, with true at each
+// sensitive position. As in "terraform show -json", objects and maps only
+// contain those elements which are or contain sensitive values, while
+// lists contain an element for every value.
+func sensitiveValues(attributes map[string]interface{}, paths []Path) (json.RawMessage, error) {
+	sensitive := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		sensitive[key] = struct{}{}
+	}
+
+	var result interface{} = map[string]interface{}{}
+	if attributes != nil {
+		result = sensitiveValue(attributes, "", sensitive)
+	}
+
+	return json.Marshal(result)
+}
+
+func sensitiveValue(v interface{}, path string, sensitive map[string]struct{}) interface{} {
+	if _, ok := sensitive[path]; ok && path != "" {
+		return true
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for k, elem := range v {
+			s := sensitiveValue(elem, path+"["+strconv.Quote(k)+"]", sensitive)
+			if s != false {
+				result[k] = s
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, elem := range v {
+			result[i] = sensitiveValue(elem, path+"["+strconv.Itoa(i)+"]", sensitive)
+		}
+		return result
+	}
+
+	return false
+}
+
+// key returns a string uniquely identifying the path, in which attribute
+// names and string keys are not distinguished, as neither are objects and
+// maps in JSON.
+func (p Path) key() (string, error) {
+	var b strings.Builder
+	for _, step := range p {
+		switch step.Type {
+		case "get_attr":
+			var name string
+			if err := json.Unmarshal(step.Value, &name); err != nil {
+				return "", fmt.Errorf("invalid sensitive attribute path: %w", err)
+			}
+			b.WriteString("[" + strconv.Quote(name) + "]")
+		case "index":
+			key, err := indexKey(step.Value)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString("[" + key + "]")
+		default:
+			return "", fmt.Errorf("invalid sensitive attribute path: unsupported step type %q", step.Type)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// indexKey returns the key of an index step, which is the JSON
+// representation of a cty value: an object with "value" and "type"
+// properties.
+func indexKey(raw json.RawMessage) (string, error) {
+	var key struct {
+		Value json.RawMessage `json:"value"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&key); err != nil {
+		return "", fmt.Errorf("invalid sensitive attribute path: %w", err)
+	}
+
+	var value interface{}
+	dec = json.NewDecoder(bytes.NewReader(key.Value))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return "", fmt.Errorf("invalid sensitive attribute path: %w", err)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v), nil
+	case json.Number:
+		i, err := v.Int64()
+		if err != nil {
+			return "", fmt.Errorf("invalid sensitive attribute path: %w", err)
+		}
+		return strconv.FormatInt(i, 10), nil
+	}
+
+	return "", errors.New("invalid sensitive attribute path: index must be a string or number")
+}
+
+// checkKinds maps the object kinds of the raw state format to those of
+// "terraform show -json".
+var checkKinds = map[string]tfjson.CheckKind{
+	"resource": tfjson.CheckKindResource,
+	"output":   tfjson.CheckKindOutputValue,
+	"check":    tfjson.CheckKindCheckBlock,
+}
+
+func (cr *CheckResults) toJSON(useJSONNumber bool) (tfjson.CheckResultStatic, error) {
+	kind, ok := checkKinds[cr.ObjectKind]
+	if !ok {
+		kind = tfjson.CheckKind(cr.ObjectKind)
+	}
+
+	address, err := checkStaticAddress(kind, cr.ConfigAddr)
+	if err != nil {
+		return tfjson.CheckResultStatic{}, err
+	}
+
+	result := tfjson.CheckResultStatic{
+		Address: address,
+		Status:  tfjson.CheckStatus(cr.Status),
+	}
+	for _, obj := range cr.Objects {
+		if obj == nil {
+			continue
+		}
+
+		instance, err := checkDynamicAddress(kind, obj.ObjectAddr, useJSONNumber)
+		if err != nil {
+			return tfjson.CheckResultStatic{}, err
+		}
+
+		dynamic := tfjson.CheckResultDynamic{
+			Address: instance,
+			Status:  tfjson.CheckStatus(obj.Status),
+		}
+		for _, msg := range obj.FailureMessages {
+			dynamic.Problems = append(dynamic.Problems, tfjson.CheckResultProblem{Message: msg})
+		}
+		result.Instances = append(result.Instances, dynamic)
+	}
+
+	return result, nil
+}
+
+func checkStaticAddress(kind tfjson.CheckKind, configAddr string) (tfjson.CheckStaticAddress, error) {
+	module, rest, err := splitModule(configAddr)
+	if err != nil {
+		return tfjson.CheckStaticAddress{}, fmt.Errorf("check %q: %w", configAddr, err)
+	}
+
+	ref, err := tfjson.ParseReference(rest)
+	if err != nil {
+		return tfjson.CheckStaticAddress{}, fmt.Errorf("check %q: %w", configAddr, err)
+	}
+
+	result := tfjson.CheckStaticAddress{
+		ToDisplay: configAddr,
+		Kind:      kind,
+		Module:    module,
+	}
+
+	switch {
+	case ref.Key != nil || ref.Remaining != "":
+		return tfjson.CheckStaticAddress{}, fmt.Errorf("check %q: invalid %s address", configAddr, kind)
+	case kind == tfjson.CheckKindResource && ref.Kind == tfjson.ReferenceKindDataSource:
+		result.Mode = tfjson.DataResourceMode
+		result.Type = ref.Type
+		result.Name = ref.Name
+	case kind == tfjson.CheckKindResource && ref.Kind == tfjson.ReferenceKindResource:
+		result.Mode = tfjson.ManagedResourceMode
+		result.Type = ref.Type
+		result.Name = ref.Name
+	case kind != tfjson.CheckKindResource && (ref.Kind == tfjson.ReferenceKindResource || ref.Kind == tfjson.ReferenceKindVariable):
+		// Outputs and checks are parsed as resources of type "output"
+		// and "check".
+		result.Name = ref.Name
+	default:
+		return tfjson.CheckStaticAddress{}, fmt.Errorf("check %q: invalid %s address", configAddr, kind)
+	}
+
+	return result, nil
+}
+
+func checkDynamicAddress(kind tfjson.CheckKind, objectAddr string, useJSONNumber bool) (tfjson.CheckDynamicAddress, error) {
+	module, rest, err := splitModule(objectAddr)
+	if err != nil {
+		return tfjson.CheckDynamicAddress{}, fmt.Errorf("check %q: %w", objectAddr, err)
+	}
+
+	result := tfjson.CheckDynamicAddress{
+		ToDisplay: objectAddr,
+		Module:    module,
+	}
+	if kind == tfjson.CheckKindResource {
+		ref, err := tfjson.ParseReference(rest)
+		if err != nil {
+			return tfjson.CheckDynamicAddress{}, fmt.Errorf("check %q: %w", objectAddr, err)
+		}
+		result.InstanceKey = ref.Key
+	}
+
+	// Decoding "terraform show -json" produces numeric keys as either
+	// json.Numbers or float64s, so match that here.
+	if i, ok := result.InstanceKey.(int); ok {
+		if useJSONNumber {
+			result.InstanceKey = json.Number(strconv.Itoa(i))
+		} else {
+			result.InstanceKey = float64(i)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,104 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfstate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/sebdah/goldie"
+)
+
+func init() {
+	goldie.FixtureDir = testDataDir
+}
+
+func TestState_ToJSONState(t *testing.T) {
+	state := testParseFile(t, "basic.tfstate")
+
+	got, err := state.ToJSONState()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := got.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	goldie.AssertJson(t, "basic", got)
+}
+
+func TestState_ToJSONState_roundTrip(t *testing.T) {
+	state := testParseFile(t, "basic.tfstate")
+
+	converted, err := state.ToJSONState()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(converted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The result must be accepted as "terraform show -json" output.
+	var decoded tfjson.State
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	var modules []string
+	decoded.WalkModules(func(m *tfjson.StateModule) bool {
+		modules = append(modules, m.Address)
+		return true
+	})
+	expected := []string{"", `module.foo["a.b"]`, `module.foo["a.b"].module.bar[0]`}
+	if diff := cmp.Diff(expected, modules); diff != "" {
+		t.Fatalf("unexpected modules (-want +got):\n%s", diff)
+	}
+}
+
+func TestState_ToJSONState_errors(t *testing.T) {
+	cases := map[string]struct {
+		input    string
+		expected string
+	}{
+		"flatmap": {
+			input:    `{"version": 4, "resources": [{"mode": "managed", "type": "aws_instance", "name": "a", "provider": "provider.aws", "instances": [{"attributes_flat": {"id": "i-1"}}]}]}`,
+			expected: "aws_instance.a: legacy flatmap attributes are not supported",
+		},
+		"provider": {
+			input:    `{"version": 4, "resources": [{"mode": "managed", "type": "aws_instance", "name": "a", "provider": "aws", "instances": []}]}`,
+			expected: `invalid provider address "aws"`,
+		},
+		"module": {
+			input:    `{"version": 4, "resources": [{"module": "foo", "mode": "managed", "type": "aws_instance", "name": "a", "provider": "provider.aws", "instances": []}]}`,
+			expected: `invalid module address "foo"`,
+		},
+		"sensitive path": {
+			input:    `{"version": 4, "resources": [{"mode": "managed", "type": "aws_instance", "name": "a", "provider": "provider.aws", "instances": [{"attributes": {}, "sensitive_attributes": [[{"type": "splat"}]]}]}]}`,
+			expected: `unsupported step type "splat"`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			state, err := Parse(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = state.ToJSONState()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tc.expected) {
+				t.Fatalf("expected error containing %q, got %q", tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package tfstate models the raw state snapshot format written by
+// Terraform to terraform.tfstate files and remote state backends, and
+// converts it into the representation produced by "terraform show -json".
+//
+// Only version 4 of the format, used by Terraform 0.12 and later, is
+// supported.
+package tfstate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// SupportedVersion is the version of the raw state format supported by
+// this package.
+const SupportedVersion = 4
+
+// State is the top-level representation of a raw state snapshot.
+type State struct {
+	// decodeOptions are the options used when decoding the state. Set them
+	// using State.SetDecodeOptions, or using State.UseJSONNumber.
+	decodeOptions tfjson.DecodeOptions
+
+	// unknownFields are the paths of the unknown fields found when decoding
+	// with DecodeOptions.CollectUnknownFields set, returned by
+	// State.UnknownFields.
+	unknownFields []string
+
+	// Version is the version of the state format.
+	Version uint64 `json:"version"`
+
+	// TerraformVersion is the version of Terraform that wrote the
+	// snapshot.
+	TerraformVersion string `json:"terraform_version"`
+
+	// Serial is incremented each time the state is updated.
+	Serial uint64 `json:"serial"`
+
+	// Lineage is a unique identifier assigned when the state is first
+	// created, shared by all subsequent snapshots of the same state.
+	Lineage string `json:"lineage"`
+
+	// Outputs are the root module output values, keyed by name.
+	Outputs map[string]*Output `json:"outputs"`
+
+	// Resources are all of the resources in the state, in every module.
+	Resources []*Resource `json:"resources"`
+
+	// CheckResults are the results of any checks, as of the last time the
+	// state was updated.
+	CheckResults []*CheckResults `json:"check_results,omitempty"`
+}
+
+// ErrPreserveUnknownFields is returned when decoding a State with
+// DecodeOptions.PreserveUnknownFields set, which is not supported for raw
+// state snapshots.
+var ErrPreserveUnknownFields = errors.New("preserving unknown fields is not supported for raw state snapshots")
+
+// SetDecodeOptions sets the options used when decoding the State,
+// replacing any set previously. DecodeOptions.PreserveUnknownFields is not
+// supported, and decoding fails with ErrPreserveUnknownFields if it is set.
+func (s *State) SetDecodeOptions(opts tfjson.DecodeOptions) {
+	s.decodeOptions = opts
+}
+
+// UseJSONNumber controls whether the State will be decoded using the
+// json.Number behavior or the float64 behavior. When b is true, the State
+// will represent numbers in attribute and output values as json.Numbers.
+// When b is false, they will be represented as float64s.
+func (s *State) UseJSONNumber(b bool) {
+	s.decodeOptions.UseJSONNumber = b
+}
+
+// UnknownFields returns the paths of the JSON object members which were
+// found when decoding the State but have no corresponding field in this
+// package. It is only populated if DecodeOptions.CollectUnknownFields was
+// set prior to decoding.
+func (s *State) UnknownFields() []string {
+	return s.unknownFields
+}
+
+// Validate checks to ensure that the state is present, and the version
+// matches the version supported by this package.
+func (s *State) Validate() error {
+	if s == nil {
+		return errors.New("state is nil")
+	}
+
+	if s.Version == 0 {
+		return errors.New("unexpected state input, version is missing")
+	}
+
+	if s.Version != SupportedVersion {
+		return fmt.Errorf("unsupported state version: %d (only version %d is supported)", s.Version, SupportedVersion)
+	}
+
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for State.
+//
+// As per established convention this method should only ever
+// be invoked *indirectly* via [encoding/json] library.
+func (s *State) UnmarshalJSON(b []byte) error {
+	type rawState State
+	var state rawState
+
+	opts := s.decodeOptions
+	if opts.PreserveUnknownFields {
+		return ErrPreserveUnknownFields
+	}
+
+	unknownFields, err := opts.Unmarshal(b, &state)
+	if err != nil {
+		return err
+	}
+
+	*s = *(*State)(&state)
+	s.decodeOptions = opts
+	s.unknownFields = unknownFields
+
+	if opts.SkipValidate {
+		return nil
+	}
+
+	return s.Validate()
+}
+
+// Decode reads a raw state snapshot from r according to opts, which are
+// used as described by State.SetDecodeOptions.
+func Decode(r io.Reader, opts tfjson.DecodeOptions) (*State, error) {
+	state := &State{decodeOptions: opts}
+	if err := opts.Decode(r, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// Parse reads and validates a raw state snapshot from r. Numbers are
+// decoded as json.Numbers, so that no precision is lost.
+func Parse(r io.Reader) (*State, error) {
+	return Decode(r, tfjson.DecodeOptions{UseJSONNumber: true})
+}
+
+// Output is a root module output value.
+type Output struct {
+	// Value is the output value.
+	Value interface{} `json:"value"`
+
+	// Type is the JSON representation of the cty type of Value.
+	Type json.RawMessage `json:"type"`
+
+	// Sensitive is true if the output was declared as sensitive.
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// EachMode is the repetition mode of a resource.
+type EachMode string
+
+const (
+	// EachNone indicates a resource with neither count nor for_each.
+	// Terraform omits the each property in this case.
+	EachNone EachMode = ""
+
+	// EachList indicates a resource using count.
+	EachList EachMode = "list"
+
+	// EachMap indicates a resource using for_each.
+	EachMap EachMode = "map"
+)
+
+// Resource is a resource declared in configuration, along with all of its
+// instances.
+type Resource struct {
+	// Module is the address of the module instance containing the
+	// resource, omitted for the root module.
+	Module string `json:"module,omitempty"`
+
+	// Mode is the resource mode: "managed" or "data".
+	Mode string `json:"mode"`
+
+	// Type is the resource type, such as "aws_instance".
+	Type string `json:"type"`
+
+	// Name is the resource name.
+	Name string `json:"name"`
+
+	// Each is the repetition mode of the resource.
+	Each EachMode `json:"each,omitempty"`
+
+	// Provider is the absolute address of the provider configuration
+	// that manages the resource, for example
+	// `provider["registry.terraform.io/hashicorp/aws"].west`.
+	Provider string `json:"provider"`
+
+	// Instances are the current and deposed objects of each instance of
+	// the resource.
+	Instances []*Instance `json:"instances"`
+}
+
+// InstanceStatus is the status of a resource instance object.
+type InstanceStatus string
+
+const (
+	// InstanceStatusReady is the status of an object which is ready for
+	// use. Terraform omits the status property in this case.
+	InstanceStatusReady InstanceStatus = ""
+
+	// InstanceStatusTainted is the status of an object which must be
+	// replaced, typically because its creation did not complete.
+	InstanceStatusTainted InstanceStatus = "tainted"
+)
+
+// Instance is a single object of a resource instance, which is either its
+// current object or one that has been deposed.
+type Instance struct {
+	// IndexKey is the instance key: an integer for resources using
+	// count, a string for resources using for_each, and absent
+	// otherwise.
+	IndexKey interface{} `json:"index_key,omitempty"`
+
+	// Status is the status of the object.
+	Status InstanceStatus `json:"status,omitempty"`
+
+	// Deposed is the deposed key, if this is a deposed object.
+	Deposed string `json:"deposed,omitempty"`
+
+	// SchemaVersion is the version of the resource type schema that
+	// Attributes conforms to.
+	SchemaVersion uint64 `json:"schema_version"`
+
+	// Attributes are the attribute values of the object.
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+
+	// AttributesFlat are the attribute values of an object written by
+	// Terraform 0.11 and earlier, in the legacy flatmap format.
+	AttributesFlat map[string]string `json:"attributes_flat,omitempty"`
+
+	// SensitiveAttributes are the paths of the attributes which are
+	// sensitive.
+	SensitiveAttributes []Path `json:"sensitive_attributes,omitempty"`
+
+	// IdentitySchemaVersion is the version of the resource identity
+	// schema that Identity conforms to.
+	IdentitySchemaVersion *uint64 `json:"identity_schema_version,omitempty"`
+
+	// Identity is the resource identity of the object.
+	Identity map[string]interface{} `json:"identity,omitempty"`
+
+	// Private is opaque data stored by the provider, base64 encoded.
+	Private string `json:"private,omitempty"`
+
+	// Dependencies are the addresses of the resources the object
+	// depends on.
+	Dependencies []string `json:"dependencies,omitempty"`
+
+	// CreateBeforeDestroy is true if the object must be replaced by
+	// creating its replacement first.
+	CreateBeforeDestroy bool `json:"create_before_destroy,omitempty"`
+}
+
+// Path is a path to a value within a resource instance object.
+type Path []PathStep
+
+// PathStep is a single step of a Path.
+type PathStep struct {
+	// Type is "get_attr" for an attribute access, or "index" for an
+	// element access.
+	Type string `json:"type"`
+
+	// Value is the attribute name for "get_attr" steps. For "index"
+	// steps it is the JSON representation of the key as a cty value,
+	// which is an object with "value" and "type" properties.
+	Value json.RawMessage `json:"value"`
+}
+
+// CheckResults are the results of the checks for a single checkable
+// object in configuration, such as a resource with preconditions.
+type CheckResults struct {
+	// ObjectKind is the kind of checkable object: "resource", "output",
+	// "check" or "var".
+	ObjectKind string `json:"object_kind"`
+
+	// ConfigAddr is the address of the object in configuration.
+	ConfigAddr string `json:"config_addr"`
+
+	// Status is the aggregate status of the checks for all instances.
+	Status string `json:"status"`
+
+	// Objects are the results for each instance of the object.
+	Objects []*CheckResultsObject `json:"objects,omitempty"`
+}
+
+// CheckResultsObject is the result of the checks for a single instance of
+// a checkable object.
+type CheckResultsObject struct {
+	// ObjectAddr is the absolute address of the instance.
+	ObjectAddr string `json:"object_addr"`
+
+	// Status is the status of the checks for the instance.
+	Status string `json:"status"`
+
+	// FailureMessages are the error messages of any failed checks.
+	FailureMessages []string `json:"failure_messages,omitempty"`
+}
@@ -0,0 +1,169 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfstate
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+const testDataDir = "testdata"
+
+func testParseFile(t *testing.T, name string) *State {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join(testDataDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	state, err := Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+func TestParse(t *testing.T) {
+	state := testParseFile(t, "basic.tfstate")
+
+	if state.Serial != 12 {
+		t.Errorf("expected serial 12, got %d", state.Serial)
+	}
+	if state.Lineage != "3a1b4a5e-0c1d-9f0e-8b5c-51f2a4d1c0e7" {
+		t.Errorf("unexpected lineage %q", state.Lineage)
+	}
+	if len(state.Resources) != 5 {
+		t.Fatalf("expected 5 resources, got %d", len(state.Resources))
+	}
+
+	bar := state.Resources[1]
+	if len(bar.Instances) != 2 {
+		t.Fatalf("expected 2 instances of %s.%s, got %d", bar.Type, bar.Name, len(bar.Instances))
+	}
+	if got := bar.Instances[0].Private; got != "bnVsbA==" {
+		t.Errorf("unexpected private data %q", got)
+	}
+	if !bar.Instances[0].CreateBeforeDestroy {
+		t.Error("expected create_before_destroy to be set")
+	}
+	if got := bar.Instances[1]; got.Deposed != "00000001" || got.Status != InstanceStatusTainted {
+		t.Errorf("unexpected deposed object %#v", got)
+	}
+
+	foo := state.Resources[2]
+	if foo.Each != EachList {
+		t.Errorf("expected each %q, got %q", EachList, foo.Each)
+	}
+	if key, ok := foo.Instances[0].IndexKey.(json.Number); !ok || key != "0" {
+		t.Errorf("expected json.Number index key, got %#v", foo.Instances[0].IndexKey)
+	}
+
+	if len(state.CheckResults) != 3 {
+		t.Fatalf("expected 3 check results, got %d", len(state.CheckResults))
+	}
+	if got := state.CheckResults[0].Objects[0].FailureMessages; len(got) != 1 {
+		t.Errorf("expected 1 failure message, got %q", got)
+	}
+}
+
+func TestState_UnmarshalJSON_float64(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(testDataDir, "basic.tfstate"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var state State
+	if err := json.Unmarshal(b, &state); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := state.Resources[2].Instances[0].IndexKey.(float64); !ok {
+		t.Errorf("expected float64 index key, got %#v", state.Resources[2].Instances[0].IndexKey)
+	}
+}
+
+func TestState_Validate(t *testing.T) {
+	cases := map[string]string{
+		`{"serial": 1}`:  "version is missing",
+		`{"version": 3}`: "unsupported state version: 3",
+		`{"version": 5}`: "unsupported state version: 5",
+	}
+
+	for input, expected := range cases {
+		_, err := Parse(strings.NewReader(input))
+		if err == nil {
+			t.Errorf("%s: expected error", input)
+			continue
+		}
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: expected error containing %q, got %q", input, expected, err)
+		}
+	}
+
+	var state *State
+	if err := state.Validate(); err == nil {
+		t.Error("expected error for nil state")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	const input = `{"version": 5, "serial": 1, "new_field": true, "resources": [{"instances": [{"new_field": 1}]}]}`
+
+	if _, err := Decode(strings.NewReader(input), tfjson.DecodeOptions{MaxSize: 10}); !errors.Is(err, tfjson.ErrDocumentTooLarge) {
+		t.Fatalf("expected ErrDocumentTooLarge, got %v", err)
+	}
+
+	expectedFields := []string{"new_field", "resources[0].instances[0].new_field"}
+
+	_, err := Decode(strings.NewReader(input), tfjson.DecodeOptions{DisallowUnknownFields: true, SkipValidate: true})
+	var unknownErr *tfjson.UnknownFieldsError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("expected *tfjson.UnknownFieldsError, got %v", err)
+	}
+	if diff := cmp.Diff(expectedFields, unknownErr.Fields); diff != "" {
+		t.Fatalf("unexpected unknown fields: %s", diff)
+	}
+
+	state, err := Decode(strings.NewReader(input), tfjson.DecodeOptions{CollectUnknownFields: true, SkipValidate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expectedFields, state.UnknownFields()); diff != "" {
+		t.Fatalf("unexpected unknown fields: %s", diff)
+	}
+
+	if _, err := Decode(strings.NewReader(input), tfjson.DecodeOptions{PreserveUnknownFields: true, SkipValidate: true}); !errors.Is(err, ErrPreserveUnknownFields) {
+		t.Fatalf("expected ErrPreserveUnknownFields, got %v", err)
+	}
+
+	if _, err := Decode(strings.NewReader(input), tfjson.DecodeOptions{}); err == nil {
+		t.Fatal("expected unsupported version error")
+	}
+
+	state, err = Decode(strings.NewReader(input), tfjson.DecodeOptions{SkipValidate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Version != 5 || state.Serial != 1 {
+		t.Fatalf("unexpected state %#v", state)
+	}
+
+	state, err = Decode(strings.NewReader(`{"version": 4, "outputs": {"n": {"value": 12345678901234567890}}}`), tfjson.DecodeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state.Outputs["n"].Value.(float64); !ok {
+		t.Fatalf("expected float64, got %#v", state.Outputs["n"].Value)
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	tfjsonPkgPath = reflect.TypeOf(Plan{}).PkgPath()
+
+	unknownFieldNameRe = regexp.MustCompile(`^[A-Za-z_@][A-Za-z0-9_@-]*$`)
+)
+
+// jsonPathStepKind is the kind of a jsonPathStep.
+type jsonPathStepKind int
+
+const (
+	// jsonPathField is a member of an object decoded into a struct.
+	jsonPathField jsonPathStepKind = iota
+
+	// jsonPathKey is a member of an object decoded into a map.
+	jsonPathKey
+
+	// jsonPathIndex is an element of an array.
+	jsonPathIndex
+)
+
+// jsonPathStep is a step in the path to a value within a JSON document.
+type jsonPathStep struct {
+	kind jsonPathStepKind
+
+	// name is the name of the member, for fields and keys.
+	name string
+
+	// index is the index of the element, for array elements.
+	index int
+}
+
+// unknownMembers holds the members of the JSON object a struct was decoded
+// from which have no corresponding field, when decoding with
+// DecodeOptions.PreserveUnknownFields. It is embedded in each struct type
+// representing an object within a document, whose MarshalJSON method emits
+// the members again after its fields, so that the members remain with the
+// object they were decoded from however the document is modified.
+type unknownMembers struct {
+	// members are never modified once decoded, and so may be shared
+	// between copies of a struct.
+	members []*jsonMember
+}
+
+func (u *unknownMembers) setUnknownMembers(members []*jsonMember) {
+	u.members = members
+}
+
+func (u *unknownMembers) jsonUnknownMembers() []*jsonMember {
+	return u.members
+}
+
+// unknownMembersHolder is implemented by pointers to the struct types which
+// embed unknownMembers.
+type unknownMembersHolder interface {
+	setUnknownMembers(members []*jsonMember)
+	jsonUnknownMembers() []*jsonMember
+}
+
+// decodeUnknownFields returns the paths of the members of the objects
+// within the JSON document b which have no corresponding field in typ or
+// in the types nested within it, such as
+// "resource_changes[0].change.new_field", in the order they appear within
+// the document. Members of the top-level object named in ignore are not
+// included.
+//
+// Values decoded into interface{} fields, and into types outside of this
+// package which implement json.Unmarshaler, are not inspected.
+func decodeUnknownFields(b []byte, typ reflect.Type, ignore ...string) ([]string, error) {
+	paths, err := DecodeOptions{CollectUnknownFields: true}.Unmarshal(b, reflect.New(typ).Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, path := range paths {
+		if !containsString(ignore, path) {
+			result = append(result, path)
+		}
+	}
+
+	return result, nil
+}
+
+// marshalUnknownMembers marshals v, a pointer to a struct which embeds
+// unknownMembers, emitting the members it holds after its fields.
+func marshalUnknownMembers(v unknownMembersHolder) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || reflect.ValueOf(v).IsNil() {
+		return b, err
+	}
+
+	return appendUnknownMembers(b, v.jsonUnknownMembers())
+}
+
+// appendUnknownMembers adds members to the end of the encoded JSON object
+// b. They never have the same names as the existing members, which
+// correspond to fields.
+func appendUnknownMembers(b []byte, members []*jsonMember) ([]byte, error) {
+	if len(members) == 0 || len(b) < 2 || b[len(b)-1] != '}' {
+		return b, nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(b[:len(b)-1])
+	for _, m := range members {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(m.name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		if err := m.value.encode(&buf); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+var jsonFieldsCache sync.Map
+
+// jsonFields returns the indexes of the fields of the struct type typ,
+// keyed by the names encoding/json uses for them, including those
+// promoted from embedded structs.
+func jsonFields(typ reflect.Type) map[string][]int {
+	if fields, ok := jsonFieldsCache.Load(typ); ok {
+		return fields.(map[string][]int)
+	}
+
+	fields := make(map[string][]int)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, index := range jsonFields(ft) {
+					if _, ok := fields[k]; !ok {
+						fields[k] = append([]int{i}, index...)
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = []int{i}
+	}
+
+	jsonFieldsCache.Store(typ, fields)
+
+	return fields
+}
+
+// jsonFieldIndex returns the index of the field of the struct type typ
+// which encoding/json decodes the object member name into, matching
+// case-insensitively when there is no exact match as encoding/json does.
+func jsonFieldIndex(typ reflect.Type, name string) ([]int, bool) {
+	fields := jsonFields(typ)
+	if index, ok := fields[name]; ok {
+		return index, true
+	}
+
+	for k, index := range fields {
+		if strings.EqualFold(k, name) {
+			return index, true
+		}
+	}
+
+	return nil, false
+}
+
+func appendJSONPath(path []jsonPathStep, step jsonPathStep) []jsonPathStep {
+	result := make([]jsonPathStep, len(path), len(path)+1)
+	copy(result, path)
+
+	return append(result, step)
+}
+
+// formatJSONPath formats path as in "values.outputs["id"].type", with
+// map keys, and fields which are not identifiers, in brackets.
+func formatJSONPath(path []jsonPathStep) string {
+	var b strings.Builder
+	for _, step := range path {
+		switch {
+		case step.kind == jsonPathIndex:
+			b.WriteString("[" + strconv.Itoa(step.index) + "]")
+		case step.kind == jsonPathKey || !unknownFieldNameRe.MatchString(step.name):
+			b.WriteString("[" + strconv.Quote(step.name) + "]")
+		default:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(step.name)
+		}
+	}
+
+	return b.String()
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,496 @@
+// Copyright IBM Corp. 2019, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package tfjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testCollectUnknownFields is implemented by the document types which
+// support collecting unknown fields.
+type testCollectUnknownFields interface {
+	CollectUnknownFields(b bool)
+	UnknownFields() []string
+}
+
+func TestUnknownFields_fixtures(t *testing.T) {
+	documents := map[string]func() testCollectUnknownFields{
+		testGoldenPlanFileName:    func() testCollectUnknownFields { return &Plan{} },
+		testGoldenStateFileName:   func() testCollectUnknownFields { return &State{} },
+		testGoldenSchemasFileName: func() testCollectUnknownFields { return &ProviderSchemas{} },
+		"functions.json":          func() testCollectUnknownFields { return &MetadataFunctions{} },
+	}
+
+	for filename, newDocument := range documents {
+		paths, err := filepath.Glob(filepath.Join(testFixtureDir, "*", filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, path := range paths {
+			if filepath.Base(filepath.Dir(path)) == testInvalidDir {
+				continue
+			}
+
+			t.Run(path, func(t *testing.T) {
+				b, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				doc := newDocument()
+				doc.CollectUnknownFields(true)
+				if err := json.Unmarshal(b, doc); err != nil {
+					t.Fatal(err)
+				}
+
+				if fields := doc.UnknownFields(); len(fields) > 0 {
+					t.Fatalf("unexpected unknown fields: %q", fields)
+				}
+			})
+		}
+	}
+}
+
+func TestPlan_unknownFields(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(testFixtureDir, "basic", testGoldenPlanFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	raw["new_section"] = map[string]interface{}{"a": 1}
+	change := raw["resource_changes"].([]interface{})[0].(map[string]interface{})["change"].(map[string]interface{})
+	change["new_field"] = true
+	config := raw["configuration"].(map[string]interface{})
+	config["provider_config"].(map[string]interface{})["aws.east"].(map[string]interface{})["new_field"] = "x"
+	resource := config["root_module"].(map[string]interface{})["resources"].([]interface{})[0].(map[string]interface{})
+	resource["expressions"].(map[string]interface{})["triggers"].(map[string]interface{})["new_field"] = 1
+	resource["expressions"].(map[string]interface{})["block"] = []interface{}{
+		map[string]interface{}{
+			"size": map[string]interface{}{"constant_value": 1, "new field": 2},
+		},
+	}
+
+	b, err = json.Marshal(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var plan Plan
+	if err := json.Unmarshal(b, &plan); err != nil {
+		t.Fatal(err)
+	}
+	if fields := plan.UnknownFields(); fields != nil {
+		t.Fatalf("expected no unknown fields to be collected, got %q", fields)
+	}
+
+	plan = Plan{}
+	plan.CollectUnknownFields(true)
+	if err := json.Unmarshal(b, &plan); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`configuration.provider_config["aws.east"].new_field`,
+		`configuration.root_module.resources[0].expressions["block"][0]["size"]["new field"]`,
+		`configuration.root_module.resources[0].expressions["triggers"].new_field`,
+		`new_section`,
+		`resource_changes[0].change.new_field`,
+	}
+	if diff := cmp.Diff(expected, plan.UnknownFields()); diff != "" {
+		t.Fatalf("unexpected unknown fields: %s", diff)
+	}
+}
+
+func TestUnknownFields_documents(t *testing.T) {
+	testCases := map[string]struct {
+		document testCollectUnknownFields
+		raw      string
+		expected []string
+	}{
+		"state": {
+			&State{},
+			`{"format_version":"1.0","values":{"root_module":{"resources":[{"address":"null_resource.foo","values":{"id":"1"},"new_field":1}]}}}`,
+			[]string{"values.root_module.resources[0].new_field"},
+		},
+		"provider schemas": {
+			&ProviderSchemas{},
+			`{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/null":{"resource_schemas":{"null_resource":{"version":0,"block":{"attributes":{"id":{"type":"string","computed":true,"new_field":true}}}}}}}}`,
+			[]string{`provider_schemas["registry.terraform.io/hashicorp/null"].resource_schemas["null_resource"].block.attributes["id"].new_field`},
+		},
+		"validate output": {
+			&ValidateOutput{},
+			`{"format_version":"1.0","valid":false,"error_count":1,"warning_count":0,"diagnostics":[{"severity":"error","summary":"bad","new_field":{}}]}`,
+			[]string{"diagnostics[0].new_field"},
+		},
+		"metadata functions": {
+			&MetadataFunctions{},
+			`{"format_version":"1.0","function_signatures":{"abs":{"return_type":"number","parameters":[{"name":"num","type":"number","new_field":"x"}]}}}`,
+			[]string{`function_signatures["abs"].parameters[0].new_field`},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tc.document.CollectUnknownFields(true)
+			if err := json.Unmarshal([]byte(tc.raw), tc.document); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, tc.document.UnknownFields()); diff != "" {
+				t.Fatalf("unexpected unknown fields: %s", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalLogMessageWithUnknownFields(t *testing.T) {
+	testCases := map[string]struct {
+		raw      string
+		expected []string
+	}{
+		"known": {
+			`{"@level":"info","@message":"Terraform 1.9.0","@module":"terraform.ui","@timestamp":"2025-08-11T15:09:15.919212+00:00","terraform":"1.9.0","type":"version","ui":"1.2"}`,
+			nil,
+		},
+		"nested": {
+			`{"@level":"error","@message":"Error: bad","@module":"terraform.ui","@timestamp":"2025-08-13T10:40:46.749685+00:00","diagnostic":{"severity":"error","summary":"bad","new_field":1},"type":"diagnostic"}`,
+			[]string{"diagnostic.new_field"},
+		},
+		"unknown type": {
+			`{"@level":"info","@message":"Apply complete!","@module":"terraform.ui","@timestamp":"2025-08-11T15:09:15.919212+00:00","changes":{"add":1},"type":"change_summary"}`,
+			[]string{"changes"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, fields, err := UnmarshalLogMessageWithUnknownFields([]byte(tc.raw))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expected, fields); diff != "" {
+				t.Fatalf("unexpected unknown fields: %s", diff)
+			}
+		})
+	}
+}
+
+func TestPlan_preserveUnknownFields(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(testFixtureDir, "basic", testGoldenPlanFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = bytes.TrimSpace(b)
+
+	root, err := testParseJSONNode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testAddMember(t, root, `{"a":[1,2.50,{"b":null}]}`, "new_section")
+	testAddMember(t, root.member("resource_changes").elem(0).member("change"), `true`, "new_field")
+	testAddMember(t, root.member("configuration").member("provider_config").member("aws.east"), `"x"`, "new field")
+	testAddMember(t, root.member("prior_state").member("values"), `12345678901234567890`, "new_field")
+
+	withUnknown, err := root.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("preserved", func(t *testing.T) {
+		var plan Plan
+		plan.PreserveUnknownFields(true)
+		if err := json.Unmarshal(withUnknown, &plan); err != nil {
+			t.Fatal(err)
+		}
+
+		actual, err := json.Marshal(plan.DeepCopy())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff(string(withUnknown), string(actual)); diff != "" {
+			t.Fatalf("unexpected difference: %s", diff)
+		}
+	})
+
+	t.Run("discarded", func(t *testing.T) {
+		var plan Plan
+		if err := json.Unmarshal(withUnknown, &plan); err != nil {
+			t.Fatal(err)
+		}
+
+		actual, err := json.Marshal(&plan)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff(string(b), string(actual)); diff != "" {
+			t.Fatalf("unexpected difference: %s", diff)
+		}
+	})
+
+	t.Run("parent removed", func(t *testing.T) {
+		var plan Plan
+		plan.PreserveUnknownFields(true)
+		if err := json.Unmarshal(withUnknown, &plan); err != nil {
+			t.Fatal(err)
+		}
+		plan.PriorState = nil
+		plan.ResourceChanges = nil
+
+		actual, err := json.Marshal(&plan)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var raw map[string]interface{}
+		if err := json.Unmarshal(actual, &raw); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := raw["new_section"]; !ok {
+			t.Fatal("expected new_section to be preserved")
+		}
+		if _, ok := raw["prior_state"]; ok {
+			t.Fatal("expected prior_state to be omitted")
+		}
+		if _, ok := raw["resource_changes"]; ok {
+			t.Fatal("expected resource_changes to be omitted")
+		}
+	})
+
+	t.Run("elements removed and reordered", func(t *testing.T) {
+		root, err := testParseJSONNode(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, rc := range root.member("resource_changes").elems {
+			testAddMember(t, rc, string(rc.member("address").raw), "new_field")
+		}
+		for _, r := range root.member("configuration").member("root_module").member("resources").elems {
+			testAddMember(t, r, string(r.member("address").raw), "new_field")
+		}
+		withUnknown, err := root.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var plan Plan
+		plan.PreserveUnknownFields(true)
+		if err := json.Unmarshal(withUnknown, &plan); err != nil {
+			t.Fatal(err)
+		}
+
+		// Remove the first element of each array and reverse the rest.
+		changes := plan.ResourceChanges[1:]
+		resources := plan.Config.RootModule.Resources[1:]
+		plan.ResourceChanges = nil
+		plan.Config.RootModule.Resources = nil
+		for i := len(changes) - 1; i >= 0; i-- {
+			plan.ResourceChanges = append(plan.ResourceChanges, changes[i])
+		}
+		for i := len(resources) - 1; i >= 0; i-- {
+			plan.Config.RootModule.Resources = append(plan.Config.RootModule.Resources, resources[i])
+		}
+
+		actual, err := json.Marshal(&plan)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var raw struct {
+			ResourceChanges []map[string]interface{} `json:"resource_changes"`
+			Configuration   struct {
+				RootModule struct {
+					Resources []map[string]interface{} `json:"resources"`
+				} `json:"root_module"`
+			} `json:"configuration"`
+		}
+		if err := json.Unmarshal(actual, &raw); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(raw.ResourceChanges) != len(changes) {
+			t.Fatalf("expected %d resource changes, got %d", len(changes), len(raw.ResourceChanges))
+		}
+		for _, rc := range raw.ResourceChanges {
+			if rc["new_field"] != rc["address"] {
+				t.Errorf("expected new_field %q, got %q", rc["address"], rc["new_field"])
+			}
+		}
+		if len(raw.Configuration.RootModule.Resources) != len(resources) {
+			t.Fatalf("expected %d resources, got %d", len(resources), len(raw.Configuration.RootModule.Resources))
+		}
+		for _, r := range raw.Configuration.RootModule.Resources {
+			if r["new_field"] != r["address"] {
+				t.Errorf("expected new_field %q, got %q", r["address"], r["new_field"])
+			}
+		}
+	})
+}
+
+func TestUnknownFields_preserveDocuments(t *testing.T) {
+	testCases := map[string]struct {
+		document interface {
+			PreserveUnknownFields(b bool)
+		}
+		raw string
+	}{
+		"state": {
+			&State{},
+			`{"format_version":"1.0","values":{"root_module":{"resources":[{"address":"null_resource.foo","schema_version":0,"values":{"id":"1"},"new_field":1}]}},"new_field":{"z":1,"a":2}}`,
+		},
+		"provider schemas": {
+			&ProviderSchemas{},
+			`{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/null":{"resource_schemas":{"null_resource":{"version":0,"block":{"attributes":{"id":{"type":"string","computed":true,"new_field":true}}}}}}}}`,
+		},
+		"validate output": {
+			&ValidateOutput{},
+			`{"format_version":"1.0","valid":false,"error_count":1,"warning_count":0,"diagnostics":[{"severity":"error","summary":"bad","new_field":{}}]}`,
+		},
+		"metadata functions": {
+			&MetadataFunctions{},
+			`{"format_version":"1.0","function_signatures":{"abs":{"return_type":"number","parameters":[{"name":"num","type":"number","new_field":"x"}]}}}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tc.document.PreserveUnknownFields(true)
+			if err := json.Unmarshal([]byte(tc.raw), tc.document); err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := json.Marshal(tc.document)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.raw, string(actual)); diff != "" {
+				t.Fatalf("unexpected difference: %s", diff)
+			}
+		})
+	}
+}
+
+// testAddMember adds a member with the given raw JSON value to the end of
+// the object n.
+func testAddMember(t *testing.T, n *jsonNode, raw, name string) {
+	t.Helper()
+
+	if n == nil || n.delim != '{' {
+		t.Fatalf("cannot add %q to a value which is not an object", name)
+	}
+
+	value, err := testParseJSONNode([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.members = append(n.members, &jsonMember{name: name, value: value})
+}
+
+// testParseJSONNode parses the JSON document b, retaining the order of
+// the members of objects so that they can be modified.
+func testParseJSONNode(b []byte) (*jsonNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	n, err := testReadJSONNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+
+	return n, nil
+}
+
+func testReadJSONNode(dec *json.Decoder) (*jsonNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		n := &jsonNode{delim: tok}
+		for dec.More() {
+			if tok == '{' {
+				name, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				value, err := testReadJSONNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.members = append(n.members, &jsonMember{name: name.(string), value: value})
+				continue
+			}
+
+			elem, err := testReadJSONNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.elems = append(n.elems, elem)
+		}
+
+		// Consume the closing delimiter.
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+
+		return n, nil
+	case json.Number:
+		return &jsonNode{raw: []byte(tok)}, nil
+	case nil:
+		return &jsonNode{raw: []byte("null")}, nil
+	default:
+		raw, err := json.Marshal(tok)
+		if err != nil {
+			return nil, err
+		}
+
+		return &jsonNode{raw: raw}, nil
+	}
+}
+
+// member returns the value of the member of the object n with the given
+// name, or nil if there is none or n is not an object.
+func (n *jsonNode) member(name string) *jsonNode {
+	for _, m := range n.members {
+		if m.name == name {
+			return m.value
+		}
+	}
+
+	return nil
+}
+
+// elem returns the element of the array n at index i, or nil if there is
+// none or n is not an array.
+func (n *jsonNode) elem(i int) *jsonNode {
+	if i < 0 || i >= len(n.elems) {
+		return nil
+	}
+
+	return n.elems[i]
+}
@@ -4,7 +4,6 @@
 package tfjson
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -17,18 +16,34 @@ var ValidateFormatVersionConstraints = ">= 0.1, < 2.0"
 
 // Pos represents a position in a config file
 type Pos struct {
+	unknownMembers
+
 	Line   int `json:"line"`
 	Column int `json:"column"`
 	Byte   int `json:"byte"`
 }
 
+// MarshalJSON implements json.Marshaler for Pos.
+func (p *Pos) MarshalJSON() ([]byte, error) {
+	type rawPos Pos
+	return marshalUnknownMembers((*rawPos)(p))
+}
+
 // Range represents a range of bytes between two positions
 type Range struct {
+	unknownMembers
+
 	Filename string `json:"filename"`
 	Start    Pos    `json:"start"`
 	End      Pos    `json:"end"`
 }
 
+// MarshalJSON implements json.Marshaler for Range.
+func (r *Range) MarshalJSON() ([]byte, error) {
+	type rawRange Range
+	return marshalUnknownMembers((*rawRange)(r))
+}
+
 type DiagnosticSeverity string
 
 // These severities map to the tfdiags.Severity values, plus an explicit
@@ -42,6 +57,8 @@ const (
 // Diagnostic represents information to be presented to a user about an
 // error or anomaly in parsing or evaluating configuration
 type Diagnostic struct {
+	unknownMembers
+
 	Severity DiagnosticSeverity `json:"severity,omitempty"`
 
 	Address string `json:"address,omitempty"`
@@ -52,11 +69,19 @@ type Diagnostic struct {
 	Snippet *DiagnosticSnippet `json:"snippet,omitempty"`
 }
 
+// MarshalJSON implements json.Marshaler for Diagnostic.
+func (d *Diagnostic) MarshalJSON() ([]byte, error) {
+	type rawDiagnostic Diagnostic
+	return marshalUnknownMembers((*rawDiagnostic)(d))
+}
+
 // DiagnosticSnippet represents source code information about the diagnostic.
 // It is possible for a diagnostic to have a source (and therefore a range) but
 // no source code can be found. In this case, the range field will be present and
 // the snippet field will not.
 type DiagnosticSnippet struct {
+	unknownMembers
+
 	// Context is derived from HCL's hcled.ContextString output. This gives a
 	// high-level summary of the root context of the diagnostic: for example,
 	// the resource block in which an expression causes an error.
@@ -87,19 +112,49 @@ type DiagnosticSnippet struct {
 	Values []DiagnosticExpressionValue `json:"values"`
 }
 
+// MarshalJSON implements json.Marshaler for DiagnosticSnippet.
+func (s *DiagnosticSnippet) MarshalJSON() ([]byte, error) {
+	type rawDiagnosticSnippet DiagnosticSnippet
+	return marshalUnknownMembers((*rawDiagnosticSnippet)(s))
+}
+
 // DiagnosticExpressionValue represents an HCL traversal string (e.g.
 // "var.foo") and a statement about its value while the expression was
 // evaluated (e.g. "is a string", "will be known only after apply"). These are
 // intended to help the consumer diagnose why an expression caused a diagnostic
 // to be emitted.
 type DiagnosticExpressionValue struct {
+	unknownMembers
+
 	Traversal string `json:"traversal"`
 	Statement string `json:"statement"`
 }
 
+// MarshalJSON implements json.Marshaler for DiagnosticExpressionValue.
+func (v *DiagnosticExpressionValue) MarshalJSON() ([]byte, error) {
+	type rawDiagnosticExpressionValue DiagnosticExpressionValue
+	return marshalUnknownMembers((*rawDiagnosticExpressionValue)(v))
+}
+
 // ValidateOutput represents JSON output from terraform validate
 // (available from 0.12 onwards)
 type ValidateOutput struct {
+	// decodeOptions are the options used when decoding the validation
+	// output. Set them using ValidateOutput.SetDecodeOptions, or
+	// individually using ValidateOutput.UseJSONNumber,
+	// ValidateOutput.CollectUnknownFields and
+	// ValidateOutput.PreserveUnknownFields.
+	decodeOptions DecodeOptions
+
+	// unknownFields are the paths recorded when decoding with
+	// DecodeOptions.CollectUnknownFields set, returned by
+	// ValidateOutput.UnknownFields.
+	unknownFields []string
+
+	// unknownMembers are the members retained when decoding with
+	// DecodeOptions.PreserveUnknownFields set.
+	unknownMembers
+
 	FormatVersion string `json:"format_version"`
 
 	Valid        bool         `json:"valid"`
@@ -108,6 +163,38 @@ type ValidateOutput struct {
 	Diagnostics  []Diagnostic `json:"diagnostics"`
 }
 
+// SetDecodeOptions sets the options used when decoding the ValidateOutput,
+// replacing any set previously.
+func (vo *ValidateOutput) SetDecodeOptions(opts DecodeOptions) {
+	vo.decodeOptions = opts
+}
+
+// UseJSONNumber controls whether the ValidateOutput will be decoded using
+// the json.Number behavior or the float64 behavior for values of arbitrary
+// type.
+func (vo *ValidateOutput) UseJSONNumber(b bool) {
+	vo.decodeOptions.UseJSONNumber = b
+}
+
+// CollectUnknownFields sets DecodeOptions.CollectUnknownFields for
+// decoding the ValidateOutput. The paths found are returned by UnknownFields.
+func (vo *ValidateOutput) CollectUnknownFields(b bool) {
+	vo.decodeOptions.CollectUnknownFields = b
+}
+
+// UnknownFields returns the paths of the unknown fields collected when
+// decoding the ValidateOutput, such as "diagnostics[0].new_field".
+func (vo *ValidateOutput) UnknownFields() []string {
+	return vo.unknownFields
+}
+
+// PreserveUnknownFields sets DecodeOptions.PreserveUnknownFields for
+// decoding the ValidateOutput, so that unknown fields are emitted again when
+// it is encoded.
+func (vo *ValidateOutput) PreserveUnknownFields(b bool) {
+	vo.decodeOptions.PreserveUnknownFields = b
+}
+
 // Validate checks to ensure that data is present, and the
 // version matches the version supported by this library.
 func (vo *ValidateOutput) Validate() error {
@@ -146,12 +233,21 @@ func (vo *ValidateOutput) UnmarshalJSON(b []byte) error {
 	type rawOutput ValidateOutput
 	var schemas rawOutput
 
-	err := json.Unmarshal(b, &schemas)
+	opts := vo.decodeOptions
+	unknownFields, err := opts.Unmarshal(b, &schemas)
 	if err != nil {
 		return err
 	}
 
 	*vo = *(*ValidateOutput)(&schemas)
+	vo.decodeOptions = opts
+	vo.unknownFields = unknownFields
+
+	return opts.validate(vo)
+}
 
-	return vo.Validate()
+// MarshalJSON implements json.Marshaler for ValidateOutput.
+func (vo *ValidateOutput) MarshalJSON() ([]byte, error) {
+	type rawValidateOutput ValidateOutput
+	return marshalUnknownMembers((*rawValidateOutput)(vo))
 }
@@ -38,7 +38,7 @@ func TestValidateOutput_error(t *testing.T) {
 			},
 		},
 	}
-	if diff := cmp.Diff(expected, &parsed); diff != "" {
+	if diff := cmp.Diff(expected, &parsed, testCmpUnexported); diff != "" {
 		t.Fatalf("output mismatch: %s", diff)
 	}
 }
@@ -116,7 +116,7 @@ func TestValidateOutput_basic(t *testing.T) {
 			},
 		},
 	}
-	if diff := cmp.Diff(expected, &parsed); diff != "" {
+	if diff := cmp.Diff(expected, &parsed, testCmpUnexported); diff != "" {
 		t.Fatalf("output mismatch: %s", diff)
 	}
 }
@@ -230,7 +230,7 @@ func TestValidateOutput_versioned(t *testing.T) {
 			},
 		},
 	}
-	if diff := cmp.Diff(expected, &parsed); diff != "" {
+	if diff := cmp.Diff(expected, &parsed, testCmpUnexported); diff != "" {
 		t.Fatalf("output mismatch: %s", diff)
 	}
 }
@@ -344,7 +344,7 @@ func TestValidateOutput_versioned10(t *testing.T) {
 			},
 		},
 	}
-	if diff := cmp.Diff(expected, &parsed); diff != "" {
+	if diff := cmp.Diff(expected, &parsed, testCmpUnexported); diff != "" {
 		t.Fatalf("output mismatch: %s", diff)
 	}
 }
@@ -89,7 +89,7 @@ func TestWorkspaceListOutput_warning(t *testing.T) {
 			},
 		},
 	}
-	if diff := cmp.Diff(expected, &parsed); diff != "" {
+	if diff := cmp.Diff(expected, &parsed, testCmpUnexported); diff != "" {
 		t.Fatalf("output mismatch: %s", diff)
 	}
 }
@@ -118,7 +118,7 @@ func TestWorkspaceListOutput_error(t *testing.T) {
 			},
 		},
 	}
-	if diff := cmp.Diff(expected, &parsed); diff != "" {
+	if diff := cmp.Diff(expected, &parsed, testCmpUnexported); diff != "" {
 		t.Fatalf("output mismatch: %s", diff)
 	}
 }